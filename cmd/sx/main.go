@@ -87,7 +87,18 @@ from remote Sleuth servers or Git vaults.`,
 	rootCmd.AddCommand(commands.NewReportUsageCommand())
 	rootCmd.AddCommand(commands.NewServeCommand())
 	rootCmd.AddCommand(commands.NewConfigCommand())
+	rootCmd.AddCommand(commands.NewDoctorCommand())
+	rootCmd.AddCommand(commands.NewBundleCommand())
+	rootCmd.AddCommand(commands.NewCacheCommand())
 	rootCmd.AddCommand(commands.NewVaultCommand())
+	rootCmd.AddCommand(commands.NewRollbackCommand())
+	rootCmd.AddCommand(commands.NewLockCommand())
+	rootCmd.AddCommand(commands.NewRegistryCommand())
+	rootCmd.AddCommand(commands.NewNewCommand())
+	rootCmd.AddCommand(commands.NewValidateCommand())
+	rootCmd.AddCommand(commands.NewLinkCommand())
+	rootCmd.AddCommand(commands.NewUnlinkCommand())
+	rootCmd.AddCommand(commands.NewMCPCommand())
 
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
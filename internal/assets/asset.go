@@ -22,6 +22,7 @@ type InstallResult struct {
 	Installed []string // Successfully installed assets
 	Failed    []string // Failed assets
 	Errors    []error  // Errors encountered
+	Skipped   []string // Assets not installed because a client config file is malformed
 }
 
 // Scope represents the current working context for scope matching
@@ -44,6 +44,23 @@ func DetectAssetType(files []string, name, version string) *metadata.Metadata {
 	return (&SkillDetector{}).CreateDefaultMetadata(name, version)
 }
 
+// CreateDefaultMetadataForType creates default metadata for an asset type key
+// Returns nil for unknown types.
+func CreateDefaultMetadataForType(typeKey, name, version string) *metadata.Metadata {
+	for _, factory := range detectorRegistry {
+		detector := factory()
+		if detector.GetType() == typeKey {
+			return detector.CreateDefaultMetadata(name, version)
+		}
+	}
+
+	// Remote MCPs carry no files to detect, so they aren't in the registry
+	if remote := (&MCPRemoteDetector{}); remote.GetType() == typeKey {
+		return remote.CreateDefaultMetadata(name, version)
+	}
+	return nil
+}
+
 func init() {
 	// Register all detectors
 	RegisterDetector(func() AssetTypeDetector { return &SkillDetector{} })
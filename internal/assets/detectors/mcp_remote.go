@@ -2,14 +2,44 @@ package detectors
 
 import (
 	"strings"
+
+	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/metadata"
 )
 
 // MCPRemoteDetector detects MCP remote assets
 // MCP remote assets contain only configuration, no server code
 type MCPRemoteDetector struct{}
 
-// Compile-time interface check
-var _ UsageDetector = (*MCPRemoteDetector)(nil)
+// Compile-time interface checks
+var (
+	_ AssetTypeDetector = (*MCPRemoteDetector)(nil)
+	_ UsageDetector     = (*MCPRemoteDetector)(nil)
+)
+
+// DetectType always returns false: a remote MCP is only configuration, so it can't
+// be told apart from other assets by its files
+func (h *MCPRemoteDetector) DetectType(files []string) bool {
+	return false
+}
+
+// GetType returns the asset type string
+func (h *MCPRemoteDetector) GetType() string {
+	return "mcp-remote"
+}
+
+// CreateDefaultMetadata creates default metadata for a remote MCP
+func (h *MCPRemoteDetector) CreateDefaultMetadata(name, version string) *metadata.Metadata {
+	return &metadata.Metadata{
+		MetadataVersion: "1.0",
+		Asset: metadata.Asset{
+			Name:    name,
+			Version: version,
+			Type:    asset.TypeMCPRemote,
+		},
+		MCP: &metadata.MCPConfig{},
+	}
+}
 
 // DetectUsageFromToolCall detects MCP remote server usage from tool calls
 // MCP remote uses the same tool naming pattern as regular MCP, so we use the same logic
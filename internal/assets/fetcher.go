@@ -2,7 +2,10 @@ package assets
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"regexp"
 	"sync"
 
 	"github.com/schollz/progressbar/v3"
@@ -14,6 +17,9 @@ import (
 	vaultpkg "github.com/sleuth-io/sx/internal/vault"
 )
 
+// ErrNotCached is returned by an offline fetcher for assets missing from the disk cache
+var ErrNotCached = errors.New("not in the local cache")
+
 // AssetFetcher handles fetching assets from a vault
 type AssetFetcher struct {
 	vault vaultpkg.Vault
@@ -26,10 +32,77 @@ func NewAssetFetcher(vault vaultpkg.Vault) *AssetFetcher {
 	}
 }
 
+// NewOfflineAssetFetcher creates a fetcher that only reads the disk cache
+func NewOfflineAssetFetcher() *AssetFetcher {
+	return &AssetFetcher{}
+}
+
+// commitSHA matches a full git commit hash
+var commitSHA = regexp.MustCompile(`^[0-9a-f]{40}([0-9a-f]{24})?$`)
+
+// lockHashes returns the hashes the lock file records for an asset, if any
+func lockHashes(asset *lockfile.Asset) map[string]string {
+	if asset.SourceHTTP != nil {
+		return asset.SourceHTTP.Hashes
+	}
+	return nil
+}
+
+// sourceIdentity identifies the content an asset is fetched from, for validating
+// cached zips. ok is false when the content can change without the lock file
+// changing: path sources and git branches or tags are read from the vault again.
+func sourceIdentity(asset *lockfile.Asset) (identity string, ok bool) {
+	switch {
+	case asset.SourceHTTP != nil:
+		// Validated against the lock file's hashes instead
+		return "", true
+	case asset.SourceGit != nil:
+		source := asset.SourceGit
+		if !commitSHA.MatchString(source.Ref) {
+			return "", false
+		}
+		return "git:" + source.URL + "@" + source.Ref + ":" + source.Subdirectory, true
+	default:
+		return "", false
+	}
+}
+
+// loadCached returns the cached zip for an asset, if it's still current
+// Offline, whatever is cached for a source is used (e.g. zips imported from a
+// bundle) since there's nothing else to install from.
+func (f *AssetFetcher) loadCached(asset *lockfile.Asset) ([]byte, error) {
+	identity, ok := sourceIdentity(asset)
+	if f.vault == nil {
+		identity = ""
+	} else if !ok {
+		return nil, os.ErrNotExist
+	}
+	return cache.LoadAssetFromDisk(asset.Name, asset.Version, lockHashes(asset), identity)
+}
+
+// saveCached caches a downloaded zip along with the identity of its source
+func saveCached(asset *lockfile.Asset, zipData []byte) {
+	identity, _ := sourceIdentity(asset)
+	// Ignore cache save errors - not critical
+	_ = cache.SaveAssetToDisk(asset.Name, asset.Version, zipData, identity)
+}
+
+// download fetches an asset from the vault, or fails if the fetcher is offline
+func (f *AssetFetcher) download(ctx context.Context, asset *lockfile.Asset) ([]byte, error) {
+	if f.vault == nil {
+		return nil, fmt.Errorf("%s@%s is %w (offline mode)", asset.Name, asset.Version, ErrNotCached)
+	}
+	zipData, err := f.vault.GetAsset(ctx, asset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download asset: %w", err)
+	}
+	return zipData, nil
+}
+
 // FetchAsset downloads a single asset
 func (f *AssetFetcher) FetchAsset(ctx context.Context, asset *lockfile.Asset) (zipData []byte, meta *metadata.Metadata, err error) {
 	// Try disk cache first
-	zipData, err = cache.LoadAssetFromDisk(asset.Name, asset.Version)
+	zipData, err = f.loadCached(asset)
 	if err == nil {
 		// Cache hit, extract metadata and return
 		metadataBytes, err := utils.ReadZipFile(zipData, "metadata.toml")
@@ -44,9 +117,9 @@ func (f *AssetFetcher) FetchAsset(ctx context.Context, asset *lockfile.Asset) (z
 	}
 
 	// Cache miss or invalid, download asset
-	zipData, err = f.vault.GetAsset(ctx, asset)
+	zipData, err = f.download(ctx, asset)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to download asset: %w", err)
+		return nil, nil, err
 	}
 
 	// Verify it's a valid zip
@@ -71,8 +144,7 @@ func (f *AssetFetcher) FetchAsset(ctx context.Context, asset *lockfile.Asset) (z
 	}
 
 	// Cache to disk for future use
-	_ = cache.SaveAssetToDisk(asset.Name, asset.Version, zipData)
-	// Ignore cache save errors - not critical
+	saveCached(asset, zipData)
 
 	return zipData, meta, nil
 }
@@ -80,7 +152,7 @@ func (f *AssetFetcher) FetchAsset(ctx context.Context, asset *lockfile.Asset) (z
 // FetchAssetWithProgress downloads a single asset with progress bar
 func (f *AssetFetcher) FetchAssetWithProgress(ctx context.Context, asset *lockfile.Asset, bar *progressbar.ProgressBar) (zipData []byte, meta *metadata.Metadata, err error) {
 	// Try disk cache first
-	zipData, err = cache.LoadAssetFromDisk(asset.Name, asset.Version)
+	zipData, err = f.loadCached(asset)
 	if err == nil {
 		// Cache hit, extract metadata and return
 		metadataBytes, err := utils.ReadZipFile(zipData, "metadata.toml")
@@ -99,9 +171,9 @@ func (f *AssetFetcher) FetchAssetWithProgress(ctx context.Context, asset *lockfi
 	}
 
 	// Download asset through vault (handles auth properly)
-	zipData, err = f.vault.GetAsset(ctx, asset)
+	zipData, err = f.download(ctx, asset)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to download asset: %w", err)
+		return nil, nil, err
 	}
 
 	// Update progress bar to 100% after download
@@ -132,8 +204,7 @@ func (f *AssetFetcher) FetchAssetWithProgress(ctx context.Context, asset *lockfi
 	}
 
 	// Cache to disk for future use
-	_ = cache.SaveAssetToDisk(asset.Name, asset.Version, zipData)
-	// Ignore cache save errors - not critical
+	saveCached(asset, zipData)
 
 	return zipData, meta, nil
 }
@@ -217,5 +288,8 @@ func (f *AssetFetcher) FetchAssets(ctx context.Context, assets []*lockfile.Asset
 		results[result.Index] = result
 	}
 
+	// Record the cache hits in one index update
+	cache.FlushAssetUses()
+
 	return results, nil
 }
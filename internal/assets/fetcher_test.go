@@ -0,0 +1,76 @@
+package assets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/lockfile"
+	"github.com/sleuth-io/sx/internal/utils"
+	vaultpkg "github.com/sleuth-io/sx/internal/vault"
+)
+
+func TestFetchAssetRereadsRepublishedPathSource(t *testing.T) {
+	t.Setenv("SX_CACHE_DIR", t.TempDir())
+	vaultDir := t.TempDir()
+	assetDir := filepath.Join(vaultDir, "assets", "helper", "1.0.0")
+
+	writeSkill := func(content string) {
+		t.Helper()
+		if err := os.MkdirAll(assetDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		metadata := "[asset]\nname = \"helper\"\nversion = \"1.0.0\"\ntype = \"skill\"\n\n[skill]\nprompt-file = \"SKILL.md\"\n"
+		if err := os.WriteFile(filepath.Join(assetDir, "metadata.toml"), []byte(metadata), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(assetDir, "SKILL.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	vault, err := vaultpkg.NewPathVault("file://" + vaultDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetcher := NewAssetFetcher(vault)
+	asset := &lockfile.Asset{
+		Name:       "helper",
+		Version:    "1.0.0",
+		SourcePath: &lockfile.SourcePath{Path: assetDir},
+	}
+
+	read := func() string {
+		t.Helper()
+		zipData, _, err := fetcher.FetchAsset(context.Background(), asset)
+		if err != nil {
+			t.Fatalf("FetchAsset() error: %v", err)
+		}
+		content, err := utils.ReadZipFile(zipData, "SKILL.md")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+
+	writeSkill("original")
+	if got := read(); got != "original" {
+		t.Fatalf("SKILL.md = %q, want original", got)
+	}
+
+	// The vault changes the version in place; the cached zip must not be served
+	writeSkill("republished")
+	if got := read(); got != "republished" {
+		t.Errorf("SKILL.md = %q, want republished", got)
+	}
+
+	// Offline, the cached copy is all there is
+	got, _, err := NewOfflineAssetFetcher().FetchAsset(context.Background(), asset)
+	if err != nil {
+		t.Fatalf("offline FetchAsset() error: %v", err)
+	}
+	if content, _ := utils.ReadZipFile(got, "SKILL.md"); string(content) != "republished" {
+		t.Errorf("offline SKILL.md = %q, want republished", content)
+	}
+}
@@ -30,6 +30,14 @@ type InstalledAsset struct {
 	Repository string   `json:"repository,omitempty"` // Empty for global scope
 	Path       string   `json:"path,omitempty"`       // Path within repo (if path-scoped)
 	Clients    []string `json:"clients"`
+	// LinkedFrom is the working directory of an asset installed with 'sx link'
+	LinkedFrom string `json:"linked-from,omitempty"`
+	// OverlayHash identifies the repo overlay applied on top of the vault version
+	OverlayHash string `json:"overlay-hash,omitempty"`
+	// RuntimeDir is where a packaged MCP server's setup ran and the server runs from
+	RuntimeDir string `json:"runtime-dir,omitempty"`
+	// Requires are the tools the installed version requires, re-checked by 'sx config'
+	Requires []string `json:"requires,omitempty"`
 }
 
 // AssetKey uniquely identifies an asset by name + scope
@@ -66,6 +74,11 @@ func (a *InstalledAsset) IsGlobal() bool {
 	return a.Repository == ""
 }
 
+// IsLinked returns true if this asset is a linked working directory, not a vault version
+func (a *InstalledAsset) IsLinked() bool {
+	return a.LinkedFrom != ""
+}
+
 // ScopeDescription returns a human-readable scope description
 func (a *InstalledAsset) ScopeDescription() string {
 	if a.Repository == "" {
@@ -284,6 +297,7 @@ func (t *Tracker) GroupByScope() map[string][]InstalledAsset {
 
 // NeedsInstall checks if an asset needs to be installed or updated
 // Returns true if the asset is new, has a different version, or is missing clients
+// Linked assets never need installing
 func (t *Tracker) NeedsInstall(key AssetKey, version string, targetClients []string) bool {
 	existing := t.FindAsset(key)
 
@@ -291,6 +305,10 @@ func (t *Tracker) NeedsInstall(key AssetKey, version string, targetClients []str
 		return true // New asset
 	}
 
+	if existing.IsLinked() {
+		return false // Linked working copies are left alone until 'sx unlink'
+	}
+
 	if existing.Version != version {
 		return true // Version changed
 	}
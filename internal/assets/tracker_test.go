@@ -302,3 +302,16 @@ func TestNewAssetKey(t *testing.T) {
 		})
 	}
 }
+
+func TestNeedsInstallSkipsLinkedAssets(t *testing.T) {
+	tracker := &Tracker{Version: TrackerFormatVersion}
+	tracker.UpsertAsset(InstalledAsset{Name: "code-review", Version: "0.1.0", Clients: []string{"claude-code"}, LinkedFrom: "/src/code-review"})
+
+	key := NewAssetKey("code-review", lockfile.ScopeGlobal, "", "")
+	if tracker.NeedsInstall(key, "1.0.0", []string{"claude-code", "cursor"}) {
+		t.Error("linked asset should not need installing")
+	}
+	if !tracker.FindAsset(key).IsLinked() {
+		t.Error("expected asset to be linked")
+	}
+}
@@ -0,0 +1,136 @@
+package audience
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/sleuth-io/sx/internal/config"
+	"github.com/sleuth-io/sx/internal/gitutil"
+	"github.com/sleuth-io/sx/internal/logger"
+)
+
+// Identity is who assets are being installed for, used to match their audience
+type Identity struct {
+	Email  string   `json:"email,omitempty"`
+	Groups []string `json:"groups"`
+}
+
+// Resolve works out the user's groups from the configured groups, group rules
+// matched against git user.email, and for Sleuth vaults, the groups claim of the
+// auth token
+func Resolve(ctx context.Context, cfg *config.Config) *Identity {
+	email, err := gitutil.GetUserEmail(ctx)
+	if err != nil {
+		logger.Get().Debug("no git user.email for group rules", "error", err)
+	}
+	return resolve(cfg, email)
+}
+
+func resolve(cfg *config.Config, email string) *Identity {
+	id := &Identity{Email: email, Groups: []string{}}
+
+	seen := make(map[string]bool)
+	add := func(group string) {
+		if group != "" && !seen[group] {
+			seen[group] = true
+			id.Groups = append(id.Groups, group)
+		}
+	}
+
+	for _, group := range cfg.Groups {
+		add(group)
+	}
+
+	for group, patterns := range cfg.GroupRules {
+		for _, pattern := range patterns {
+			if MatchEmail(pattern, email) {
+				add(group)
+				break
+			}
+		}
+	}
+
+	if cfg.Type == config.RepositoryTypeSleuth {
+		for _, group := range tokenGroups(cfg.AuthToken) {
+			add(group)
+		}
+	}
+
+	sort.Strings(id.Groups)
+	return id
+}
+
+// InGroup checks if the user belongs to a group
+func (i *Identity) InGroup(group string) bool {
+	for _, g := range i.Groups {
+		if strings.EqualFold(g, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches checks if an asset with the given audience is for this user. An empty
+// audience is for everyone; !group entries exclude members of that group, and
+// win over the groups that include them.
+func (i *Identity) Matches(audience []string) bool {
+	if len(audience) == 0 {
+		return true
+	}
+
+	hasIncludes, included := false, false
+	for _, group := range audience {
+		if excluded, ok := strings.CutPrefix(group, "!"); ok {
+			if i.InGroup(excluded) {
+				return false
+			}
+			continue
+		}
+		hasIncludes = true
+		if i.InGroup(group) {
+			included = true
+		}
+	}
+
+	return included || !hasIncludes
+}
+
+// MatchEmail matches an email against a glob such as "*@example.com". A pattern
+// starting with @ matches the whole domain.
+func MatchEmail(pattern, email string) bool {
+	if email == "" || pattern == "" {
+		return false
+	}
+	pattern = strings.ToLower(pattern)
+	if strings.HasPrefix(pattern, "@") {
+		pattern = "*" + pattern
+	}
+	ok, err := path.Match(pattern, strings.ToLower(email))
+	return err == nil && ok
+}
+
+// tokenGroups reads the groups claim from a JWT auth token. Opaque tokens have
+// no claims.
+func tokenGroups(token string) []string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil
+	}
+
+	var claims struct {
+		Groups []string `json:"groups"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil
+	}
+	return claims.Groups
+}
@@ -0,0 +1,67 @@
+package audience
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/config"
+)
+
+func TestResolveGroups(t *testing.T) {
+	cfg := &config.Config{
+		Type:   config.RepositoryTypePath,
+		Groups: []string{"frontend"},
+		GroupRules: map[string][]string{
+			"contractors": {"@agency.example.com"},
+			"sre":         {"ops-*@example.com"},
+		},
+	}
+
+	if got := strings.Join(resolve(cfg, "Dev@Agency.example.com").Groups, ","); got != "contractors,frontend" {
+		t.Errorf("groups = %s, want contractors,frontend", got)
+	}
+	if got := strings.Join(resolve(cfg, "ops-jo@example.com").Groups, ","); got != "frontend,sre" {
+		t.Errorf("groups = %s, want frontend,sre", got)
+	}
+	if got := strings.Join(resolve(cfg, "").Groups, ","); got != "frontend" {
+		t.Errorf("groups without email = %s, want frontend", got)
+	}
+}
+
+func TestResolveTokenGroups(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"1","groups":["sre","frontend"]}`))
+	cfg := &config.Config{Type: config.RepositoryTypeSleuth, AuthToken: "header." + payload + ".signature"}
+
+	if got := strings.Join(resolve(cfg, "").Groups, ","); got != "frontend,sre" {
+		t.Errorf("groups = %s, want frontend,sre", got)
+	}
+
+	cfg.AuthToken = "opaque-token"
+	if got := resolve(cfg, "").Groups; len(got) != 0 {
+		t.Errorf("groups for opaque token = %v, want none", got)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	id := &Identity{Groups: []string{"frontend", "contractors"}}
+
+	tests := []struct {
+		audience []string
+		want     bool
+	}{
+		{nil, true},
+		{[]string{"frontend"}, true},
+		{[]string{"sre"}, false},
+		{[]string{"sre", "Frontend"}, true},
+		{[]string{"frontend", "!contractors"}, false},
+		{[]string{"!sre"}, true},
+		{[]string{"!contractors"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := id.Matches(tt.audience); got != tt.want {
+			t.Errorf("Matches(%v) = %v, want %v", tt.audience, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+// Package bundle reads and writes offline bundles: a tar archive holding a lock file
+// and every asset zip it references, used to seed the cache on air-gapped machines.
+package bundle
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sleuth-io/sx/internal/utils"
+)
+
+const (
+	// FormatVersion is the bundle layout version written by this build
+	FormatVersion = 1
+
+	manifestFile = "manifest.json"
+	lockFile     = "sx.lock"
+	assetsDir    = "assets"
+)
+
+// Manifest describes the contents of a bundle
+type Manifest struct {
+	FormatVersion int       `json:"formatVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+	CreatedBy     string    `json:"createdBy"`
+
+	// VaultType and VaultURL identify the vault the lock file came from
+	VaultType string `json:"vaultType"`
+	VaultURL  string `json:"vaultUrl"`
+
+	// Scope describes which assets were selected (e.g. "global", a repo URL, or "all")
+	Scope string `json:"scope"`
+
+	Assets []Asset `json:"assets"`
+}
+
+// Asset is an asset zip stored in the bundle
+type Asset struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	SHA256  string `json:"sha256"`
+}
+
+// Bundle is the in-memory contents of a bundle file
+type Bundle struct {
+	Manifest Manifest
+	LockFile []byte
+
+	// Zips maps "name@version" to the asset zip
+	Zips map[string][]byte
+}
+
+// New creates an empty bundle
+func New(manifest Manifest, lockFileData []byte) *Bundle {
+	manifest.FormatVersion = FormatVersion
+	manifest.Assets = nil
+	return &Bundle{
+		Manifest: manifest,
+		LockFile: lockFileData,
+		Zips:     make(map[string][]byte),
+	}
+}
+
+// AddAsset adds an asset zip to the bundle
+func (b *Bundle) AddAsset(name, version string, zipData []byte) {
+	key := assetKey(name, version)
+	if _, ok := b.Zips[key]; !ok {
+		b.Manifest.Assets = append(b.Manifest.Assets, Asset{
+			Name:    name,
+			Version: version,
+			SHA256:  checksum(zipData),
+		})
+	}
+	b.Zips[key] = zipData
+}
+
+// Zip returns the zip for an asset in the bundle
+func (b *Bundle) Zip(name, version string) ([]byte, bool) {
+	data, ok := b.Zips[assetKey(name, version)]
+	return data, ok
+}
+
+// Write writes the bundle as a tar archive
+func (b *Bundle) Write(w io.Writer) error {
+	manifest := b.Manifest
+	manifest.Assets = append([]Asset(nil), b.Manifest.Assets...)
+	sort.Slice(manifest.Assets, func(i, j int) bool {
+		return assetKey(manifest.Assets[i].Name, manifest.Assets[i].Version) <
+			assetKey(manifest.Assets[j].Name, manifest.Assets[j].Version)
+	})
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	tw := tar.NewWriter(w)
+	modTime := manifest.CreatedAt
+
+	if err := writeEntry(tw, manifestFile, manifestData, modTime); err != nil {
+		return err
+	}
+	if err := writeEntry(tw, lockFile, b.LockFile, modTime); err != nil {
+		return err
+	}
+	for _, a := range manifest.Assets {
+		if err := writeEntry(tw, assetPath(a.Name, a.Version), b.Zips[assetKey(a.Name, a.Version)], modTime); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	return nil
+}
+
+// Read reads and verifies a bundle written by Write
+func Read(r io.Reader) (*Bundle, error) {
+	entries := make(map[string][]byte)
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from bundle: %w", hdr.Name, err)
+		}
+		entries[path.Clean(hdr.Name)] = data
+	}
+
+	manifestData, ok := entries[manifestFile]
+	if !ok {
+		return nil, fmt.Errorf("not an sx bundle: %s is missing", manifestFile)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse bundle manifest: %w", err)
+	}
+	if manifest.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("bundle format version %d is newer than this sx supports (%d); upgrade sx", manifest.FormatVersion, FormatVersion)
+	}
+
+	lockFileData, ok := entries[lockFile]
+	if !ok {
+		return nil, fmt.Errorf("bundle is missing %s", lockFile)
+	}
+
+	b := &Bundle{
+		Manifest: manifest,
+		LockFile: lockFileData,
+		Zips:     make(map[string][]byte),
+	}
+	for _, a := range manifest.Assets {
+		if !validSegment(a.Name) || !validSegment(a.Version) {
+			return nil, fmt.Errorf("bundle lists an invalid asset %q@%q", a.Name, a.Version)
+		}
+		data, ok := entries[assetPath(a.Name, a.Version)]
+		if !ok {
+			return nil, fmt.Errorf("bundle is missing %s@%s", a.Name, a.Version)
+		}
+		if sum := checksum(data); sum != a.SHA256 {
+			return nil, fmt.Errorf("checksum mismatch for %s@%s: bundle may be corrupted", a.Name, a.Version)
+		}
+		if !utils.IsZipFile(data) {
+			return nil, fmt.Errorf("%s@%s in bundle is not a valid zip archive", a.Name, a.Version)
+		}
+		b.Zips[assetKey(a.Name, a.Version)] = data
+	}
+
+	return b, nil
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+	}
+	return nil
+}
+
+func assetKey(name, version string) string {
+	return name + "@" + version
+}
+
+func assetPath(name, version string) string {
+	return path.Join(assetsDir, name, version+".zip")
+}
+
+// validSegment rejects names that would escape their directory in the archive
+func validSegment(s string) bool {
+	return s != "" && s != "." && s != ".." && !strings.ContainsAny(s, `/\`)
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
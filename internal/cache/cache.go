@@ -61,8 +61,17 @@ func getFallbackCacheDir() (string, error) {
 	}
 }
 
-// GetAssetCacheDir returns the directory for caching assets
+// GetAssetCacheDir returns the directory for caching assets (the content-addressed store)
 func GetAssetCacheDir() (string, error) {
+	cacheDir, err := GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "asset-store"), nil
+}
+
+// getLegacyAssetCacheDir returns the old name/version keyed asset directory
+func getLegacyAssetCacheDir() (string, error) {
 	cacheDir, err := GetCacheDir()
 	if err != nil {
 		return "", err
@@ -94,6 +103,34 @@ func GetLockFileCacheDir() (string, error) {
 	return filepath.Join(cacheDir, "lockfiles"), nil
 }
 
+// GetRegistryCacheDir returns the directory for caching remote registry indexes
+func GetRegistryCacheDir() (string, error) {
+	cacheDir, err := GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "registries"), nil
+}
+
+// GetLanguageCacheDir returns the directory for caching detected repository languages
+func GetLanguageCacheDir() (string, error) {
+	cacheDir, err := GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "languages"), nil
+}
+
+// GetMCPRuntimeCacheDir returns the directory where packaged MCP servers are set
+// up. Installed servers run from here, so 'sx cache clear' leaves it alone.
+func GetMCPRuntimeCacheDir() (string, error) {
+	cacheDir, err := GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "mcp-runtimes"), nil
+}
+
 // EnsureCacheDirs creates all necessary cache directories
 func EnsureCacheDirs() error {
 	dirs := []func() (string, error){
@@ -116,9 +153,9 @@ func EnsureCacheDirs() error {
 	return nil
 }
 
-// GetAssetCachePath returns the cache path for a specific asset
-func GetAssetCachePath(name, version string) (string, error) {
-	assetCacheDir, err := GetAssetCacheDir()
+// getLegacyAssetCachePath returns the pre-store cache path for a specific asset
+func getLegacyAssetCachePath(name, version string) (string, error) {
+	assetCacheDir, err := getLegacyAssetCacheDir()
 	if err != nil {
 		return "", err
 	}
@@ -137,15 +174,6 @@ func GetGitRepoCachePath(repoURL string) (string, error) {
 	return filepath.Join(gitReposDir, urlHash), nil
 }
 
-// ClearAssetCache removes cached assets for cleanup
-func ClearAssetCache() error {
-	assetCacheDir, err := GetAssetCacheDir()
-	if err != nil {
-		return err
-	}
-	return os.RemoveAll(assetCacheDir)
-}
-
 // ETagCache stores ETags for lock files
 type ETagCache struct {
 	URL  string    `json:"url"`
@@ -254,6 +282,27 @@ func LoadLockFile(repoURL string) ([]byte, error) {
 	return os.ReadFile(path)
 }
 
+// SaveRegistryIndex caches a fetched registry index so it stays usable offline
+func SaveRegistryIndex(indexURL string, data []byte) error {
+	dir, err := GetRegistryCacheDir()
+	if err != nil {
+		return err
+	}
+	if err := utils.EnsureDir(dir); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, utils.URLHash(indexURL)+".yaml"), data, 0644)
+}
+
+// LoadRegistryIndex loads a cached registry index
+func LoadRegistryIndex(indexURL string) ([]byte, error) {
+	dir, err := GetRegistryCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(filepath.Join(dir, utils.URLHash(indexURL)+".yaml"))
+}
+
 // GetTrackerCacheDir returns the directory for tracking installed assets state
 func GetTrackerCacheDir() (string, error) {
 	cacheDir, err := GetCacheDir()
@@ -273,52 +322,6 @@ func GetTrackerCachePath(scopeKey string) (string, error) {
 	return filepath.Join(trackerDir, scopeKey+".json"), nil
 }
 
-// SaveAssetToDisk caches an asset zip to disk
-func SaveAssetToDisk(name, version string, data []byte) error {
-	cachePath, err := GetAssetCachePath(name, version)
-	if err != nil {
-		return err
-	}
-
-	// Ensure directory exists
-	if err := utils.EnsureDir(filepath.Dir(cachePath)); err != nil {
-		return err
-	}
-
-	// Verify it's a valid zip before caching
-	if !utils.IsZipFile(data) {
-		return fmt.Errorf("not a valid zip file")
-	}
-
-	return os.WriteFile(cachePath, data, 0644)
-}
-
-// LoadAssetFromDisk loads a cached asset from disk
-func LoadAssetFromDisk(name, version string) ([]byte, error) {
-	cachePath, err := GetAssetCachePath(name, version)
-	if err != nil {
-		return nil, err
-	}
-
-	if !utils.FileExists(cachePath) {
-		return nil, os.ErrNotExist
-	}
-
-	data, err := os.ReadFile(cachePath)
-	if err != nil {
-		return nil, err
-	}
-
-	// Verify cached file is still a valid zip
-	if !utils.IsZipFile(data) {
-		// Corrupted cache, remove it
-		os.Remove(cachePath)
-		return nil, fmt.Errorf("cached file corrupted")
-	}
-
-	return data, nil
-}
-
 // InvalidateLockFileCache removes cached lock file and ETag for a repository URL
 // This forces the next GetLockFile call to fetch fresh data from the backend
 func InvalidateLockFileCache(repoURL string) error {
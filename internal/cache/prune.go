@@ -0,0 +1,281 @@
+package cache
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GitRepoEntry is a cached git clone (a git vault or a git asset source)
+type GitRepoEntry struct {
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// PrunePolicy controls which cache entries Prune evicts
+type PrunePolicy struct {
+	// MaxAge evicts assets and git clones not used for longer than this (0 disables)
+	MaxAge time.Duration
+	// MaxSize evicts least recently used assets until the store fits (0 disables)
+	MaxSize int64
+	// Keep holds name@version keys that are never evicted (e.g. installed assets)
+	Keep map[string]bool
+	// KeepRepos holds git clone paths that are never pruned (e.g. the configured vault)
+	KeepRepos map[string]bool
+	// DryRun reports what would be evicted without removing anything
+	DryRun bool
+}
+
+// PruneResult reports what Prune evicted
+type PruneResult struct {
+	Assets   []AssetEntry
+	GitRepos []GitRepoEntry
+	// Blobs is the number of zips removed, including ones no entry referenced
+	Blobs int
+	Freed int64
+}
+
+// Prune evicts asset store entries and git clones according to policy
+func Prune(policy PrunePolicy) (*PruneResult, error) {
+	unlock, err := lockIndex()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	result := &PruneResult{}
+	now := time.Now()
+
+	idx, err := loadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	evict := func(e *AssetEntry) {
+		result.Assets = append(result.Assets, *e)
+		delete(idx.Entries, e.Key())
+	}
+
+	// Least recently used first, so size-based eviction drops the coldest entries
+	entries := make([]AssetEntry, 0, len(idx.Entries))
+	for _, e := range idx.Entries {
+		entries = append(entries, *e)
+	}
+	sortByLastUsed(entries)
+
+	if policy.MaxAge > 0 {
+		for i := range entries {
+			e := &entries[i]
+			if !policy.Keep[e.Key()] && now.Sub(e.LastUsed) > policy.MaxAge {
+				evict(e)
+			}
+		}
+	}
+
+	if policy.MaxSize > 0 {
+		for i := range entries {
+			if storeSize(idx) <= policy.MaxSize {
+				break
+			}
+			e := &entries[i]
+			if _, ok := idx.Entries[e.Key()]; ok && !policy.Keep[e.Key()] {
+				evict(e)
+			}
+		}
+	}
+
+	// Remove blobs no remaining entry points to
+	referenced := make(map[string]bool)
+	for _, e := range idx.Entries {
+		referenced[e.SHA256] = true
+	}
+	dir, err := GetAssetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	_ = filepath.WalkDir(filepath.Join(dir, blobsDir), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		sum := strings.TrimSuffix(d.Name(), ".zip")
+		if referenced[sum] {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			result.Freed += info.Size()
+		}
+		result.Blobs++
+		if !policy.DryRun {
+			os.Remove(path)
+		}
+		return nil
+	})
+
+	if !policy.DryRun && (len(result.Assets) > 0 || result.Blobs > 0) {
+		if err := saveIndex(idx); err != nil {
+			return nil, err
+		}
+	}
+
+	// Git clones are re-cloned on demand, so only age applies
+	if policy.MaxAge > 0 {
+		repos, err := ListGitRepos()
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			if policy.KeepRepos[repo.Path] || now.Sub(repo.LastUsed) <= policy.MaxAge {
+				continue
+			}
+			result.GitRepos = append(result.GitRepos, repo)
+			result.Freed += repo.Size
+			if !policy.DryRun {
+				if err := os.RemoveAll(repo.Path); err != nil {
+					return nil, fmt.Errorf("failed to remove cached git clone: %w", err)
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// ListGitRepos returns the cached git clones, least recently used first
+func ListGitRepos() ([]GitRepoEntry, error) {
+	reposDir, err := GetGitReposCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	dirEntries, err := os.ReadDir(reposDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var repos []GitRepoEntry
+	for _, d := range dirEntries {
+		if !d.IsDir() {
+			continue
+		}
+		path := filepath.Join(reposDir, d.Name())
+		repos = append(repos, GitRepoEntry{
+			Path:     path,
+			Size:     dirSize(path),
+			LastUsed: gitLastUsed(path),
+		})
+	}
+	sort.Slice(repos, func(i, j int) bool {
+		return repos[i].LastUsed.Before(repos[j].LastUsed)
+	})
+	return repos, nil
+}
+
+// StoreSize returns the total size of the zips in the asset store
+func StoreSize() (int64, error) {
+	idx, err := loadIndex()
+	if err != nil {
+		return 0, err
+	}
+	return storeSize(idx), nil
+}
+
+// Clear removes all cached assets, git clones, lock files, registry indexes, and
+// detected repository languages
+// Installed assets, the tracker, and install journals are left alone.
+func Clear() (int64, error) {
+	// Hold the index lock so no other process writes the store while it's cleared
+	unlock, err := lockIndex()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	// The store is emptied rather than removed, keeping the lock file others wait on
+	storeDir, err := GetAssetCacheDir()
+	if err != nil {
+		return 0, err
+	}
+	entries, err := os.ReadDir(storeDir)
+	if err != nil {
+		return 0, err
+	}
+	var freed int64
+	for _, entry := range entries {
+		if entry.Name() == indexLockFile {
+			continue
+		}
+		path := filepath.Join(storeDir, entry.Name())
+		freed += dirSize(path)
+		if err := os.RemoveAll(path); err != nil {
+			return freed, err
+		}
+	}
+
+	for _, dirFunc := range []func() (string, error){
+		getLegacyAssetCacheDir,
+		GetGitReposCacheDir,
+		GetLockFileCacheDir,
+		GetRegistryCacheDir,
+		GetLanguageCacheDir,
+	} {
+		dir, err := dirFunc()
+		if err != nil {
+			return freed, err
+		}
+		freed += dirSize(dir)
+		if err := os.RemoveAll(dir); err != nil {
+			return freed, err
+		}
+	}
+	return freed, nil
+}
+
+// storeSize sums the unique blobs referenced by the index
+func storeSize(idx *storeIndex) int64 {
+	seen := make(map[string]bool)
+	var total int64
+	for _, e := range idx.Entries {
+		if !seen[e.SHA256] {
+			seen[e.SHA256] = true
+			total += e.Size
+		}
+	}
+	return total
+}
+
+// gitLastUsed estimates when a clone was last fetched or checked out
+func gitLastUsed(path string) time.Time {
+	var latest time.Time
+	for _, p := range []string{
+		path,
+		filepath.Join(path, ".git", "FETCH_HEAD"),
+		filepath.Join(path, ".git", "HEAD"),
+		filepath.Join(path, ".git", "index"),
+	} {
+		if info, err := os.Stat(p); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
+func dirSize(path string) int64 {
+	var size int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
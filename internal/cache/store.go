@@ -0,0 +1,401 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/sleuth-io/sx/internal/utils"
+)
+
+// The asset store keeps zips addressed by their sha256 under blobsDir, plus an index
+// mapping name@version to the blob it resolved to. A vault that republishes a version
+// with new contents gets a new blob; the index entry is checked against the lock
+// file's hash, or the identity of the source it came from, so the stale zip is never
+// served.
+const (
+	blobsDir      = "sha256"
+	indexFile     = "index.json"
+	indexLockFile = "index.lock"
+
+	// indexLockWait bounds how long an index update waits for another process
+	indexLockWait = 10 * time.Second
+)
+
+// AssetEntry is a name@version entry in the asset store
+type AssetEntry struct {
+	Name     string    `json:"name"`
+	Version  string    `json:"version"`
+	SHA256   string    `json:"sha256"`
+	Size     int64     `json:"size"`
+	Source   string    `json:"source,omitempty"` // Identity of the source the zip came from, if known
+	AddedAt  time.Time `json:"addedAt"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// Key returns the name@version key of the entry
+func (e *AssetEntry) Key() string {
+	return AssetKey(e.Name, e.Version)
+}
+
+// AssetKey builds the store key for an asset
+func AssetKey(name, version string) string {
+	return name + "@" + version
+}
+
+type storeIndex struct {
+	Entries map[string]*AssetEntry `json:"entries"`
+}
+
+var (
+	// storeMu serializes index updates within the process; lockIndex adds a file
+	// lock on top for other sx processes
+	storeMu sync.Mutex
+
+	// pendingUses holds cache hits not yet recorded in the index. They only affect
+	// pruning order, so they're written with the next index update or FlushAssetUses
+	// instead of rewriting the index on every hit.
+	pendingUses   = make(map[string]time.Time)
+	pendingUsesMu sync.Mutex
+)
+
+// SaveAssetToDisk caches an asset zip to disk
+// source identifies the content it was fetched from (see LoadAssetFromDisk), or is
+// empty if unknown
+func SaveAssetToDisk(name, version string, data []byte, source string) error {
+	// Verify it's a valid zip before caching
+	if !utils.IsZipFile(data) {
+		return fmt.Errorf("not a valid zip file")
+	}
+
+	unlock, err := lockIndex()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Written under the lock so a concurrent prune can't remove it before it's indexed
+	sum := utils.ComputeSHA256(data)
+	if err := writeBlob(sum, data); err != nil {
+		return err
+	}
+
+	idx, err := loadIndex()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	key := AssetKey(name, version)
+	entry := idx.Entries[key]
+	if entry == nil || entry.SHA256 != sum {
+		entry = &AssetEntry{Name: name, Version: version, SHA256: sum, Size: int64(len(data)), AddedAt: now}
+		idx.Entries[key] = entry
+	}
+	entry.Source = source
+	entry.LastUsed = now
+
+	return saveIndex(idx)
+}
+
+// LoadAssetFromDisk loads a cached asset from disk
+// hashes are the lock file's hashes for the asset and source the identity of the
+// content it's fetched from (either may be empty); a cached zip that doesn't match
+// them is treated as a miss so the current version is downloaded.
+func LoadAssetFromDisk(name, version string, hashes map[string]string, source string) ([]byte, error) {
+	// The index is replaced atomically, so reading it needs no lock
+	idx, err := loadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	key := AssetKey(name, version)
+	entry := idx.Entries[key]
+	if entry == nil {
+		if entry, err = migrateLegacyAsset(name, version); err != nil || entry == nil {
+			return nil, os.ErrNotExist
+		}
+	}
+
+	if expected := hashes["sha256"]; expected != "" && expected != entry.SHA256 {
+		// Republished with new contents
+		dropEntry(entry)
+		return nil, os.ErrNotExist
+	}
+	if source != "" && source != entry.Source {
+		// Fetched from another commit or an unknown source
+		dropEntry(entry)
+		return nil, os.ErrNotExist
+	}
+
+	data, err := os.ReadFile(blobPath(entry.SHA256))
+	if err != nil || utils.ComputeSHA256(data) != entry.SHA256 {
+		// Corrupted or missing blob, drop it
+		os.Remove(blobPath(entry.SHA256))
+		dropEntry(entry)
+		return nil, fmt.Errorf("cached file corrupted")
+	}
+
+	if expected := hashes["sha512"]; expected != "" && hashes["sha256"] == "" {
+		if err := utils.VerifyHash(data, "sha512", expected); err != nil {
+			dropEntry(entry)
+			return nil, os.ErrNotExist
+		}
+	}
+
+	pendingUsesMu.Lock()
+	pendingUses[key] = time.Now()
+	pendingUsesMu.Unlock()
+
+	return data, nil
+}
+
+// FlushAssetUses records the cache hits since the last index update
+// Failures are ignored: the access times only affect pruning order
+func FlushAssetUses() {
+	pendingUsesMu.Lock()
+	empty := len(pendingUses) == 0
+	pendingUsesMu.Unlock()
+	if empty {
+		return
+	}
+
+	unlock, err := lockIndex()
+	if err != nil {
+		return
+	}
+	defer unlock()
+
+	idx, err := loadIndex()
+	if err != nil {
+		return
+	}
+	_ = saveIndex(idx)
+}
+
+// dropEntry removes a stale entry from the index, unless another process has
+// already replaced it
+func dropEntry(stale *AssetEntry) {
+	unlock, err := lockIndex()
+	if err != nil {
+		return
+	}
+	defer unlock()
+
+	idx, err := loadIndex()
+	if err != nil {
+		return
+	}
+	if entry := idx.Entries[stale.Key()]; entry != nil && entry.SHA256 == stale.SHA256 {
+		delete(idx.Entries, stale.Key())
+		_ = saveIndex(idx)
+	}
+}
+
+// lockIndex takes the index lock, shared with other sx processes
+// The returned function releases it.
+func lockIndex() (func(), error) {
+	dir, err := GetAssetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.EnsureDir(dir); err != nil {
+		return nil, fmt.Errorf("failed to create asset store: %w", err)
+	}
+
+	storeMu.Lock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), indexLockWait)
+	defer cancel()
+
+	fileLock := flock.New(filepath.Join(dir, indexLockFile))
+	locked, err := fileLock.TryLockContext(ctx, 50*time.Millisecond)
+	if err != nil || !locked {
+		storeMu.Unlock()
+		if err == nil {
+			err = ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to lock asset cache index: %w", err)
+	}
+
+	return func() {
+		_ = fileLock.Unlock()
+		storeMu.Unlock()
+	}, nil
+}
+
+// ListAssets returns every entry in the asset store, least recently used first
+func ListAssets() ([]AssetEntry, error) {
+	idx, err := loadIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]AssetEntry, 0, len(idx.Entries))
+	for _, e := range idx.Entries {
+		entries = append(entries, *e)
+	}
+	sortByLastUsed(entries)
+	return entries, nil
+}
+
+// migrateLegacyAsset moves a zip from the old name/version layout into the store
+func migrateLegacyAsset(name, version string) (*AssetEntry, error) {
+	legacyPath, err := getLegacyAssetCachePath(name, version)
+	if err != nil || !utils.FileExists(legacyPath) {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(legacyPath)
+	if err != nil {
+		return nil, err
+	}
+	if !utils.IsZipFile(data) {
+		// A corrupt legacy zip is no use to anyone
+		_ = os.Remove(legacyPath)
+		return nil, nil
+	}
+
+	unlock, err := lockIndex()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	sum := utils.ComputeSHA256(data)
+	if err := writeBlob(sum, data); err != nil {
+		return nil, err
+	}
+
+	idx, err := loadIndex()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	entry := &AssetEntry{Name: name, Version: version, SHA256: sum, Size: int64(len(data)), AddedAt: now, LastUsed: now}
+	idx.Entries[AssetKey(name, version)] = entry
+	if err := saveIndex(idx); err != nil {
+		return nil, err
+	}
+
+	// Only now is the legacy zip safe to drop; a failed migration keeps it for next time
+	_ = os.Remove(legacyPath)
+	return entry, nil
+}
+
+// blobPath returns the store path for a blob
+func blobPath(sum string) string {
+	dir, _ := GetAssetCacheDir()
+	return filepath.Join(dir, blobsDir, sum[:2], sum+".zip")
+}
+
+// writeBlob stores data under its hash, atomically so readers never see a partial zip
+func writeBlob(sum string, data []byte) error {
+	path := blobPath(sum)
+	if utils.FileExists(path) {
+		return nil
+	}
+	if err := utils.EnsureDir(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to create asset store: %w", err)
+	}
+	return writeFileAtomic(path, data)
+}
+
+func getIndexPath() (string, error) {
+	dir, err := GetAssetCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, indexFile), nil
+}
+
+// loadIndex reads the store index
+// An unreadable index is discarded: blobs are re-indexed as assets are fetched again
+func loadIndex() (*storeIndex, error) {
+	idx := &storeIndex{Entries: make(map[string]*AssetEntry)}
+
+	path, err := getIndexPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return idx, nil
+		}
+		return nil, fmt.Errorf("failed to read asset cache index: %w", err)
+	}
+	if err := json.Unmarshal(data, idx); err != nil {
+		return &storeIndex{Entries: make(map[string]*AssetEntry)}, nil
+	}
+	if idx.Entries == nil {
+		idx.Entries = make(map[string]*AssetEntry)
+	}
+	return idx, nil
+}
+
+// saveIndex writes the store index along with any pending cache hits; callers must
+// hold the index lock and have loaded idx under it
+func saveIndex(idx *storeIndex) error {
+	path, err := getIndexPath()
+	if err != nil {
+		return err
+	}
+
+	pendingUsesMu.Lock()
+	for key, used := range pendingUses {
+		if entry := idx.Entries[key]; entry != nil && used.After(entry.LastUsed) {
+			entry.LastUsed = used
+		}
+	}
+	clear(pendingUses)
+	pendingUsesMu.Unlock()
+
+	if err := utils.EnsureDir(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to create asset store: %w", err)
+	}
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal asset cache index: %w", err)
+	}
+	return writeFileAtomic(path, data)
+}
+
+// writeFileAtomic writes data via a temp file and rename
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+func sortByLastUsed(entries []AssetEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].LastUsed.Equal(entries[j].LastUsed) {
+			return entries[i].LastUsed.Before(entries[j].LastUsed)
+		}
+		return entries[i].Key() < entries[j].Key()
+	})
+}
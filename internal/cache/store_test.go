@@ -0,0 +1,202 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sleuth-io/sx/internal/utils"
+)
+
+func testZip(t *testing.T, content string) []byte {
+	t.Helper()
+	data, err := utils.CreateZipFromContent("SKILL.md", []byte(content))
+	if err != nil {
+		t.Fatalf("failed to create zip: %v", err)
+	}
+	return data
+}
+
+func TestAssetStoreVerifiesLockHashes(t *testing.T) {
+	t.Setenv("SX_CACHE_DIR", t.TempDir())
+
+	v1 := testZip(t, "original")
+	if err := SaveAssetToDisk("skill", "1.0.0", v1, ""); err != nil {
+		t.Fatalf("SaveAssetToDisk() error: %v", err)
+	}
+
+	got, err := LoadAssetFromDisk("skill", "1.0.0", map[string]string{"sha256": utils.ComputeSHA256(v1)}, "")
+	if err != nil || string(got) != string(v1) {
+		t.Fatalf("expected cache hit, got err=%v", err)
+	}
+
+	// The vault republished 1.0.0 with new contents
+	v2 := testZip(t, "republished")
+	if _, err := LoadAssetFromDisk("skill", "1.0.0", map[string]string{"sha256": utils.ComputeSHA256(v2)}, ""); err == nil {
+		t.Fatal("expected a miss for a zip that doesn't match the lock file")
+	}
+
+	if err := SaveAssetToDisk("skill", "1.0.0", v2, ""); err != nil {
+		t.Fatal(err)
+	}
+	got, err = LoadAssetFromDisk("skill", "1.0.0", nil, "")
+	if err != nil || string(got) != string(v2) {
+		t.Fatalf("expected the republished zip, got err=%v", err)
+	}
+}
+
+func TestAssetStoreVerifiesSource(t *testing.T) {
+	t.Setenv("SX_CACHE_DIR", t.TempDir())
+
+	data := testZip(t, "from git")
+	if err := SaveAssetToDisk("skill", "1.0.0", data, "git:repo@aaa"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadAssetFromDisk("skill", "1.0.0", nil, "git:repo@aaa"); err != nil {
+		t.Fatalf("expected cache hit for the same source, got %v", err)
+	}
+
+	// The lock file now points the version at another commit
+	if _, err := LoadAssetFromDisk("skill", "1.0.0", nil, "git:repo@bbb"); err == nil {
+		t.Fatal("expected a miss for a zip from another source")
+	}
+	if entries, _ := ListAssets(); len(entries) != 0 {
+		t.Errorf("stale entry should be dropped, got %+v", entries)
+	}
+}
+
+func TestAssetStoreBatchesLastUsed(t *testing.T) {
+	t.Setenv("SX_CACHE_DIR", t.TempDir())
+
+	if err := SaveAssetToDisk("skill", "1.0.0", testZip(t, "skill"), ""); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err := lockIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx, _ := loadIndex()
+	idx.Entries["skill@1.0.0"].LastUsed = time.Now().Add(-time.Hour)
+	_ = saveIndex(idx)
+	unlock()
+
+	indexPath, _ := getIndexPath()
+	before, _ := os.ReadFile(indexPath)
+	if _, err := LoadAssetFromDisk("skill", "1.0.0", nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := os.ReadFile(indexPath); string(after) != string(before) {
+		t.Error("a cache hit should not rewrite the index")
+	}
+
+	FlushAssetUses()
+	entries, _ := ListAssets()
+	if len(entries) != 1 || time.Since(entries[0].LastUsed) > time.Minute {
+		t.Errorf("expected the hit to be recorded, got %+v", entries)
+	}
+}
+
+func TestAssetStoreMigratesLegacyEntries(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("SX_CACHE_DIR", cacheDir)
+
+	data := testZip(t, "legacy")
+	legacyPath := filepath.Join(cacheDir, "assets", "skill", "1.0.0.zip")
+	if err := os.MkdirAll(filepath.Dir(legacyPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(legacyPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadAssetFromDisk("skill", "1.0.0", nil, "")
+	if err != nil || string(got) != string(data) {
+		t.Fatalf("expected legacy entry to be served, got err=%v", err)
+	}
+	if utils.FileExists(legacyPath) {
+		t.Error("expected legacy file to be moved into the store")
+	}
+	if entries, _ := ListAssets(); len(entries) != 1 || entries[0].SHA256 != utils.ComputeSHA256(data) {
+		t.Errorf("unexpected store entries: %+v", entries)
+	}
+}
+
+func TestAssetStoreKeepsLegacyEntryWhenMigrationFails(t *testing.T) {
+	cacheDir := t.TempDir()
+	t.Setenv("SX_CACHE_DIR", cacheDir)
+
+	legacyPath := filepath.Join(cacheDir, "assets", "skill", "1.0.0.zip")
+	if err := os.MkdirAll(filepath.Dir(legacyPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(legacyPath, testZip(t, "legacy"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A file where the blob directory belongs makes writing the blob fail
+	storeDir := filepath.Join(cacheDir, "asset-store")
+	if err := os.MkdirAll(storeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(storeDir, blobsDir), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadAssetFromDisk("skill", "1.0.0", nil, ""); err == nil {
+		t.Fatal("expected the migration to fail")
+	}
+	if !utils.FileExists(legacyPath) {
+		t.Error("legacy file was removed by a failed migration")
+	}
+}
+
+func TestPruneRespectsPolicies(t *testing.T) {
+	t.Setenv("SX_CACHE_DIR", t.TempDir())
+
+	for _, name := range []string{"old-installed", "old", "warm", "hot"} {
+		if err := SaveAssetToDisk(name, "1.0.0", testZip(t, name), ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Age the entries: old ones 60 days, warm 2 days, hot now
+	unlock, err := lockIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx, _ := loadIndex()
+	idx.Entries["old-installed@1.0.0"].LastUsed = time.Now().Add(-60 * 24 * time.Hour)
+	idx.Entries["old@1.0.0"].LastUsed = time.Now().Add(-60 * 24 * time.Hour)
+	idx.Entries["warm@1.0.0"].LastUsed = time.Now().Add(-48 * time.Hour)
+	_ = saveIndex(idx)
+	hotSize := idx.Entries["hot@1.0.0"].Size
+	unlock()
+
+	keep := map[string]bool{"old-installed@1.0.0": true}
+
+	// Dry run removes nothing
+	result, err := Prune(PrunePolicy{MaxAge: 30 * 24 * time.Hour, Keep: keep, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Assets) != 1 || result.Assets[0].Name != "old" {
+		t.Errorf("dry run: unexpected evictions %+v", result.Assets)
+	}
+	if entries, _ := ListAssets(); len(entries) != 4 {
+		t.Errorf("dry run removed entries: %d left", len(entries))
+	}
+
+	// Age, then size: warm is evicted before hot, the installed asset survives
+	result, err = Prune(PrunePolicy{MaxAge: 30 * 24 * time.Hour, MaxSize: hotSize, Keep: keep})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Assets) != 3 || result.Blobs != 3 {
+		t.Errorf("unexpected prune result: %+v", result)
+	}
+	entries, _ := ListAssets()
+	if len(entries) != 1 || entries[0].Name != "old-installed" {
+		t.Errorf("unexpected remaining entries: %+v", entries)
+	}
+}
@@ -0,0 +1,434 @@
+// Package clientconfig edits AI client config files (settings.json, mcp.json, hooks.json)
+// in place, preserving the user's formatting, key order, and comments.
+//
+// Every entry sx writes is recorded with a hash of its value, so an entry the user
+// has since edited by hand is detected instead of being silently replaced.
+package clientconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sleuth-io/sx/internal/journal"
+	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/utils"
+)
+
+const defaultIndent = "  "
+
+// ErrSkippedMalformed is returned by Update when the file is not valid JSONC.
+// The file is left untouched; callers fail only the asset that needed it.
+var ErrSkippedMalformed = errors.New("skipped malformed client config")
+
+// Document is a JSON or JSONC config file loaded for editing
+type Document struct {
+	path     string
+	src      []byte
+	root     *node
+	existed  bool
+	modified bool
+
+	// Ownership changes applied to the state on Save
+	claims   map[string]string
+	releases map[string]bool
+}
+
+// Load reads a config file for editing
+// A missing or empty file yields an empty object. A file that is not valid JSONC
+// returns a *ParseError.
+func Load(path string) (*Document, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	d := &Document{
+		path:     absPath,
+		claims:   make(map[string]string),
+		releases: make(map[string]bool),
+	}
+
+	data, err := os.ReadFile(absPath)
+	switch {
+	case err == nil:
+		d.existed = true
+	case os.IsNotExist(err):
+	default:
+		return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(path), err)
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		data = []byte("{}\n")
+	}
+
+	root, err := parse(data)
+	if err != nil {
+		return nil, parseError(absPath, data, err)
+	}
+	if root.kind != kindObject {
+		return nil, &ParseError{Path: absPath, Line: 1, Column: 1, Msg: "top-level value must be an object"}
+	}
+
+	d.src = data
+	d.root = root
+	return d, nil
+}
+
+// Update loads path, applies fn, and saves the result if fn changed anything
+// A malformed file is left untouched and Update returns an error wrapping both
+// ErrSkippedMalformed and the *ParseError
+func Update(path string, fn func(d *Document) error) error {
+	d, err := Load(path)
+	if err != nil {
+		var pe *ParseError
+		if errors.As(err, &pe) {
+			logger.Get().Warn("skipping malformed client config", "path", pe.Path, "error", pe)
+			return fmt.Errorf("%w: %w", ErrSkippedMalformed, pe)
+		}
+		return err
+	}
+
+	if err := fn(d); err != nil {
+		return err
+	}
+	return d.Save()
+}
+
+// Path returns the absolute path of the document
+func (d *Document) Path() string {
+	return d.path
+}
+
+// Bytes returns the current contents of the document
+func (d *Document) Bytes() []byte {
+	return d.src
+}
+
+// Get returns the value at path, decoded as with encoding/json
+func (d *Document) Get(path ...string) (interface{}, bool) {
+	n := d.lookup(path)
+	if n == nil {
+		return nil, false
+	}
+	v, err := decode(d.src[n.start:n.end])
+	if err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// Decode unmarshals the whole document into v
+func (d *Document) Decode(v interface{}) error {
+	return json.Unmarshal(standardize(d.src), v)
+}
+
+// Set writes value at path, creating intermediate objects as needed
+// Only the text of the affected value changes; the rest of the file is kept as is
+func (d *Document) Set(value interface{}, path ...string) error {
+	if len(path) == 0 {
+		return d.replace(d.root, value)
+	}
+
+	obj := d.root
+	for i, key := range path {
+		m := obj.find(key)
+		if m == nil {
+			return d.insert(obj, key, nest(path[i+1:], value))
+		}
+		if i == len(path)-1 {
+			if current, ok := d.Get(path...); ok && jsonEqual(current, value) {
+				return nil
+			}
+			return d.replace(m.value, value)
+		}
+		if m.value.kind != kindObject {
+			return d.replace(m.value, nest(path[i+1:], value))
+		}
+		obj = m.value
+	}
+	return nil
+}
+
+// Delete removes the member at path, returning false if it did not exist
+func (d *Document) Delete(path ...string) (bool, error) {
+	if len(path) == 0 {
+		return false, nil
+	}
+	parent := d.lookup(path[:len(path)-1])
+	if parent == nil || parent.kind != kindObject {
+		return false, nil
+	}
+
+	key := path[len(path)-1]
+	idx := -1
+	for i, m := range parent.members {
+		if m.key == key {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return false, nil
+	}
+
+	m := parent.members[idx]
+	err := d.edit(func() {
+		switch {
+		case len(parent.members) == 1:
+			d.splice(parent.start, parent.end, []byte("{}"))
+		case m.comma >= 0:
+			start, end := m.keyStart, m.comma+1
+			if ls := lineStart(d.src, start); onlySpace(d.src[ls:start]) {
+				if le := lineEnd(d.src, end); onlySpace(d.src[end:le]) {
+					start, end = ls, min(le+1, len(d.src))
+				}
+			}
+			d.splice(start, end, nil)
+		default:
+			// Last member: drop it and the comma that precedes it
+			prev := parent.members[idx-1]
+			start := m.keyStart
+			if ls := lineStart(d.src, start); ls > 0 && onlySpace(d.src[ls:start]) {
+				start = ls - 1
+			}
+			d.splice(start, m.value.end, nil)
+			d.splice(prev.comma, prev.comma+1, nil)
+		}
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Save writes the document if it was modified
+// The first time sx modifies a file, a timestamped backup of the original is kept
+func (d *Document) Save() error {
+	if !d.modified {
+		return commitOwnership(d)
+	}
+
+	if d.existed {
+		if err := backupOnce(d.path); err != nil {
+			return err
+		}
+	}
+
+	if err := journal.Track(d.path); err != nil {
+		return err
+	}
+	if err := utils.EnsureDir(filepath.Dir(d.path)); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(d.path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(d.path, d.src, mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(d.path), err)
+	}
+
+	d.existed = true
+	d.modified = false
+	return commitOwnership(d)
+}
+
+// lookup returns the node at path, or nil
+func (d *Document) lookup(path []string) *node {
+	n := d.root
+	for _, key := range path {
+		if n.kind != kindObject {
+			return nil
+		}
+		m := n.find(key)
+		if m == nil {
+			return nil
+		}
+		n = m.value
+	}
+	return n
+}
+
+// replace overwrites the text of n with value
+func (d *Document) replace(n *node, value interface{}) error {
+	text, err := encode(value, indentOf(d.src, n.start), d.indentUnit())
+	if err != nil {
+		return err
+	}
+	return d.edit(func() { d.splice(n.start, n.end, text) })
+}
+
+// insert adds key: value as the last member of obj
+func (d *Document) insert(obj *node, key string, value interface{}) error {
+	keyText, err := encode(key, "", "")
+	if err != nil {
+		return err
+	}
+
+	unit := d.indentUnit()
+	outer := indentOf(d.src, obj.start)
+
+	if len(obj.members) == 0 {
+		inner := outer + unit
+		valueText, err := encode(value, inner, unit)
+		if err != nil {
+			return err
+		}
+		entry := "\n" + inner + string(keyText) + ": " + string(valueText)
+		return d.edit(func() {
+			if onlySpace(d.src[obj.start+1 : obj.end-1]) {
+				d.splice(obj.start, obj.end, []byte("{"+entry+"\n"+outer+"}"))
+			} else {
+				d.splice(obj.start+1, obj.start+1, []byte(entry))
+			}
+		})
+	}
+
+	first := obj.members[0]
+	last := obj.members[len(obj.members)-1]
+
+	// Single-line objects stay single-line
+	if !bytes.Contains(d.src[obj.start:first.keyStart], []byte("\n")) {
+		valueText, err := encode(value, "", "")
+		if err != nil {
+			return err
+		}
+		entry := string(keyText) + ": " + string(valueText)
+		return d.edit(func() {
+			if last.comma >= 0 {
+				d.splice(last.comma+1, last.comma+1, []byte(" "+entry))
+			} else {
+				d.splice(last.value.end, last.value.end, []byte(", "+entry))
+			}
+		})
+	}
+
+	inner := indentOf(d.src, first.keyStart)
+	valueText, err := encode(value, inner, unit)
+	if err != nil {
+		return err
+	}
+	entry := "\n" + inner + string(keyText) + ": " + string(valueText)
+	return d.edit(func() {
+		if last.comma >= 0 {
+			// The file uses trailing commas; keep doing so
+			at := d.afterLineComments(last.comma + 1)
+			d.splice(at, at, []byte(entry+","))
+		} else {
+			at := d.afterLineComments(last.value.end)
+			d.splice(at, at, []byte(entry))
+			d.splice(last.value.end, last.value.end, []byte(","))
+		}
+	})
+}
+
+// afterLineComments returns the end of the line at offset if only whitespace and
+// comments follow, so inserted entries don't steal a comment from the previous line
+func (d *Document) afterLineComments(offset int) int {
+	le := lineEnd(d.src, offset)
+	p := &parser{src: d.src[offset:le]}
+	if err := p.skip(); err == nil && p.pos == len(p.src) {
+		return le
+	}
+	return offset
+}
+
+// splice replaces src[start:end] with text
+func (d *Document) splice(start, end int, text []byte) {
+	out := make([]byte, 0, len(d.src)-(end-start)+len(text))
+	out = append(out, d.src[:start]...)
+	out = append(out, text...)
+	out = append(out, d.src[end:]...)
+	d.src = out
+	d.modified = true
+}
+
+// edit applies the splices made by fn and rebuilds the node tree. Edits should
+// only ever produce valid JSONC; if one doesn't, it's undone and an error returned.
+func (d *Document) edit(fn func()) error {
+	src, modified := d.src, d.modified
+	fn()
+	root, err := parse(d.src)
+	if err != nil {
+		d.src, d.modified = src, modified
+		logger.Get().Error("client config edit produced invalid JSON", "path", d.path, "error", err)
+		return fmt.Errorf("failed to edit %s: edit produced invalid JSON: %w", filepath.Base(d.path), err)
+	}
+	d.root = root
+	return nil
+}
+
+// indentUnit detects the file's indentation, defaulting to two spaces
+func (d *Document) indentUnit() string {
+	for _, line := range strings.Split(string(d.src), "\n")[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" || trimmed == line {
+			continue
+		}
+		indent := line[:len(line)-len(trimmed)]
+		if strings.HasPrefix(indent, "\t") {
+			return "\t"
+		}
+		return indent
+	}
+	return defaultIndent
+}
+
+// encode marshals v with the given prefix for continuation lines
+func encode(v interface{}, prefix, unit string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if unit != "" {
+		enc.SetIndent(prefix, unit)
+	}
+	if err := enc.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to encode value: %w", err)
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// nest wraps value in objects for each key of path
+func nest(path []string, value interface{}) interface{} {
+	for i := len(path) - 1; i >= 0; i-- {
+		value = map[string]interface{}{path[i]: value}
+	}
+	return value
+}
+
+// jsonEqual compares two values by their JSON encoding
+func jsonEqual(a, b interface{}) bool {
+	ha, errA := hashValue(a)
+	hb, errB := hashValue(b)
+	return errA == nil && errB == nil && ha == hb
+}
+
+// indentOf returns the leading whitespace of the line containing offset
+func indentOf(src []byte, offset int) string {
+	ls := lineStart(src, offset)
+	end := ls
+	for end < len(src) && (src[end] == ' ' || src[end] == '\t') {
+		end++
+	}
+	return string(src[ls:end])
+}
+
+func lineStart(src []byte, offset int) int {
+	return bytes.LastIndexByte(src[:offset], '\n') + 1
+}
+
+func lineEnd(src []byte, offset int) int {
+	if i := bytes.IndexByte(src[offset:], '\n'); i >= 0 {
+		return offset + i
+	}
+	return len(src)
+}
+
+func onlySpace(b []byte) bool {
+	return len(bytes.TrimSpace(b)) == 0
+}
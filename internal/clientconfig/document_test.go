@@ -0,0 +1,224 @@
+package clientconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mcp.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestSetPreservesFormattingAndComments(t *testing.T) {
+	t.Setenv("SX_CACHE_DIR", t.TempDir())
+	path := writeConfig(t, `{
+    // Servers I added myself
+    "mcpServers": {
+        "zeta": {"command": "zeta"}, /* keep me */
+    },
+    "alpha": true
+}
+`)
+
+	d, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if err := d.Set(map[string]interface{}{"command": "sx"}, "mcpServers", "github"); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	if err := d.Set("x", "newSection", "key"); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	if err := d.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	want := `{
+    // Servers I added myself
+    "mcpServers": {
+        "zeta": {"command": "zeta"}, /* keep me */
+        "github": {
+            "command": "sx"
+        },
+    },
+    "alpha": true,
+    "newSection": {
+        "key": "x"
+    }
+}
+`
+	if string(data) != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", data, want)
+	}
+
+	// The original was backed up before the first modification
+	backup, ok := BackupPath(path)
+	if !ok {
+		t.Fatal("expected a backup to be recorded")
+	}
+	original, _ := os.ReadFile(backup)
+	if !strings.Contains(string(original), `"alpha": true`) || strings.Contains(string(original), "github") {
+		t.Errorf("backup does not hold the original file:\n%s", original)
+	}
+}
+
+func TestDeleteKeepsNeighbours(t *testing.T) {
+	t.Setenv("SX_CACHE_DIR", t.TempDir())
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "middle member",
+			in:   "{\n  \"a\": 1,\n  \"b\": 2,\n  \"c\": 3\n}\n",
+			want: "{\n  \"a\": 1,\n  \"c\": 3\n}\n",
+		},
+		{
+			name: "last member",
+			in:   "{\n  \"a\": 1, // note\n  \"b\": 2\n}\n",
+			want: "{\n  \"a\": 1 // note\n}\n",
+		},
+		{
+			name: "only member",
+			in:   "{\n  \"b\": 2\n}\n",
+			want: "{}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Load(writeConfig(t, tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if deleted, err := d.Delete("b"); err != nil || !deleted {
+				t.Fatalf("Delete() = %v, %v", deleted, err)
+			}
+			if got := string(d.Bytes()); got != tt.want {
+				t.Errorf("got:\n%q\nwant:\n%q", got, tt.want)
+			}
+			if deleted, _ := d.Delete("missing"); deleted {
+				t.Error("Delete() of missing key returned true")
+			}
+		})
+	}
+}
+
+func TestInvalidEditIsUndone(t *testing.T) {
+	in := "{\n  \"a\": 1\n}\n"
+	d, err := Load(writeConfig(t, in))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.edit(func() { d.splice(0, 1, nil) }); err == nil {
+		t.Fatal("expected an error for an edit that breaks the JSON")
+	}
+	if got := string(d.Bytes()); got != in {
+		t.Errorf("document not restored, got:\n%q", got)
+	}
+	if err := d.Set(2, "a"); err != nil {
+		t.Fatalf("document unusable after a failed edit: %v", err)
+	}
+}
+
+func TestLoadReportsMalformedFiles(t *testing.T) {
+	t.Setenv("SX_CACHE_DIR", t.TempDir())
+	path := writeConfig(t, "{\n  \"a\": 1\n  \"b\": 2\n}")
+
+	_, err := Load(path)
+	pe, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("expected *ParseError, got %v", err)
+	}
+	if pe.Line != 3 {
+		t.Errorf("Line = %d, want 3", pe.Line)
+	}
+
+	// Update leaves the broken file alone and reports it as skipped
+	before, _ := os.ReadFile(path)
+	called := false
+	err = Update(path, func(d *Document) error { called = true; return nil })
+	if !errors.Is(err, ErrSkippedMalformed) || !errors.As(err, &pe) {
+		t.Fatalf("Update() error = %v, want ErrSkippedMalformed wrapping *ParseError", err)
+	}
+	if called {
+		t.Error("fn should not be called for a malformed file")
+	}
+	if after, _ := os.ReadFile(path); string(after) != string(before) {
+		t.Error("malformed file should be left untouched")
+	}
+}
+
+func TestSetOwnedDetectsUserEdits(t *testing.T) {
+	t.Setenv("SX_CACHE_DIR", t.TempDir())
+	path := writeConfig(t, "{}")
+
+	install := func(value map[string]interface{}) bool {
+		t.Helper()
+		var applied bool
+		err := Update(path, func(d *Document) error {
+			var err error
+			applied, err = d.SetOwned(value, "mcpServers", "github")
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Update() error: %v", err)
+		}
+		return applied
+	}
+
+	if !install(map[string]interface{}{"command": "v1", "args": []string{"a"}}) {
+		t.Fatal("first install should apply")
+	}
+
+	// Unchanged entries are upgraded silently
+	if !install(map[string]interface{}{"command": "v2", "args": []string{"a"}}) {
+		t.Fatal("upgrade of unedited entry should apply")
+	}
+
+	// Simulate the user editing the entry
+	d, _ := Load(path)
+	if err := d.Set("my-own-command", "mcpServers", "github", "command"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a resolver the user's edit is kept
+	if install(map[string]interface{}{"command": "v3"}) {
+		t.Error("user edit should have been kept")
+	}
+	d, _ = Load(path)
+	if v, _ := d.Get("mcpServers", "github", "command"); v != "my-own-command" {
+		t.Errorf("command = %v, want my-own-command", v)
+	}
+
+	// The resolver can choose to overwrite
+	var seen []Conflict
+	SetConflictResolver(func(c Conflict) bool { seen = append(seen, c); return true })
+	defer SetConflictResolver(nil)
+
+	if !install(map[string]interface{}{"command": "v3"}) {
+		t.Error("resolver chose overwrite")
+	}
+	if len(seen) != 1 || seen[0].Entry != "mcpServers/github" {
+		t.Errorf("unexpected conflicts: %+v", seen)
+	}
+	d, _ = Load(path)
+	if v, _ := d.Get("mcpServers", "github", "command"); v != "v3" {
+		t.Errorf("command = %v, want v3", v)
+	}
+}
@@ -0,0 +1,346 @@
+package clientconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// kind is the type of a parsed JSON value
+type kind int
+
+const (
+	kindObject kind = iota
+	kindArray
+	kindScalar
+)
+
+// node is a JSON value with its byte span in the source
+type node struct {
+	kind       kind
+	start, end int // Value span, end exclusive
+	members    []*member
+	elems      []*node
+}
+
+// member is a key/value pair of an object
+type member struct {
+	key      string
+	keyStart int
+	value    *node
+	comma    int // Offset of the comma following the value, -1 if none
+}
+
+// find returns the member for key (the last one wins, as with encoding/json)
+func (n *node) find(key string) *member {
+	var found *member
+	for _, m := range n.members {
+		if m.key == key {
+			found = m
+		}
+	}
+	return found
+}
+
+// ParseError reports a config file that is not valid JSON or JSONC
+type ParseError struct {
+	Path   string
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s is not valid JSON (line %d, column %d): %s", e.Path, e.Line, e.Column, e.Msg)
+}
+
+// parser is a JSONC parser that keeps byte offsets so values can be edited in place
+// Comments (// and /* */) and trailing commas are accepted, as in VS Code and Cursor
+type parser struct {
+	src []byte
+	pos int
+}
+
+// parse parses src into a node tree
+func parse(src []byte) (*node, error) {
+	p := &parser{src: src}
+	if err := p.skip(); err != nil {
+		return nil, err
+	}
+	root, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.skip(); err != nil {
+		return nil, err
+	}
+	if p.pos < len(src) {
+		return nil, p.errorf("unexpected %q after top-level value", src[p.pos])
+	}
+	return root, nil
+}
+
+// parseError converts a parser error into a ParseError for path
+func parseError(path string, src []byte, err error) error {
+	pe, ok := err.(*syntaxError)
+	if !ok {
+		return err
+	}
+	line, col := 1, 1
+	for _, c := range src[:pe.offset] {
+		if c == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return &ParseError{Path: path, Line: line, Column: col, Msg: pe.msg}
+}
+
+type syntaxError struct {
+	offset int
+	msg    string
+}
+
+func (e *syntaxError) Error() string {
+	return fmt.Sprintf("offset %d: %s", e.offset, e.msg)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &syntaxError{offset: p.pos, msg: fmt.Sprintf(format, args...)}
+}
+
+// skip advances past whitespace and comments
+func (p *parser) skip() error {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			p.pos++
+		case c == '/' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '/':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		case c == '/' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '*':
+			end := bytes.Index(p.src[p.pos+2:], []byte("*/"))
+			if end < 0 {
+				return p.errorf("unterminated comment")
+			}
+			p.pos += end + 4
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (p *parser) value() (*node, error) {
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of input")
+	}
+	switch c := p.src[p.pos]; c {
+	case '{':
+		return p.object()
+	case '[':
+		return p.array()
+	case '"':
+		start := p.pos
+		if err := p.str(); err != nil {
+			return nil, err
+		}
+		return &node{kind: kindScalar, start: start, end: p.pos}, nil
+	default:
+		return p.literal()
+	}
+}
+
+func (p *parser) object() (*node, error) {
+	n := &node{kind: kindObject, start: p.pos}
+	p.pos++ // {
+
+	for {
+		if err := p.skip(); err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated object")
+		}
+		if p.src[p.pos] == '}' {
+			p.pos++
+			n.end = p.pos
+			return n, nil
+		}
+		if len(n.members) > 0 && n.members[len(n.members)-1].comma < 0 {
+			return nil, p.errorf("expected ',' or '}'")
+		}
+		if p.src[p.pos] != '"' {
+			return nil, p.errorf("expected string key")
+		}
+
+		m := &member{keyStart: p.pos, comma: -1}
+		if err := p.str(); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(p.src[m.keyStart:p.pos], &m.key); err != nil {
+			return nil, &syntaxError{offset: m.keyStart, msg: "invalid key"}
+		}
+
+		if err := p.skip(); err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.src) || p.src[p.pos] != ':' {
+			return nil, p.errorf("expected ':' after key")
+		}
+		p.pos++
+		if err := p.skip(); err != nil {
+			return nil, err
+		}
+
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		m.value = v
+		n.members = append(n.members, m)
+
+		if err := p.skip(); err != nil {
+			return nil, err
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == ',' {
+			m.comma = p.pos
+			p.pos++
+		}
+	}
+}
+
+func (p *parser) array() (*node, error) {
+	n := &node{kind: kindArray, start: p.pos}
+	p.pos++ // [
+	needComma := false
+
+	for {
+		if err := p.skip(); err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.src[p.pos] == ']' {
+			p.pos++
+			n.end = p.pos
+			return n, nil
+		}
+		if needComma {
+			return nil, p.errorf("expected ',' or ']'")
+		}
+
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		n.elems = append(n.elems, v)
+
+		if err := p.skip(); err != nil {
+			return nil, err
+		}
+		needComma = true
+		if p.pos < len(p.src) && p.src[p.pos] == ',' {
+			needComma = false
+			p.pos++
+		}
+	}
+}
+
+// str advances past a string literal
+func (p *parser) str() error {
+	start := p.pos
+	p.pos++ // opening quote
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos += 2
+		case '"':
+			p.pos++
+			return nil
+		case '\n':
+			return &syntaxError{offset: start, msg: "unterminated string"}
+		default:
+			p.pos++
+		}
+	}
+	return &syntaxError{offset: start, msg: "unterminated string"}
+}
+
+// literal parses a number, true, false, or null
+func (p *parser) literal() (*node, error) {
+	start := p.pos
+	for p.pos < len(p.src) && isLiteralByte(p.src[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos || !json.Valid(p.src[start:p.pos]) {
+		p.pos = start
+		return nil, p.errorf("invalid value")
+	}
+	return &node{kind: kindScalar, start: start, end: p.pos}, nil
+}
+
+func isLiteralByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '+' || c == '.'
+}
+
+// decode unmarshals a JSONC fragment by stripping comments and trailing commas first
+func decode(src []byte) (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal(standardize(src), &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// standardize converts JSONC to plain JSON
+func standardize(src []byte) []byte {
+	out := make([]byte, 0, len(src))
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '"':
+			j := i + 1
+			for j < len(src) && src[j] != '"' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				j = len(src) - 1
+			}
+			out = append(out, src[i:j+1]...)
+			i = j
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			for i+1 < len(src) && src[i+1] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := bytes.Index(src[i+2:], []byte("*/"))
+			if end < 0 {
+				return out
+			}
+			i += end + 3
+			out = append(out, ' ')
+		case c == ',' && closesNext(src[i+1:]):
+			// Drop trailing comma
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// closesNext reports whether the next significant byte closes an object or array
+func closesNext(src []byte) bool {
+	p := &parser{src: src}
+	if err := p.skip(); err != nil || p.pos >= len(src) {
+		return false
+	}
+	return src[p.pos] == '}' || src[p.pos] == ']'
+}
@@ -0,0 +1,363 @@
+package clientconfig
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sleuth-io/sx/internal/cache"
+	"github.com/sleuth-io/sx/internal/journal"
+	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/utils"
+)
+
+const (
+	stateFile  = "client-config.json"
+	backupsDir = "config-backups"
+)
+
+// state records which config entries sx owns and where original files were backed up
+type state struct {
+	// Backups maps a config file to the backup taken before sx first modified it
+	Backups map[string]string `json:"backups"`
+	// Entries maps a config file to its sx-managed entries and the hash of the value sx wrote
+	Entries map[string]map[string]string `json:"entries"`
+}
+
+// Conflict describes an sx-managed entry that the user has edited by hand
+type Conflict struct {
+	File     string
+	Entry    string
+	Current  interface{}
+	Proposed interface{} // nil when sx wants to remove the entry
+}
+
+// ConflictResolver decides whether to overwrite a user-edited entry
+// Returning false keeps the user's version
+type ConflictResolver func(c Conflict) bool
+
+var (
+	stateMu sync.Mutex
+
+	// promptMu serializes prompts from clients installing concurrently
+	promptMu    sync.Mutex
+	resolver    ConflictResolver
+	warnHandler func(msg string)
+)
+
+// SetConflictResolver sets how conflicts with user edits are resolved
+// With no resolver (the default, e.g. in hook mode) user edits are always kept
+func SetConflictResolver(r ConflictResolver) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	resolver = r
+}
+
+// SetWarningHandler sets where user-facing warnings (skipped files, kept edits) are shown
+// Warnings are always logged as well
+func SetWarningHandler(fn func(msg string)) {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	warnHandler = fn
+}
+
+// warn reports a warning to the user if a handler is set
+func warn(msg string) {
+	promptMu.Lock()
+	fn := warnHandler
+	promptMu.Unlock()
+	if fn != nil {
+		fn(msg)
+	}
+}
+
+// SetOwned writes value at path as an sx-managed entry
+// If the user edited the entry since sx last wrote it, the conflict resolver decides
+// whether to overwrite it. Returns false if the user's version was kept.
+func (d *Document) SetOwned(value interface{}, path ...string) (bool, error) {
+	key := EntryKey(path...)
+	if current, ok := d.Get(path...); ok && !d.MayReplace(key, current, value) {
+		return false, nil
+	}
+	if err := d.Set(value, path...); err != nil {
+		return false, err
+	}
+	d.Claim(key, value)
+	return true, nil
+}
+
+// DeleteOwned removes the sx-managed entry at path
+// A user-edited entry is only removed if the conflict resolver agrees; either way
+// sx stops managing it. Returns false if nothing was removed.
+func (d *Document) DeleteOwned(path ...string) (bool, error) {
+	key := EntryKey(path...)
+	defer d.Release(key)
+
+	current, ok := d.Get(path...)
+	if !ok || !d.MayReplace(key, current, nil) {
+		return false, nil
+	}
+	return d.Delete(path...)
+}
+
+// MayReplace reports whether sx may replace (or remove, if proposed is nil) the entry
+// identified by key whose current value is current
+// Entries sx has no record of, or that are unchanged since sx wrote them, may be replaced.
+func (d *Document) MayReplace(key string, current, proposed interface{}) bool {
+	recorded, ok := d.recordedHash(key)
+	if !ok {
+		return true
+	}
+	if h, err := hashValue(current); err == nil && h == recorded {
+		return true
+	}
+	if proposed != nil && jsonEqual(current, proposed) {
+		return true
+	}
+
+	c := Conflict{File: d.path, Entry: key, Current: current, Proposed: proposed}
+	log := logger.Get()
+
+	promptMu.Lock()
+	r := resolver
+	overwrite := false
+	if r != nil {
+		overwrite = r(c)
+	}
+	promptMu.Unlock()
+
+	if r != nil {
+		log.Info("resolved edited client config entry", "file", d.path, "entry", key, "overwrite", overwrite)
+		return overwrite
+	}
+
+	warn(fmt.Sprintf("Keeping your edits to %s in %s", key, d.path))
+	log.Warn("kept user-edited client config entry", "file", d.path, "entry", key)
+	return false
+}
+
+// Claim records key as an sx-managed entry holding value
+func (d *Document) Claim(key string, value interface{}) {
+	h, err := hashValue(value)
+	if err != nil {
+		return
+	}
+	d.claims[key] = h
+	delete(d.releases, key)
+}
+
+// Release stops tracking key as an sx-managed entry
+func (d *Document) Release(key string) {
+	d.releases[key] = true
+	delete(d.claims, key)
+}
+
+// EntryKey builds the ownership key for an entry at path
+func EntryKey(path ...string) string {
+	return strings.Join(path, "/")
+}
+
+// BackupPath returns the backup taken before sx first modified path, if any
+func BackupPath(path string) (string, bool) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	s, err := loadState()
+	if err != nil {
+		return "", false
+	}
+	backup, ok := s.Backups[absPath]
+	return backup, ok
+}
+
+// recordedHash returns the hash of the value sx last wrote for key
+func (d *Document) recordedHash(key string) (string, bool) {
+	if d.releases[key] {
+		return "", false
+	}
+	if h, ok := d.claims[key]; ok {
+		return h, true
+	}
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	s, err := loadState()
+	if err != nil {
+		logger.Get().Warn("failed to load client config state", "error", err)
+		return "", false
+	}
+	h, ok := s.Entries[d.path][key]
+	return h, ok
+}
+
+// commitOwnership persists the document's claims and releases
+func commitOwnership(d *Document) error {
+	if len(d.claims) == 0 && len(d.releases) == 0 {
+		return nil
+	}
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	s, err := loadState()
+	if err != nil {
+		return err
+	}
+
+	entries := s.Entries[d.path]
+	if entries == nil {
+		entries = make(map[string]string)
+	}
+	for key := range d.releases {
+		delete(entries, key)
+	}
+	for key, h := range d.claims {
+		entries[key] = h
+	}
+	if len(entries) == 0 {
+		delete(s.Entries, d.path)
+	} else {
+		s.Entries[d.path] = entries
+	}
+
+	if err := saveState(s); err != nil {
+		return err
+	}
+
+	d.claims = make(map[string]string)
+	d.releases = make(map[string]bool)
+	return nil
+}
+
+// backupOnce copies path into the backup directory the first time sx modifies it
+func backupOnce(path string) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	s, err := loadState()
+	if err != nil {
+		return err
+	}
+	if backup, ok := s.Backups[path]; ok && utils.FileExists(backup) {
+		return nil
+	}
+
+	cacheDir, err := cache.GetCacheDir()
+	if err != nil {
+		return fmt.Errorf("failed to get cache directory: %w", err)
+	}
+	dir := filepath.Join(cacheDir, backupsDir)
+	if err := utils.EnsureDir(dir); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s for backup: %w", filepath.Base(path), err)
+	}
+
+	sum := sha256.Sum256([]byte(path))
+	name := fmt.Sprintf("%s-%s-%s", time.Now().Format("20060102-150405"), hex.EncodeToString(sum[:4]), filepath.Base(path))
+	backup := filepath.Join(dir, name)
+	if err := os.WriteFile(backup, data, 0600); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	logger.Get().Info("backed up client config", "path", path, "backup", backup)
+	s.Backups[path] = backup
+	return saveState(s)
+}
+
+// getStatePath returns the path of the ownership state file
+func getStatePath() (string, error) {
+	cacheDir, err := cache.GetCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get cache directory: %w", err)
+	}
+	return filepath.Join(cacheDir, stateFile), nil
+}
+
+// loadState reads the ownership state; callers must hold stateMu
+func loadState() (*state, error) {
+	s := &state{
+		Backups: make(map[string]string),
+		Entries: make(map[string]map[string]string),
+	}
+
+	path, err := getStatePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read client config state: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse client config state: %w", err)
+	}
+	if s.Backups == nil {
+		s.Backups = make(map[string]string)
+	}
+	if s.Entries == nil {
+		s.Entries = make(map[string]map[string]string)
+	}
+	return s, nil
+}
+
+// saveState writes the ownership state; callers must hold stateMu
+// The state is journaled with the config files so a rollback keeps them in sync
+func saveState(s *state) error {
+	path, err := getStatePath()
+	if err != nil {
+		return err
+	}
+	if err := journal.Track(path); err != nil {
+		return err
+	}
+	if err := utils.EnsureDir(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal client config state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write client config state: %w", err)
+	}
+	return nil
+}
+
+// hashValue hashes the canonical JSON encoding of v
+func hashValue(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	// Round-trip so typed values ([]string, int) hash the same as decoded ones
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return "", err
+	}
+	data, err = json.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/sleuth-io/sx/internal/clients/claude_code/handlers"
 	"github.com/sleuth-io/sx/internal/handlers/dirasset"
 	"github.com/sleuth-io/sx/internal/lockfile"
+	"github.com/sleuth-io/sx/internal/logger"
 	"github.com/sleuth-io/sx/internal/metadata"
 )
 
@@ -70,6 +71,25 @@ func (c *Client) InstallAssets(ctx context.Context, req clients.InstallRequest)
 		return resp, fmt.Errorf("cannot determine installation directory: %w", err)
 	}
 
+	// In plugin mode assets go into the scope's plugin. Each asset is removed
+	// from the other layout, so switching modes doesn't leave copies behind.
+	otherBase := c.pluginBaseIfExists(req.Scope)
+	if usePlugin(req.Scope) {
+		otherBase = targetBase
+		if targetBase, err = ensurePlugin(req.Scope); err != nil {
+			// Report every asset, so none of them is recorded as installed
+			for _, bundle := range req.Assets {
+				resp.Results = append(resp.Results, clients.AssetResult{
+					AssetName: bundle.Asset.Name,
+					Status:    clients.StatusFailed,
+					Error:     err,
+					Message:   fmt.Sprintf("Installation failed: %v", err),
+				})
+			}
+			return resp, err
+		}
+	}
+
 	// Ensure target directory exists
 	if err := os.MkdirAll(targetBase, 0755); err != nil {
 		return resp, fmt.Errorf("failed to create target directory: %w", err)
@@ -81,6 +101,7 @@ func (c *Client) InstallAssets(ctx context.Context, req clients.InstallRequest)
 			AssetName: bundle.Asset.Name,
 		}
 
+		ctx, warnings := clients.WithWarnings(ctx)
 		var err error
 		switch bundle.Metadata.Asset.Type {
 		case asset.TypeSkill:
@@ -105,6 +126,7 @@ func (c *Client) InstallAssets(ctx context.Context, req clients.InstallRequest)
 			err = fmt.Errorf("unsupported asset type: %s", bundle.Metadata.Asset.Type.Key)
 		}
 
+		result.Warnings = warnings()
 		if err != nil {
 			result.Status = clients.StatusFailed
 			result.Error = err
@@ -112,11 +134,18 @@ func (c *Client) InstallAssets(ctx context.Context, req clients.InstallRequest)
 		} else {
 			result.Status = clients.StatusSuccess
 			result.Message = fmt.Sprintf("Installed to %s", targetBase)
+			if otherBase != "" {
+				c.removeFrom(ctx, bundle.Metadata, otherBase)
+			}
 		}
 
 		resp.Results = append(resp.Results, result)
 	}
 
+	if err := finishPlugin(req.Scope); err != nil {
+		return resp, fmt.Errorf("failed to update plugin: %w", err)
+	}
+
 	return resp, nil
 }
 
@@ -130,6 +159,7 @@ func (c *Client) UninstallAssets(ctx context.Context, req clients.UninstallReque
 	if err != nil {
 		return resp, fmt.Errorf("cannot determine uninstall directory: %w", err)
 	}
+	pluginBase := c.pluginBaseIfExists(req.Scope)
 
 	for _, a := range req.Assets {
 		result := clients.AssetResult{
@@ -144,6 +174,12 @@ func (c *Client) UninstallAssets(ctx context.Context, req clients.UninstallReque
 			},
 		}
 
+		// The asset may be in the scope's plugin, the .claude directory, or both
+		// while switching modes
+		if pluginBase != "" {
+			c.removeFrom(ctx, meta, pluginBase)
+		}
+
 		var err error
 		switch a.Type {
 		case asset.TypeSkill:
@@ -179,9 +215,47 @@ func (c *Client) UninstallAssets(ctx context.Context, req clients.UninstallReque
 		resp.Results = append(resp.Results, result)
 	}
 
+	if err := finishPlugin(req.Scope); err != nil {
+		return resp, fmt.Errorf("failed to update plugin: %w", err)
+	}
+
 	return resp, nil
 }
 
+// assetBase returns where assets for a scope are installed: the scope's sx
+// plugin in plugin mode, otherwise its .claude directory
+func (c *Client) assetBase(scope *clients.InstallScope) (string, error) {
+	if usePlugin(scope) {
+		return pluginDir(scope)
+	}
+	return c.determineTargetBase(scope)
+}
+
+// pluginBaseIfExists returns the scope's plugin directory if it has one
+func (c *Client) pluginBaseIfExists(scope *clients.InstallScope) string {
+	if !pluginScope(scope) {
+		return ""
+	}
+	dir, err := pluginDir(scope)
+	if err != nil || !handlers.IsPluginDir(dir) {
+		return ""
+	}
+	return dir
+}
+
+// removeFrom removes an asset from a target base it may have been installed to
+// earlier. Failures are only logged since the asset is being installed or
+// removed elsewhere anyway.
+func (c *Client) removeFrom(ctx context.Context, meta *metadata.Metadata, targetBase string) {
+	handler, err := handlers.NewHandler(meta.Asset.Type, meta)
+	if err != nil {
+		return
+	}
+	if err := handler.Remove(ctx, targetBase); err != nil {
+		logger.Get().Warn("failed to remove asset from previous location", "name", meta.Asset.Name, "path", targetBase, "error", err)
+	}
+}
+
 // determineTargetBase returns the installation directory based on scope
 // Returns an error if a repo/path-scoped install is requested without a valid RepoRoot
 func (c *Client) determineTargetBase(scope *clients.InstallScope) (string, error) {
@@ -207,7 +281,7 @@ func (c *Client) determineTargetBase(scope *clients.InstallScope) (string, error
 
 // ListAssets returns all installed skills for a given scope
 func (c *Client) ListAssets(ctx context.Context, scope *clients.InstallScope) ([]clients.InstalledSkill, error) {
-	targetBase, err := c.determineTargetBase(scope)
+	targetBase, err := c.assetBase(scope)
 	if err != nil {
 		return nil, fmt.Errorf("cannot determine target directory: %w", err)
 	}
@@ -232,7 +306,7 @@ func (c *Client) ListAssets(ctx context.Context, scope *clients.InstallScope) ([
 
 // ReadSkill reads the content of a specific skill by name
 func (c *Client) ReadSkill(ctx context.Context, name string, scope *clients.InstallScope) (*clients.SkillContent, error) {
-	targetBase, err := c.determineTargetBase(scope)
+	targetBase, err := c.assetBase(scope)
 	if err != nil {
 		return nil, fmt.Errorf("cannot determine target directory: %w", err)
 	}
@@ -279,7 +353,7 @@ func (c *Client) ShouldInstall(ctx context.Context) (bool, error) {
 func (c *Client) VerifyAssets(ctx context.Context, assets []*lockfile.Asset, scope *clients.InstallScope) []clients.VerifyResult {
 	results := make([]clients.VerifyResult, 0, len(assets))
 
-	targetBase, err := c.determineTargetBase(scope)
+	targetBase, err := c.assetBase(scope)
 	if err != nil {
 		// Can't determine target - mark all assets as not installed
 		for _, a := range assets {
@@ -319,7 +393,7 @@ func (c *Client) VerifyAssets(ctx context.Context, assets []*lockfile.Asset, sco
 // ScanInstalledAssets scans for unmanaged assets (those without metadata.toml)
 // Assets with metadata.toml were installed by sx and are already managed.
 func (c *Client) ScanInstalledAssets(ctx context.Context, scope *clients.InstallScope) ([]clients.InstalledAsset, error) {
-	targetBase, err := c.determineTargetBase(scope)
+	targetBase, err := c.assetBase(scope)
 	if err != nil {
 		return nil, fmt.Errorf("cannot determine target directory: %w", err)
 	}
@@ -446,7 +520,7 @@ func scanUnmanagedAgentFiles(targetBase string) ([]clients.InstalledAsset, error
 
 // GetAssetPath returns the filesystem path to an installed asset
 func (c *Client) GetAssetPath(ctx context.Context, name string, assetType asset.Type, scope *clients.InstallScope) (string, error) {
-	targetBase, err := c.determineTargetBase(scope)
+	targetBase, err := c.assetBase(scope)
 	if err != nil {
 		return "", fmt.Errorf("cannot determine target directory: %w", err)
 	}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/sleuth-io/sx/internal/asset"
 	"github.com/sleuth-io/sx/internal/handlers/fileasset"
@@ -96,8 +97,29 @@ func (h *AgentHandler) Install(ctx context.Context, zipData []byte, targetBase s
 
 	// Get the prompt file from metadata
 	promptFile := h.metadata.Agent.PromptFile
+	promptData, err := utils.ReadZipFile(zipData, promptFile)
+	if err != nil {
+		return fmt.Errorf("failed to read prompt file from zip: %w", err)
+	}
+	promptData, err = renderPrompt(ctx, h.metadata.Asset.Name, promptFile, promptData, h.frontmatter())
+	if err != nil {
+		return err
+	}
 
-	return agentOps.Install(ctx, zipData, targetBase, h.metadata.Asset.Name, promptFile)
+	return agentOps.InstallPrompt(ctx, zipData, targetBase, h.metadata.Asset.Name, promptData)
+}
+
+// frontmatter returns the subagent frontmatter from metadata. Claude Code needs
+// a name and description, so they're filled in from [asset] when the prompt lacks them.
+func (h *AgentHandler) frontmatter() []fileasset.Field {
+	agent := h.metadata.Agent
+	return []fileasset.Field{
+		{Key: "name", Value: h.metadata.Asset.Name, IfMissing: true},
+		{Key: "description", Value: h.metadata.Asset.Description, IfMissing: true},
+		{Key: "tools", Value: strings.Join(agent.Tools, ", ")},
+		{Key: "model", Value: agent.Model},
+		{Key: "color", Value: agent.Color},
+	}
 }
 
 // Remove uninstalls the agent asset
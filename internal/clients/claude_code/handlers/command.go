@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/sleuth-io/sx/internal/asset"
@@ -102,12 +103,49 @@ func (h *CommandHandler) Install(ctx context.Context, zipData []byte, targetBase
 
 	// Get the prompt file from metadata
 	promptFile := h.metadata.Command.PromptFile
+	promptData, err := utils.ReadZipFile(zipData, promptFile)
+	if err != nil {
+		return fmt.Errorf("failed to read prompt file from zip: %w", err)
+	}
+	promptData, err = renderPrompt(ctx, h.metadata.Asset.Name, promptFile, promptData, h.frontmatter())
+	if err != nil {
+		return err
+	}
+	if h.metadata.Command.Dangerous {
+		promptData = fileasset.WithDangerousPreamble(promptData)
+	}
+
+	name := h.metadata.Asset.Name
+	if err := commandOps.InstallPrompt(ctx, zipData, targetBase, name, promptData); err != nil {
+		return err
+	}
+
+	// Aliases are extra entry points to the same prompt
+	commandsDir := filepath.Join(targetBase, "commands")
+	if err := fileasset.RemoveAliases(commandsDir, name); err != nil {
+		return err
+	}
+	return fileasset.InstallAliases(ctx, commandsDir, name, commandOps.GetAssetPath(targetBase, name), h.metadata.Command.Aliases)
+}
 
-	return commandOps.Install(ctx, zipData, targetBase, h.metadata.Asset.Name, promptFile)
+// frontmatter returns the slash command frontmatter from metadata, describing
+// the command with [asset] description unless it has its own
+func (h *CommandHandler) frontmatter() []fileasset.Field {
+	command := h.metadata.Command
+	return []fileasset.Field{
+		{Key: "description", Value: command.Description},
+		{Key: "description", Value: h.metadata.Asset.Description, IfMissing: true},
+		{Key: "argument-hint", Value: command.ArgumentHint},
+		{Key: "allowed-tools", Value: strings.Join(command.AllowedTools, ", ")},
+		{Key: "model", Value: command.Model},
+	}
 }
 
-// Remove uninstalls the command asset
+// Remove uninstalls the command asset and its aliases
 func (h *CommandHandler) Remove(ctx context.Context, targetBase string) error {
+	if err := fileasset.RemoveAliases(filepath.Join(targetBase, "commands"), h.metadata.Asset.Name); err != nil {
+		return err
+	}
 	return commandOps.Remove(ctx, targetBase, h.metadata.Asset.Name)
 }
 
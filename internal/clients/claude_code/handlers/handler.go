@@ -2,10 +2,16 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
 
 	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/clients"
+	"github.com/sleuth-io/sx/internal/handlers/fileasset"
+	"github.com/sleuth-io/sx/internal/logger"
 	"github.com/sleuth-io/sx/internal/metadata"
+	"github.com/sleuth-io/sx/internal/utils"
 )
 
 // Handler defines the interface for asset type handlers
@@ -46,3 +52,25 @@ func NewHandler(assetType asset.Type, meta *metadata.Metadata) (Handler, error)
 		return nil, fmt.Errorf("unsupported asset type: %s", assetType.Key)
 	}
 }
+
+// IsPluginDir reports whether targetBase is a Claude Code plugin managed by sx
+// rather than a .claude directory
+func IsPluginDir(targetBase string) bool {
+	return utils.FileExists(filepath.Join(targetBase, ".claude-plugin", "plugin.json"))
+}
+
+// renderPrompt sets frontmatter fields from metadata in an asset's prompt. A
+// prompt whose frontmatter isn't valid YAML is installed as is, since Claude Code
+// reads frontmatter more leniently than sx can rewrite it.
+func renderPrompt(ctx context.Context, name, promptFile string, prompt []byte, fields []fileasset.Field) ([]byte, error) {
+	rendered, err := fileasset.SetFrontmatter(prompt, fields)
+	if errors.Is(err, fileasset.ErrInvalidFrontmatter) {
+		clients.Warn(ctx, fmt.Sprintf("Installing %s without its metadata settings because the frontmatter in %s isn't valid YAML", name, promptFile))
+		logger.Get().Warn("frontmatter not updated", "name", name, "file", promptFile, "error", err)
+		return prompt, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", promptFile, err)
+	}
+	return rendered, nil
+}
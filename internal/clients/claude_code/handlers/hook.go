@@ -2,12 +2,11 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/clientconfig"
 	"github.com/sleuth-io/sx/internal/handlers/dirasset"
 	"github.com/sleuth-io/sx/internal/metadata"
 	"github.com/sleuth-io/sx/internal/utils"
@@ -173,137 +172,98 @@ func (h *HookHandler) Validate(zipData []byte) error {
 	return nil
 }
 
-// updateSettings updates settings.json to register the hook
-func (h *HookHandler) updateSettings(targetBase string) error {
-	settingsPath := filepath.Join(targetBase, "settings.json")
-
-	// Read existing settings or create new
-	var settings map[string]interface{}
-	if utils.FileExists(settingsPath) {
-		data, err := os.ReadFile(settingsPath)
-		if err != nil {
-			return fmt.Errorf("failed to read settings.json: %w", err)
-		}
-		if err := json.Unmarshal(data, &settings); err != nil {
-			return fmt.Errorf("failed to parse settings.json: %w", err)
-		}
-	} else {
-		settings = make(map[string]interface{})
-	}
-
-	// Ensure hooks section exists
-	if settings["hooks"] == nil {
-		settings["hooks"] = make(map[string]interface{})
+// settingsPath returns the file the hook is registered in: settings.json, or
+// hooks/hooks.json when targetBase is a plugin
+func (h *HookHandler) settingsPath(targetBase string) string {
+	if IsPluginDir(targetBase) {
+		return filepath.Join(targetBase, "hooks", "hooks.json")
 	}
-	hooks := settings["hooks"].(map[string]interface{})
-
-	// Build hook configuration
-	hookConfig := h.buildHookConfig()
+	return filepath.Join(targetBase, "settings.json")
+}
 
-	// Add/update hook entry
+// updateSettings updates settings.json to register the hook
+func (h *HookHandler) updateSettings(targetBase string) error {
+	settingsPath := h.settingsPath(targetBase)
 	hookEvent := h.metadata.Hook.Event
-	if hooks[hookEvent] == nil {
-		hooks[hookEvent] = []interface{}{}
-	}
-
-	// Get existing hooks for this event
-	eventHooks := hooks[hookEvent].([]interface{})
-
-	// Remove any existing entry for this asset (by checking _artifact field)
-	var filtered []interface{}
-	for _, hook := range eventHooks {
-		hookMap, ok := hook.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		assetID, ok := hookMap["_artifact"].(string)
-		if !ok || assetID != h.metadata.Asset.Name {
+	entryKey := clientconfig.EntryKey("hooks", hookEvent, h.metadata.Asset.Name)
+
+	return clientconfig.Update(settingsPath, func(doc *clientconfig.Document) error {
+		eventHooks, _ := doc.Get("hooks", hookEvent)
+		existing, _ := eventHooks.([]interface{})
+
+		// Build hook configuration
+		hookConfig := h.buildHookConfig(targetBase)
+
+		// Replace any existing entry for this asset (by checking _artifact field)
+		filtered := []interface{}{}
+		for _, hook := range existing {
+			if h.isOwnEntry(hook) {
+				if !doc.MayReplace(entryKey, hook, hookConfig) {
+					return nil // Keep the user's edited entry
+				}
+				continue
+			}
 			filtered = append(filtered, hook)
 		}
-	}
-
-	// Add new hook entry
-	filtered = append(filtered, hookConfig)
-	hooks[hookEvent] = filtered
-
-	// Write updated settings
-	data, err := json.MarshalIndent(settings, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal settings: %w", err)
-	}
-
-	if err := os.WriteFile(settingsPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write settings.json: %w", err)
-	}
+		filtered = append(filtered, hookConfig)
 
-	return nil
+		if err := doc.Set(filtered, "hooks", hookEvent); err != nil {
+			return err
+		}
+		doc.Claim(entryKey, hookConfig)
+		return nil
+	})
 }
 
 // removeFromSettings removes the hook from settings.json
 func (h *HookHandler) removeFromSettings(targetBase string) error {
-	settingsPath := filepath.Join(targetBase, "settings.json")
-
-	if !utils.FileExists(settingsPath) {
-		return nil // Nothing to remove
-	}
-
-	// Read settings
-	data, err := os.ReadFile(settingsPath)
-	if err != nil {
-		return fmt.Errorf("failed to read settings.json: %w", err)
-	}
-
-	var settings map[string]interface{}
-	if err := json.Unmarshal(data, &settings); err != nil {
-		return fmt.Errorf("failed to parse settings.json: %w", err)
-	}
-
-	// Check if hooks section exists
-	if settings["hooks"] == nil {
-		return nil
-	}
-	hooks := settings["hooks"].(map[string]interface{})
-
-	// Remove from the specific event
+	settingsPath := h.settingsPath(targetBase)
 	hookEvent := h.metadata.Hook.Event
-	if hooks[hookEvent] == nil {
-		return nil
-	}
+	entryKey := clientconfig.EntryKey("hooks", hookEvent, h.metadata.Asset.Name)
 
-	eventHooks := hooks[hookEvent].([]interface{})
+	return clientconfig.Update(settingsPath, func(doc *clientconfig.Document) error {
+		defer doc.Release(entryKey)
 
-	// Filter out this asset's hook
-	var filtered []interface{}
-	for _, hook := range eventHooks {
-		hookMap, ok := hook.(map[string]interface{})
+		eventHooks, _ := doc.Get("hooks", hookEvent)
+		existing, ok := eventHooks.([]interface{})
 		if !ok {
-			continue
+			return nil // Nothing to remove
 		}
-		assetID, ok := hookMap["_artifact"].(string)
-		if !ok || assetID != h.metadata.Asset.Name {
+
+		// Filter out this asset's hook
+		filtered := []interface{}{}
+		for _, hook := range existing {
+			if h.isOwnEntry(hook) && doc.MayReplace(entryKey, hook, nil) {
+				continue
+			}
 			filtered = append(filtered, hook)
 		}
-	}
-
-	hooks[hookEvent] = filtered
 
-	// Write updated settings
-	data, err = json.MarshalIndent(settings, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal settings: %w", err)
-	}
+		if len(filtered) == len(existing) {
+			return nil
+		}
+		return doc.Set(filtered, "hooks", hookEvent)
+	})
+}
 
-	if err := os.WriteFile(settingsPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write settings.json: %w", err)
+// isOwnEntry reports whether a settings.json hook entry belongs to this asset
+func (h *HookHandler) isOwnEntry(hook interface{}) bool {
+	hookMap, ok := hook.(map[string]interface{})
+	if !ok {
+		return false
 	}
-
-	return nil
+	assetID, ok := hookMap["_artifact"].(string)
+	return ok && assetID == h.metadata.Asset.Name
 }
 
 // buildHookConfig builds the hook configuration for settings.json
-func (h *HookHandler) buildHookConfig() map[string]interface{} {
+func (h *HookHandler) buildHookConfig(targetBase string) map[string]interface{} {
 	// Get absolute path to script file
 	scriptPath := filepath.Join(h.GetInstallPath(), h.metadata.Hook.ScriptFile)
+	if IsPluginDir(targetBase) {
+		// Plugins are copied by Claude Code, so scripts are found through the plugin root
+		scriptPath = "${CLAUDE_PLUGIN_ROOT}/" + filepath.ToSlash(scriptPath)
+	}
 
 	config := map[string]interface{}{
 		"script":    scriptPath,
@@ -2,13 +2,13 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/clientconfig"
 	"github.com/sleuth-io/sx/internal/handlers/dirasset"
 	"github.com/sleuth-io/sx/internal/metadata"
 	"github.com/sleuth-io/sx/internal/utils"
@@ -103,6 +103,10 @@ func (h *MCPHandler) Install(ctx context.Context, zipData []byte, targetBase str
 
 	// Update .mcp.json to register the MCP server
 	installPath := filepath.Join(targetBase, h.GetInstallPath())
+	if IsPluginDir(targetBase) {
+		// Plugins are copied by Claude Code, so servers are found through the plugin root
+		installPath = "${CLAUDE_PLUGIN_ROOT}/" + filepath.ToSlash(h.GetInstallPath())
+	}
 	if err := h.updateMCPConfig(targetBase, installPath); err != nil {
 		return fmt.Errorf("failed to update MCP config: %w", err)
 	}
@@ -172,45 +176,11 @@ func (h *MCPHandler) Validate(zipData []byte) error {
 func (h *MCPHandler) updateMCPConfig(targetBase, installPath string) error {
 	mcpConfigPath := filepath.Join(targetBase, ".mcp.json")
 
-	// Read existing config or create new
-	var config map[string]interface{}
-	if utils.FileExists(mcpConfigPath) {
-		data, err := os.ReadFile(mcpConfigPath)
-		if err != nil {
-			return fmt.Errorf("failed to read .mcp.json: %w", err)
-		}
-		if err := json.Unmarshal(data, &config); err != nil {
-			return fmt.Errorf("failed to parse .mcp.json: %w", err)
-		}
-	} else {
-		config = map[string]interface{}{
-			"mcpServers": make(map[string]interface{}),
-		}
-	}
-
-	// Ensure mcpServers section exists
-	if config["mcpServers"] == nil {
-		config["mcpServers"] = make(map[string]interface{})
-	}
-	mcpServers := config["mcpServers"].(map[string]interface{})
-
-	// Build MCP server configuration
-	serverConfig := h.buildMCPServerConfig(installPath)
-
-	// Add/update MCP server entry
-	mcpServers[h.metadata.Asset.Name] = serverConfig
-
-	// Write updated config
-	data, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal MCP config: %w", err)
-	}
-
-	if err := os.WriteFile(mcpConfigPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write .mcp.json: %w", err)
-	}
-
-	return nil
+	return clientconfig.Update(mcpConfigPath, func(doc *clientconfig.Document) error {
+		// Add/update MCP server entry unless the user has edited it
+		_, err := doc.SetOwned(h.buildMCPServerConfig(installPath), "mcpServers", h.metadata.Asset.Name)
+		return err
+	})
 }
 
 // removeFromMCPConfig removes the MCP server from .mcp.json
@@ -221,37 +191,10 @@ func (h *MCPHandler) removeFromMCPConfig(targetBase string) error {
 		return nil // Nothing to remove
 	}
 
-	// Read config
-	data, err := os.ReadFile(mcpConfigPath)
-	if err != nil {
-		return fmt.Errorf("failed to read .mcp.json: %w", err)
-	}
-
-	var config map[string]interface{}
-	if err := json.Unmarshal(data, &config); err != nil {
-		return fmt.Errorf("failed to parse .mcp.json: %w", err)
-	}
-
-	// Check if mcpServers section exists
-	if config["mcpServers"] == nil {
-		return nil
-	}
-	mcpServers := config["mcpServers"].(map[string]interface{})
-
-	// Remove this MCP server
-	delete(mcpServers, h.metadata.Asset.Name)
-
-	// Write updated config
-	data, err = json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal MCP config: %w", err)
-	}
-
-	if err := os.WriteFile(mcpConfigPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write .mcp.json: %w", err)
-	}
-
-	return nil
+	return clientconfig.Update(mcpConfigPath, func(doc *clientconfig.Document) error {
+		_, err := doc.DeleteOwned("mcpServers", h.metadata.Asset.Name)
+		return err
+	})
 }
 
 // buildMCPServerConfig builds the MCP server configuration for .mcp.json
@@ -261,7 +204,7 @@ func (h *MCPHandler) buildMCPServerConfig(installPath string) map[string]interfa
 	// Convert relative command paths to absolute (relative to install path)
 	command := mcpConfig.Command
 	if !filepath.IsAbs(command) {
-		command = filepath.Join(installPath, command)
+		command = joinInstallPath(installPath, command)
 	}
 
 	// Convert relative args paths to absolute
@@ -269,7 +212,7 @@ func (h *MCPHandler) buildMCPServerConfig(installPath string) map[string]interfa
 	for i, arg := range mcpConfig.Args {
 		// If arg looks like a relative path (contains / or \), make it absolute
 		if !filepath.IsAbs(arg) && (filepath.Base(arg) != arg) {
-			args[i] = filepath.Join(installPath, arg)
+			args[i] = joinInstallPath(installPath, arg)
 		} else {
 			args[i] = arg
 		}
@@ -292,6 +235,15 @@ func (h *MCPHandler) buildMCPServerConfig(installPath string) map[string]interfa
 	return config
 }
 
+// joinInstallPath joins a relative path onto installPath, keeping forward
+// slashes for the ${CLAUDE_PLUGIN_ROOT} form used in plugins
+func joinInstallPath(installPath, rel string) string {
+	if strings.HasPrefix(installPath, "${CLAUDE_PLUGIN_ROOT}") {
+		return path.Join(installPath, filepath.ToSlash(rel))
+	}
+	return filepath.Join(installPath, rel)
+}
+
 // CanDetectInstalledState returns true since MCP servers preserve metadata.toml
 func (h *MCPHandler) CanDetectInstalledState() bool {
 	return true
@@ -2,13 +2,12 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/clientconfig"
 	"github.com/sleuth-io/sx/internal/metadata"
 	"github.com/sleuth-io/sx/internal/utils"
 )
@@ -104,45 +103,11 @@ func (h *MCPRemoteHandler) Validate(zipData []byte) error {
 func (h *MCPRemoteHandler) updateMCPConfig(targetBase string) error {
 	mcpConfigPath := filepath.Join(targetBase, ".mcp.json")
 
-	// Read existing config or create new
-	var config map[string]interface{}
-	if utils.FileExists(mcpConfigPath) {
-		data, err := os.ReadFile(mcpConfigPath)
-		if err != nil {
-			return fmt.Errorf("failed to read .mcp.json: %w", err)
-		}
-		if err := json.Unmarshal(data, &config); err != nil {
-			return fmt.Errorf("failed to parse .mcp.json: %w", err)
-		}
-	} else {
-		config = map[string]interface{}{
-			"mcpServers": make(map[string]interface{}),
-		}
-	}
-
-	// Ensure mcpServers section exists
-	if config["mcpServers"] == nil {
-		config["mcpServers"] = make(map[string]interface{})
-	}
-	mcpServers := config["mcpServers"].(map[string]interface{})
-
-	// Build MCP server configuration
-	serverConfig := h.buildMCPServerConfig()
-
-	// Add/update MCP server entry
-	mcpServers[h.metadata.Asset.Name] = serverConfig
-
-	// Write updated config
-	data, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal MCP config: %w", err)
-	}
-
-	if err := os.WriteFile(mcpConfigPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write .mcp.json: %w", err)
-	}
-
-	return nil
+	return clientconfig.Update(mcpConfigPath, func(doc *clientconfig.Document) error {
+		// Add/update MCP server entry unless the user has edited it
+		_, err := doc.SetOwned(h.buildMCPServerConfig(), "mcpServers", h.metadata.Asset.Name)
+		return err
+	})
 }
 
 // removeFromMCPConfig removes the MCP remote server from .mcp.json
@@ -153,43 +118,20 @@ func (h *MCPRemoteHandler) removeFromMCPConfig(targetBase string) error {
 		return nil // Nothing to remove
 	}
 
-	// Read config
-	data, err := os.ReadFile(mcpConfigPath)
-	if err != nil {
-		return fmt.Errorf("failed to read .mcp.json: %w", err)
-	}
-
-	var config map[string]interface{}
-	if err := json.Unmarshal(data, &config); err != nil {
-		return fmt.Errorf("failed to parse .mcp.json: %w", err)
-	}
-
-	// Check if mcpServers section exists
-	if config["mcpServers"] == nil {
-		return nil
-	}
-	mcpServers := config["mcpServers"].(map[string]interface{})
-
-	// Remove this MCP server
-	delete(mcpServers, h.metadata.Asset.Name)
-
-	// Write updated config
-	data, err = json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal MCP config: %w", err)
-	}
-
-	if err := os.WriteFile(mcpConfigPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write .mcp.json: %w", err)
-	}
-
-	return nil
+	return clientconfig.Update(mcpConfigPath, func(doc *clientconfig.Document) error {
+		_, err := doc.DeleteOwned("mcpServers", h.metadata.Asset.Name)
+		return err
+	})
 }
 
 // buildMCPServerConfig builds the MCP server configuration for .mcp.json
 func (h *MCPRemoteHandler) buildMCPServerConfig() map[string]interface{} {
 	mcpConfig := h.metadata.MCP
 
+	if mcpConfig.IsRemote() {
+		return h.buildRemoteServerConfig()
+	}
+
 	// For remote MCPs, commands are external (npx, docker, etc.)
 	// No path conversion needed
 	args := make([]interface{}, len(mcpConfig.Args))
@@ -214,6 +156,36 @@ func (h *MCPRemoteHandler) buildMCPServerConfig() map[string]interface{} {
 	return config
 }
 
+// buildRemoteServerConfig builds an http or sse server entry, which Claude Code
+// connects to directly
+func (h *MCPRemoteHandler) buildRemoteServerConfig() map[string]interface{} {
+	mcpConfig := h.metadata.MCP
+
+	config := map[string]interface{}{
+		"type":      mcpConfig.GetTransport(),
+		"url":       mcpConfig.URL,
+		"_artifact": h.metadata.Asset.Name,
+	}
+
+	if len(mcpConfig.Headers) > 0 {
+		config["headers"] = mcpConfig.Headers
+	}
+	if oauth := mcpConfig.OAuth; oauth != nil {
+		hints := map[string]interface{}{}
+		if oauth.ClientID != "" {
+			hints["clientId"] = oauth.ClientID
+		}
+		if oauth.CallbackPort > 0 {
+			hints["callbackPort"] = oauth.CallbackPort
+		}
+		if len(hints) > 0 {
+			config["oauth"] = hints
+		}
+	}
+
+	return config
+}
+
 // CanDetectInstalledState returns false since mcp-remote doesn't preserve metadata.toml
 func (h *MCPRemoteHandler) CanDetectInstalledState() bool {
 	return false
@@ -227,22 +199,17 @@ func (h *MCPRemoteHandler) VerifyInstalled(targetBase string) (bool, string) {
 		return false, ".mcp.json not found"
 	}
 
-	data, err := os.ReadFile(mcpConfigPath)
+	doc, err := clientconfig.Load(mcpConfigPath)
 	if err != nil {
 		return false, "failed to read .mcp.json: " + err.Error()
 	}
 
-	var config map[string]interface{}
-	if err := json.Unmarshal(data, &config); err != nil {
-		return false, "failed to parse .mcp.json: " + err.Error()
-	}
-
-	mcpServers, ok := config["mcpServers"].(map[string]interface{})
+	mcpServers, ok := doc.Get("mcpServers")
 	if !ok {
 		return false, "mcpServers section not found"
 	}
 
-	if _, exists := mcpServers[h.metadata.Asset.Name]; !exists {
+	if servers, ok := mcpServers.(map[string]interface{}); !ok || servers[h.metadata.Asset.Name] == nil {
 		return false, "MCP remote server not registered"
 	}
 
@@ -1,13 +1,14 @@
 package claude_code
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/sleuth-io/sx/internal/clientconfig"
 	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/utils"
 )
 
 // installHooks installs system hooks for Claude Code (auto-update and usage tracking).
@@ -41,97 +42,73 @@ func installSessionStartHook(claudeDir string) error {
 	settingsPath := filepath.Join(claudeDir, "settings.json")
 	log := logger.Get()
 
-	// Read existing settings or create new
-	var settings map[string]interface{}
-	if data, err := os.ReadFile(settingsPath); err == nil {
-		if err := json.Unmarshal(data, &settings); err != nil {
-			log.Error("failed to parse settings.json for SessionStart hook", "error", err)
-			return fmt.Errorf("failed to parse settings.json: %w", err)
+	return clientconfig.Update(settingsPath, func(doc *clientconfig.Document) error {
+		// Get or create SessionStart array
+		existing, _ := doc.Get("hooks", "SessionStart")
+		sessionStart, ok := existing.([]interface{})
+		if !ok {
+			sessionStart = []interface{}{}
 		}
-	} else {
-		settings = make(map[string]interface{})
-	}
-
-	// Get or create hooks section
-	hooks, ok := settings["hooks"].(map[string]interface{})
-	if !ok {
-		hooks = make(map[string]interface{})
-		settings["hooks"] = hooks
-	}
-
-	// Get or create SessionStart array
-	sessionStart, ok := hooks["SessionStart"].([]interface{})
-	if !ok {
-		sessionStart = []interface{}{}
-	}
 
-	hookCommand := "sx install --hook-mode --client=claude-code"
-
-	// First, check if exact hook command already exists
-	exactMatch := false
-	var oldHookRef map[string]interface{}
-	for _, item := range sessionStart {
-		if hookMap, ok := item.(map[string]interface{}); ok {
-			if hooksArray, ok := hookMap["hooks"].([]interface{}); ok {
-				for _, h := range hooksArray {
-					if hMap, ok := h.(map[string]interface{}); ok {
-						if cmd, ok := hMap["command"].(string); ok {
-							if cmd == hookCommand {
-								exactMatch = true
-								break
-							}
-							if strings.HasPrefix(cmd, "sx install") || strings.HasPrefix(cmd, "skills install") {
-								oldHookRef = hMap // Remember for updating
+		hookCommand := "sx install --hook-mode --client=claude-code"
+
+		// First, check if exact hook command already exists
+		exactMatch := false
+		var oldHookRef map[string]interface{}
+		for _, item := range sessionStart {
+			if hookMap, ok := item.(map[string]interface{}); ok {
+				if hooksArray, ok := hookMap["hooks"].([]interface{}); ok {
+					for _, h := range hooksArray {
+						if hMap, ok := h.(map[string]interface{}); ok {
+							if cmd, ok := hMap["command"].(string); ok {
+								if cmd == hookCommand {
+									exactMatch = true
+									break
+								}
+								if strings.HasPrefix(cmd, "sx install") || strings.HasPrefix(cmd, "skills install") {
+									oldHookRef = hMap // Remember for updating
+								}
 							}
 						}
 					}
 				}
 			}
+			if exactMatch {
+				break
+			}
 		}
+
+		// Already have exact match, nothing to do
 		if exactMatch {
-			break
+			return nil
 		}
-	}
-
-	// Already have exact match, nothing to do
-	if exactMatch {
-		return nil
-	}
 
-	// Get current working directory for context logging
-	cwd, _ := os.Getwd()
-
-	// Update old hook if found, otherwise add new
-	if oldHookRef != nil {
-		oldHookRef["command"] = hookCommand
-		log.Info("hook updated", "hook", "SessionStart", "command", hookCommand, "cwd", cwd)
-	} else {
-		newHook := map[string]interface{}{
-			"hooks": []interface{}{
-				map[string]interface{}{
-					"type":    "command",
-					"command": hookCommand,
+		// Get current working directory for context logging
+		cwd, _ := os.Getwd()
+
+		// Update old hook if found, otherwise add new
+		if oldHookRef != nil {
+			oldHookRef["command"] = hookCommand
+			log.Info("hook updated", "hook", "SessionStart", "command", hookCommand, "cwd", cwd)
+		} else {
+			newHook := map[string]interface{}{
+				"hooks": []interface{}{
+					map[string]interface{}{
+						"type":    "command",
+						"command": hookCommand,
+					},
 				},
-			},
+			}
+			sessionStart = append(sessionStart, newHook)
+			log.Info("hook installed", "hook", "SessionStart", "command", hookCommand, "cwd", cwd)
 		}
-		sessionStart = append(sessionStart, newHook)
-		hooks["SessionStart"] = sessionStart
-		log.Info("hook installed", "hook", "SessionStart", "command", hookCommand, "cwd", cwd)
-	}
-
-	// Write back to file
-	data, err := json.MarshalIndent(settings, "", "  ")
-	if err != nil {
-		log.Error("failed to marshal settings for SessionStart hook", "error", err)
-		return fmt.Errorf("failed to marshal settings: %w", err)
-	}
 
-	if err := os.WriteFile(settingsPath, data, 0644); err != nil {
-		log.Error("failed to write settings.json for SessionStart hook", "error", err, "path", settingsPath)
-		return fmt.Errorf("failed to write settings.json: %w", err)
-	}
-
-	return nil
+		if err := doc.Set(sessionStart, "hooks", "SessionStart"); err != nil {
+			log.Error("failed to update settings.json for SessionStart hook", "error", err, "path", settingsPath)
+			return fmt.Errorf("failed to update settings.json: %w", err)
+		}
+		return nil
+	})
 }
 
 // uninstallHooks removes system hooks for Claude Code.
@@ -144,79 +121,56 @@ func uninstallHooks() error {
 	claudeDir := filepath.Join(home, ".claude")
 	settingsPath := filepath.Join(claudeDir, "settings.json")
 
+	if !utils.FileExists(settingsPath) {
+		// No settings file, nothing to uninstall
+		return nil
+	}
+
 	log := logger.Get()
 
-	// Read existing settings
-	data, err := os.ReadFile(settingsPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			// No settings file, nothing to uninstall
-			return nil
+	return clientconfig.Update(settingsPath, func(doc *clientconfig.Document) error {
+		// Remove our SessionStart and PostToolUse hooks (check both sx and legacy skills commands)
+		removals := []struct {
+			event    string
+			prefixes []string
+		}{
+			{"SessionStart", []string{"sx install", "skills install"}},
+			{"PostToolUse", []string{"sx report-usage", "skills report-usage"}},
 		}
-		return fmt.Errorf("failed to read settings.json: %w", err)
-	}
-
-	var settings map[string]interface{}
-	if err := json.Unmarshal(data, &settings); err != nil {
-		return fmt.Errorf("failed to parse settings.json: %w", err)
-	}
 
-	hooks, ok := settings["hooks"].(map[string]interface{})
-	if !ok {
-		// No hooks section, nothing to uninstall
-		return nil
-	}
+		for _, r := range removals {
+			existing, _ := doc.Get("hooks", r.event)
+			eventHooks, ok := existing.([]interface{})
+			if !ok {
+				continue
+			}
 
-	modified := false
+			filtered := removeSxHooks(eventHooks, r.prefixes...)
+			if len(filtered) == len(eventHooks) {
+				continue
+			}
 
-	// Remove our SessionStart hook (check both sx and legacy skills commands)
-	if sessionStart, ok := hooks["SessionStart"].([]interface{}); ok {
-		filtered := removeSxHooks(sessionStart, "sx install", "skills install")
-		if len(filtered) != len(sessionStart) {
-			modified = true
 			if len(filtered) == 0 {
-				delete(hooks, "SessionStart")
-			} else {
-				hooks["SessionStart"] = filtered
+				if _, err := doc.Delete("hooks", r.event); err != nil {
+					return fmt.Errorf("failed to update settings.json: %w", err)
+				}
+			} else if err := doc.Set(filtered, "hooks", r.event); err != nil {
+				return fmt.Errorf("failed to update settings.json: %w", err)
 			}
-			log.Info("hook removed", "hook", "SessionStart")
+			log.Info("hook removed", "hook", r.event)
 		}
-	}
 
-	// Remove our PostToolUse hook (check both sx and legacy skills commands)
-	if postToolUse, ok := hooks["PostToolUse"].([]interface{}); ok {
-		filtered := removeSxHooks(postToolUse, "sx report-usage", "skills report-usage")
-		if len(filtered) != len(postToolUse) {
-			modified = true
-			if len(filtered) == 0 {
-				delete(hooks, "PostToolUse")
-			} else {
-				hooks["PostToolUse"] = filtered
+		// Remove empty hooks section
+		if hooks, ok := doc.Get("hooks"); ok {
+			if hooksMap, ok := hooks.(map[string]interface{}); ok && len(hooksMap) == 0 {
+				if _, err := doc.Delete("hooks"); err != nil {
+					return fmt.Errorf("failed to update settings.json: %w", err)
+				}
 			}
-			log.Info("hook removed", "hook", "PostToolUse")
 		}
-	}
 
-	// Remove empty hooks section
-	if len(hooks) == 0 {
-		delete(settings, "hooks")
-	}
-
-	if !modified {
 		return nil
-	}
-
-	// Write back to file
-	data, err = json.MarshalIndent(settings, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal settings: %w", err)
-	}
-
-	if err := os.WriteFile(settingsPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write settings.json: %w", err)
-	}
-
-	return nil
+	})
 }
 
 // removeSxHooks filters out hooks whose command starts with any of the given prefixes
@@ -269,93 +223,69 @@ func installUsageReportingHook(claudeDir string) error {
 	settingsPath := filepath.Join(claudeDir, "settings.json")
 	log := logger.Get()
 
-	// Read existing settings or create new
-	var settings map[string]interface{}
-	if data, err := os.ReadFile(settingsPath); err == nil {
-		if err := json.Unmarshal(data, &settings); err != nil {
-			log.Error("failed to parse settings.json for PostToolUse hook", "error", err)
-			return fmt.Errorf("failed to parse settings.json: %w", err)
+	return clientconfig.Update(settingsPath, func(doc *clientconfig.Document) error {
+		// Get or create PostToolUse array
+		existing, _ := doc.Get("hooks", "PostToolUse")
+		postToolUse, ok := existing.([]interface{})
+		if !ok {
+			postToolUse = []interface{}{}
 		}
-	} else {
-		settings = make(map[string]interface{})
-	}
-
-	// Get or create hooks section
-	hooks, ok := settings["hooks"].(map[string]interface{})
-	if !ok {
-		hooks = make(map[string]interface{})
-		settings["hooks"] = hooks
-	}
-
-	// Get or create PostToolUse array
-	postToolUse, ok := hooks["PostToolUse"].([]interface{})
-	if !ok {
-		postToolUse = []interface{}{}
-	}
 
-	hookCommand := "sx report-usage --client=claude-code"
-
-	// Check if our hook already exists (check for both old and new command formats)
-	hookExists := false
-	var oldHookRef map[string]interface{}
-	for _, item := range postToolUse {
-		if hookMap, ok := item.(map[string]interface{}); ok {
-			if hooksArray, ok := hookMap["hooks"].([]interface{}); ok {
-				for _, h := range hooksArray {
-					if hMap, ok := h.(map[string]interface{}); ok {
-						if cmd, ok := hMap["command"].(string); ok {
-							if cmd == hookCommand {
-								hookExists = true
-								break
-							}
-							if cmd == "skills report-usage" || cmd == "sx report-usage" || cmd == "skills report-usage --client=claude-code" {
-								oldHookRef = hMap // Remember for updating
+		hookCommand := "sx report-usage --client=claude-code"
+
+		// Check if our hook already exists (check for both old and new command formats)
+		hookExists := false
+		var oldHookRef map[string]interface{}
+		for _, item := range postToolUse {
+			if hookMap, ok := item.(map[string]interface{}); ok {
+				if hooksArray, ok := hookMap["hooks"].([]interface{}); ok {
+					for _, h := range hooksArray {
+						if hMap, ok := h.(map[string]interface{}); ok {
+							if cmd, ok := hMap["command"].(string); ok {
+								if cmd == hookCommand {
+									hookExists = true
+									break
+								}
+								if cmd == "skills report-usage" || cmd == "sx report-usage" || cmd == "skills report-usage --client=claude-code" {
+									oldHookRef = hMap // Remember for updating
+								}
 							}
 						}
 					}
 				}
 			}
+			if hookExists {
+				break
+			}
 		}
+
+		// Already have exact match, nothing to do
 		if hookExists {
-			break
+			return nil
 		}
-	}
-
-	// Already have exact match, nothing to do
-	if hookExists {
-		return nil
-	}
 
-	// Update old hook if found, otherwise add new
-	if oldHookRef != nil {
-		oldHookRef["command"] = hookCommand
-		log.Info("hook updated", "hook", "PostToolUse", "command", hookCommand)
-	} else {
-		newHook := map[string]interface{}{
-			"matcher": "Skill|Task|SlashCommand|mcp__.*",
-			"hooks": []interface{}{
-				map[string]interface{}{
-					"type":    "command",
-					"command": hookCommand,
+		// Update old hook if found, otherwise add new
+		if oldHookRef != nil {
+			oldHookRef["command"] = hookCommand
+			log.Info("hook updated", "hook", "PostToolUse", "command", hookCommand)
+		} else {
+			newHook := map[string]interface{}{
+				"matcher": "Skill|Task|SlashCommand|mcp__.*",
+				"hooks": []interface{}{
+					map[string]interface{}{
+						"type":    "command",
+						"command": hookCommand,
+					},
 				},
-			},
+			}
+			postToolUse = append(postToolUse, newHook)
+			log.Info("hook installed", "hook", "PostToolUse", "command", hookCommand)
 		}
-		postToolUse = append(postToolUse, newHook)
-		hooks["PostToolUse"] = postToolUse
-		log.Info("hook installed", "hook", "PostToolUse", "command", hookCommand)
-	}
-
-	// Write back to file
-	data, err := json.MarshalIndent(settings, "", "  ")
-	if err != nil {
-		log.Error("failed to marshal settings for PostToolUse hook", "error", err)
-		return fmt.Errorf("failed to marshal settings: %w", err)
-	}
 
-	if err := os.WriteFile(settingsPath, data, 0644); err != nil {
-		log.Error("failed to write settings.json for PostToolUse hook", "error", err, "path", settingsPath)
-		return fmt.Errorf("failed to write settings.json: %w", err)
-	}
-
-	return nil
+		if err := doc.Set(postToolUse, "hooks", "PostToolUse"); err != nil {
+			log.Error("failed to update settings.json for PostToolUse hook", "error", err, "path", settingsPath)
+			return fmt.Errorf("failed to update settings.json: %w", err)
+		}
+		return nil
+	})
 }
@@ -0,0 +1,352 @@
+package claude_code
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sleuth-io/sx/internal/clientconfig"
+	"github.com/sleuth-io/sx/internal/clients"
+	"github.com/sleuth-io/sx/internal/config"
+	"github.com/sleuth-io/sx/internal/journal"
+	"github.com/sleuth-io/sx/internal/utils"
+)
+
+// marketplaceName is the name sx's plugin marketplace is registered under
+const marketplaceName = "sx"
+
+// pluginAssetDirs are the plugin directories assets are installed into
+var pluginAssetDirs = []string{"skills", "commands", "agents", "hooks", "mcp-servers"}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// pluginScope reports whether a scope can have a plugin. Plugins can't be
+// limited to a path within a repository, so path-scoped assets always use the
+// .claude directory layout.
+func pluginScope(scope *clients.InstallScope) bool {
+	switch scope.Type {
+	case clients.ScopeGlobal:
+		return true
+	case clients.ScopeRepository:
+		return scope.RepoRoot != ""
+	default:
+		return false
+	}
+}
+
+// usePlugin reports whether assets for scope are installed into an sx plugin
+func usePlugin(scope *clients.InstallScope) bool {
+	if !pluginScope(scope) {
+		return false
+	}
+	cfg, err := config.Load()
+	return err == nil && cfg.ClaudePlugin
+}
+
+// marketplaceDir returns the local plugin marketplace maintained by sx
+func marketplaceDir() (string, error) {
+	configDir, err := utils.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "claude-marketplace"), nil
+}
+
+// pluginName returns the name of the plugin holding a scope's assets
+func pluginName(scope *clients.InstallScope) string {
+	if scope.Type == clients.ScopeGlobal {
+		return "sx-global"
+	}
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(filepath.Base(scope.RepoRoot)), "-"), "-")
+	sum := sha256.Sum256([]byte(scope.RepoRoot))
+	return fmt.Sprintf("sx-%s-%s", slug, hex.EncodeToString(sum[:4]))
+}
+
+// pluginDir returns the directory of a scope's plugin
+func pluginDir(scope *clients.InstallScope) (string, error) {
+	dir, err := marketplaceDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "plugins", pluginName(scope)), nil
+}
+
+// pluginSettingsPath returns the settings file a scope's plugin is enabled in.
+// Repository plugins use settings.local.json since the marketplace path is
+// specific to this machine.
+func pluginSettingsPath(scope *clients.InstallScope) string {
+	if scope.Type == clients.ScopeGlobal {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, ".claude", "settings.json")
+	}
+	return filepath.Join(scope.RepoRoot, ".claude", "settings.local.json")
+}
+
+// pluginManifest is a plugin's .claude-plugin/plugin.json
+type pluginManifest struct {
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+}
+
+// ensurePlugin creates the scope's plugin if needed and registers it with Claude Code
+func ensurePlugin(scope *clients.InstallScope) (string, error) {
+	dir, err := pluginDir(scope)
+	if err != nil {
+		return "", fmt.Errorf("cannot determine plugin directory: %w", err)
+	}
+
+	manifestPath := filepath.Join(dir, ".claude-plugin", "plugin.json")
+	if !utils.FileExists(manifestPath) {
+		description := "Assets installed by sx for all projects"
+		if scope.Type == clients.ScopeRepository {
+			description = "Assets installed by sx for " + scope.RepoRoot
+		}
+		manifest := pluginManifest{Name: pluginName(scope), Version: "0.0.0", Description: description}
+		if err := writePluginJSON(manifestPath, manifest); err != nil {
+			return "", err
+		}
+	}
+
+	if err := writeMarketplace(); err != nil {
+		return "", err
+	}
+
+	marketplace, err := marketplaceDir()
+	if err != nil {
+		return "", err
+	}
+	pluginKey := pluginName(scope) + "@" + marketplaceName
+	err = clientconfig.Update(pluginSettingsPath(scope), func(doc *clientconfig.Document) error {
+		source := map[string]interface{}{
+			"source": map[string]interface{}{"source": "directory", "path": marketplace},
+		}
+		if _, err := doc.SetOwned(source, "extraKnownMarketplaces", marketplaceName); err != nil {
+			return err
+		}
+		// Only enable it the first time, so a plugin the user turned off stays off
+		if _, ok := doc.Get("enabledPlugins", pluginKey); !ok {
+			return doc.Set(true, "enabledPlugins", pluginKey)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to register plugin: %w", err)
+	}
+
+	return dir, nil
+}
+
+// finishPlugin updates the scope's plugin after assets were installed or
+// removed, removing it entirely once it's empty
+func finishPlugin(scope *clients.InstallScope) error {
+	if !pluginScope(scope) {
+		return nil
+	}
+	dir, err := pluginDir(scope)
+	if err != nil {
+		return err
+	}
+	if !utils.IsDirectory(dir) {
+		return nil
+	}
+
+	empty, err := pluginIsEmpty(dir)
+	if err != nil {
+		return err
+	}
+	if empty {
+		return removePlugin(scope)
+	}
+
+	// Claude Code caches plugins by version, so the version follows the content
+	version, err := hashPluginContent(dir)
+	if err != nil {
+		return err
+	}
+	manifestPath := filepath.Join(dir, ".claude-plugin", "plugin.json")
+	var manifest pluginManifest
+	if data, err := os.ReadFile(manifestPath); err == nil {
+		_ = json.Unmarshal(data, &manifest)
+	}
+	manifest.Name = pluginName(scope)
+	if manifest.Version == version {
+		return nil
+	}
+	manifest.Version = version
+	if err := writePluginJSON(manifestPath, manifest); err != nil {
+		return err
+	}
+	return writeMarketplace()
+}
+
+// removePlugin deletes the scope's plugin and unregisters it
+func removePlugin(scope *clients.InstallScope) error {
+	dir, err := pluginDir(scope)
+	if err != nil {
+		return err
+	}
+	if err := journal.Track(dir); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove plugin: %w", err)
+	}
+	if err := writeMarketplace(); err != nil {
+		return err
+	}
+
+	// Each settings file only enables its own scope's plugin
+	settingsPath := pluginSettingsPath(scope)
+	if !utils.FileExists(settingsPath) {
+		return nil
+	}
+	return clientconfig.Update(settingsPath, func(doc *clientconfig.Document) error {
+		if _, err := doc.Delete("enabledPlugins", pluginName(scope)+"@"+marketplaceName); err != nil {
+			return err
+		}
+		_, err := doc.DeleteOwned("extraKnownMarketplaces", marketplaceName)
+		return err
+	})
+}
+
+// pluginIsEmpty reports whether a plugin no longer contains any assets
+func pluginIsEmpty(dir string) (bool, error) {
+	for _, sub := range pluginAssetDirs {
+		entries, err := os.ReadDir(filepath.Join(dir, sub))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return false, err
+		}
+		for _, entry := range entries {
+			if entry.Name() != "hooks.json" {
+				return false, nil
+			}
+		}
+	}
+
+	mcpPath := filepath.Join(dir, ".mcp.json")
+	if !utils.FileExists(mcpPath) {
+		return true, nil
+	}
+	doc, err := clientconfig.Load(mcpPath)
+	if err != nil {
+		return false, err
+	}
+	servers, _ := doc.Get("mcpServers")
+	serverMap, _ := servers.(map[string]interface{})
+	return len(serverMap) == 0, nil
+}
+
+// hashPluginContent returns a version string derived from a plugin's files
+func hashPluginContent(dir string) (string, error) {
+	hash := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		if d.IsDir() {
+			if rel == ".claude-plugin" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00", filepath.ToSlash(rel), len(content))
+		hash.Write(content)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash plugin: %w", err)
+	}
+	return "0.0.0+" + hex.EncodeToString(hash.Sum(nil))[:12], nil
+}
+
+// listPlugins returns the manifests of the plugins in the marketplace, by name
+func listPlugins() ([]pluginManifest, error) {
+	dir, err := marketplaceDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, "plugins"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read plugins: %w", err)
+	}
+
+	var plugins []pluginManifest
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, "plugins", entry.Name(), ".claude-plugin", "plugin.json"))
+		if err != nil {
+			continue
+		}
+		var manifest pluginManifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			continue
+		}
+		plugins = append(plugins, manifest)
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins, nil
+}
+
+// writeMarketplace writes the marketplace manifest listing every sx plugin
+func writeMarketplace() error {
+	dir, err := marketplaceDir()
+	if err != nil {
+		return err
+	}
+	plugins, err := listPlugins()
+	if err != nil {
+		return err
+	}
+
+	entries := make([]map[string]interface{}, 0, len(plugins))
+	for _, plugin := range plugins {
+		entries = append(entries, map[string]interface{}{
+			"name":        plugin.Name,
+			"source":      "./plugins/" + plugin.Name,
+			"description": plugin.Description,
+			"version":     plugin.Version,
+		})
+	}
+	marketplace := map[string]interface{}{
+		"name":    marketplaceName,
+		"owner":   map[string]interface{}{"name": "sx"},
+		"plugins": entries,
+	}
+	return writePluginJSON(filepath.Join(dir, ".claude-plugin", "marketplace.json"), marketplace)
+}
+
+// writePluginJSON writes a plugin or marketplace manifest
+func writePluginJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(path), err)
+	}
+	if err := journal.Track(path); err != nil {
+		return err
+	}
+	if err := utils.EnsureDir(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -36,7 +36,7 @@ type Client interface {
 
 	// EnsureAssetSupport ensures asset infrastructure is set up for the current context.
 	// This is called after installation to ensure rules files, MCP servers, etc. are configured.
-	// For Cursor, this creates local .cursor/rules/skills.md with skills from all applicable scopes.
+	// For Cursor, this creates local .cursor/rules/ entries for skills and agents from all applicable scopes.
 	// Clients that don't need post-install setup can return nil.
 	EnsureAssetSupport(ctx context.Context, scope *InstallScope) error
 
@@ -183,6 +183,24 @@ type AssetResult struct {
 	Status    ResultStatus
 	Message   string
 	Error     error
+	Warnings  []string // Parts of the asset the client couldn't apply as asked
+}
+
+type warningsKey struct{}
+
+// WithWarnings returns a context that collects the warnings handlers raise with
+// Warn while installing one asset, and a function returning them
+func WithWarnings(ctx context.Context) (context.Context, func() []string) {
+	warnings := &[]string{}
+	return context.WithValue(ctx, warningsKey{}, warnings), func() []string { return *warnings }
+}
+
+// Warn records a user-facing warning about the asset being installed, such as a
+// setting the client doesn't support. Callers log it themselves.
+func Warn(ctx context.Context, msg string) {
+	if warnings, ok := ctx.Value(warningsKey{}).(*[]string); ok {
+		*warnings = append(*warnings, msg)
+	}
 }
 
 type ResultStatus string
@@ -1,6 +1,7 @@
 package cursor
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -14,6 +15,7 @@ import (
 	"github.com/sleuth-io/sx/internal/clients"
 	"github.com/sleuth-io/sx/internal/clients/cursor/handlers"
 	"github.com/sleuth-io/sx/internal/handlers/dirasset"
+	"github.com/sleuth-io/sx/internal/journal"
 	"github.com/sleuth-io/sx/internal/lockfile"
 	"github.com/sleuth-io/sx/internal/logger"
 	"github.com/sleuth-io/sx/internal/metadata"
@@ -37,7 +39,8 @@ func NewClient() *Client {
 				asset.TypeMCPRemote,
 				asset.TypeSkill, // Transform to commands
 				asset.TypeCommand,
-				asset.TypeHook, // Supported via hooks.json
+				asset.TypeAgent, // Installed as on-demand rules
+				asset.TypeHook,  // Supported via hooks.json
 			},
 		),
 	}
@@ -89,6 +92,7 @@ func (c *Client) InstallAssets(ctx context.Context, req clients.InstallRequest)
 			AssetName: bundle.Asset.Name,
 		}
 
+		ctx, warnings := clients.WithWarnings(ctx)
 		var err error
 		switch bundle.Metadata.Asset.Type {
 		case asset.TypeMCP:
@@ -104,6 +108,9 @@ func (c *Client) InstallAssets(ctx context.Context, req clients.InstallRequest)
 		case asset.TypeCommand:
 			handler := handlers.NewCommandHandler(bundle.Metadata)
 			err = handler.Install(ctx, bundle.ZipData, targetBase)
+		case asset.TypeAgent:
+			handler := handlers.NewAgentHandler(bundle.Metadata)
+			err = handler.Install(ctx, bundle.ZipData, targetBase)
 		case asset.TypeHook:
 			handler := handlers.NewHookHandler(bundle.Metadata)
 			err = handler.Install(ctx, bundle.ZipData, targetBase)
@@ -114,6 +121,7 @@ func (c *Client) InstallAssets(ctx context.Context, req clients.InstallRequest)
 			continue
 		}
 
+		result.Warnings = warnings()
 		if err != nil {
 			result.Status = clients.StatusFailed
 			result.Error = err
@@ -167,6 +175,9 @@ func (c *Client) UninstallAssets(ctx context.Context, req clients.UninstallReque
 		case asset.TypeCommand:
 			handler := handlers.NewCommandHandler(meta)
 			err = handler.Remove(ctx, targetBase)
+		case asset.TypeAgent:
+			handler := handlers.NewAgentHandler(meta)
+			err = handler.Remove(ctx, targetBase)
 		case asset.TypeHook:
 			handler := handlers.NewHookHandler(meta)
 			err = handler.Remove(ctx, targetBase)
@@ -215,10 +226,10 @@ func (c *Client) determineTargetBase(scope *clients.InstallScope) (string, error
 }
 
 // EnsureAssetSupport ensures asset infrastructure is set up for the current context.
-// This scans skills from all applicable scopes (global, repo, path) and creates
-// a local .cursor/rules/skills.md file listing all available skills.
+// This scans skills and agents from all applicable scopes (global, repo, path) and
+// creates a local on-demand rule in .cursor/rules/ for each of them.
 // This must be called even when no new assets are installed, to ensure the
-// local rules file exists (Cursor doesn't load global rules).
+// local rules files exist (Cursor doesn't load global rules).
 func (c *Client) EnsureAssetSupport(ctx context.Context, scope *clients.InstallScope) error {
 	log := logger.Get()
 
@@ -227,9 +238,10 @@ func (c *Client) EnsureAssetSupport(ctx context.Context, scope *clients.InstallS
 		return fmt.Errorf("failed to register MCP server: %w", err)
 	}
 
-	// 2. Collect skills from all applicable scopes
+	// 2. Collect skills and agents from all applicable scopes
 	allSkills := c.collectAllScopeSkills(scope)
-	log.Debug("collected skills for rules file", "count", len(allSkills), "scope_type", scope.Type, "repo_root", scope.RepoRoot)
+	allAgents := c.collectAllScopeAgents(scope)
+	log.Debug("collected assets for rules files", "skills", len(allSkills), "agents", len(allAgents), "scope_type", scope.Type, "repo_root", scope.RepoRoot)
 
 	// 3. Determine local target (current working directory context)
 	localTarget := c.determineLocalTarget(scope)
@@ -239,19 +251,39 @@ func (c *Client) EnsureAssetSupport(ctx context.Context, scope *clients.InstallS
 		return nil
 	}
 
-	log.Debug("generating rules file", "target", localTarget, "skill_count", len(allSkills))
+	log.Debug("generating rules files", "target", localTarget, "skill_count", len(allSkills), "agent_count", len(allAgents))
 
-	// 4. Generate rules file with all skills
-	return c.generateSkillsRulesFileFromSkills(allSkills, localTarget)
+	// 4. Generate rules files with all skills and agents
+	if err := c.generateSkillRules(allSkills, localTarget); err != nil {
+		return err
+	}
+	return c.generateAgentRules(allAgents, localTarget)
+}
+
+// scopeBases returns the .cursor directories that apply to a scope, from
+// highest to lowest precedence (path > repo > global)
+func (c *Client) scopeBases(scope *clients.InstallScope) []string {
+	var bases []string
+	if scope.Type == clients.ScopePath && scope.RepoRoot != "" && scope.Path != "" {
+		bases = append(bases, filepath.Join(scope.RepoRoot, scope.Path, ".cursor"))
+	}
+	if scope.RepoRoot != "" {
+		bases = append(bases, filepath.Join(scope.RepoRoot, ".cursor"))
+	}
+	home, _ := os.UserHomeDir()
+	return append(bases, filepath.Join(home, ".cursor"))
 }
 
 // collectAllScopeSkills gathers skills from global, repo, and path scopes
-func (c *Client) collectAllScopeSkills(scope *clients.InstallScope) []clients.InstalledSkill {
-	var allSkills []clients.InstalledSkill
+func (c *Client) collectAllScopeSkills(scope *clients.InstallScope) []handlers.InstalledSkill {
+	var allSkills []handlers.InstalledSkill
 	seen := make(map[string]bool)
 
-	// Helper to add skills without duplicates (path > repo > global precedence)
-	addSkills := func(skills []clients.InstalledSkill) {
+	for _, base := range c.scopeBases(scope) {
+		skills, err := handlers.ScanInstalledSkills(base)
+		if err != nil {
+			continue
+		}
 		for _, skill := range skills {
 			if !seen[skill.Name] {
 				seen[skill.Name] = true
@@ -260,36 +292,28 @@ func (c *Client) collectAllScopeSkills(scope *clients.InstallScope) []clients.In
 		}
 	}
 
-	// 1. Path-scoped skills (highest precedence)
-	if scope.Type == clients.ScopePath && scope.RepoRoot != "" && scope.Path != "" {
-		pathBase := filepath.Join(scope.RepoRoot, scope.Path, ".cursor")
-		if skills, err := skillOps.ScanInstalled(pathBase); err == nil {
-			for _, s := range skills {
-				addSkills([]clients.InstalledSkill{{Name: s.Name, Description: s.Description, Version: s.Version}})
-			}
-		}
-	}
+	return allSkills
+}
 
-	// 2. Repo-scoped skills
-	if scope.RepoRoot != "" {
-		repoBase := filepath.Join(scope.RepoRoot, ".cursor")
-		if skills, err := skillOps.ScanInstalled(repoBase); err == nil {
-			for _, s := range skills {
-				addSkills([]clients.InstalledSkill{{Name: s.Name, Description: s.Description, Version: s.Version}})
-			}
-		}
-	}
+// collectAllScopeAgents gathers agents from global, repo, and path scopes
+func (c *Client) collectAllScopeAgents(scope *clients.InstallScope) []handlers.InstalledAgent {
+	var allAgents []handlers.InstalledAgent
+	seen := make(map[string]bool)
 
-	// 3. Global skills (lowest precedence)
-	home, _ := os.UserHomeDir()
-	globalBase := filepath.Join(home, ".cursor")
-	if skills, err := skillOps.ScanInstalled(globalBase); err == nil {
-		for _, s := range skills {
-			addSkills([]clients.InstalledSkill{{Name: s.Name, Description: s.Description, Version: s.Version}})
+	for _, base := range c.scopeBases(scope) {
+		agents, err := handlers.ScanInstalledAgents(base)
+		if err != nil {
+			continue
+		}
+		for _, agent := range agents {
+			if !seen[agent.Name] {
+				seen[agent.Name] = true
+				allAgents = append(allAgents, agent)
+			}
 		}
 	}
 
-	return allSkills
+	return allAgents
 }
 
 // determineLocalTarget returns the local .cursor directory for rules file
@@ -317,55 +341,94 @@ func (c *Client) determineLocalTarget(scope *clients.InstallScope) string {
 	}
 }
 
-// generateSkillsRulesFileFromSkills creates the rules file from a list of skills
-func (c *Client) generateSkillsRulesFileFromSkills(skills []clients.InstalledSkill, targetBase string) error {
-	rulesDir := filepath.Join(targetBase, "rules")
-	if err := os.MkdirAll(rulesDir, 0755); err != nil {
-		return err
-	}
-
-	rulePath := filepath.Join(rulesDir, "skills.md")
-
-	// If no skills, remove the rules file if it exists
-	if len(skills) == 0 {
-		if _, err := os.Stat(rulePath); err == nil {
-			return os.Remove(rulePath)
+// legacySkillsRule is the single always-applied rule listing every skill that
+// older versions generated, replaced by a rule per skill
+const legacySkillsRule = "skills.md"
+
+// generateSkillRules writes an on-demand rule for each skill, and removes the
+// legacy skills.md rule
+func (c *Client) generateSkillRules(skills []handlers.InstalledSkill, targetBase string) error {
+	legacyPath := filepath.Join(targetBase, "rules", legacySkillsRule)
+	if content, err := os.ReadFile(legacyPath); err == nil && strings.Contains(string(content), "AUTO-GENERATED by sx") {
+		if err := journal.Track(legacyPath); err != nil {
+			return err
+		}
+		if err := os.Remove(legacyPath); err != nil {
+			return fmt.Errorf("failed to remove legacy skills rule: %w", err)
 		}
-		return nil
 	}
 
-	// Build skill list
-	var skillsList string
+	rules := make(map[string][]byte, len(skills))
 	for _, skill := range skills {
-		skillsList += fmt.Sprintf("\n<skill>\n<name>%s</name>\n<description>%s</description>\n</skill>\n",
-			skill.Name, skill.Description)
+		content, err := handlers.RenderSkillRule(skill)
+		if err != nil {
+			return err
+		}
+		rules[skill.Name] = content
 	}
+	return c.writeRules(handlers.RuleKindSkill, rules, targetBase)
+}
 
-	// Generate complete skills.md with frontmatter
-	content := fmt.Sprintf(`---
-description: "Available skills for AI assistance"
-alwaysApply: true
----
-
-<!-- AUTO-GENERATED by sx - Do not edit manually -->
-<!-- Run 'sx install' to regenerate this file -->
-
-## Available Skills
-
-You have access to the following skills. When a user's task matches a skill, use the %sread_skill%s MCP tool to load full instructions.
+// generateAgentRules writes an on-demand rule for each agent
+func (c *Client) generateAgentRules(agents []handlers.InstalledAgent, targetBase string) error {
+	rules := make(map[string][]byte, len(agents))
+	for _, agent := range agents {
+		content, err := handlers.RenderAgentRule(agent)
+		if err != nil {
+			return err
+		}
+		rules[agent.Name] = content
+	}
+	return c.writeRules(handlers.RuleKindAgent, rules, targetBase)
+}
 
-<available_skills>
-%s
-</available_skills>
+// writeRules writes the rules of one kind, keyed by asset name, and removes
+// the rules of that kind for assets that are no longer installed in any
+// applicable scope
+func (c *Client) writeRules(kind string, rules map[string][]byte, targetBase string) error {
+	rulesDir := filepath.Join(targetBase, "rules")
 
-## Usage
+	wanted := make(map[string]bool)
+	for name, content := range rules {
+		if err := os.MkdirAll(rulesDir, 0755); err != nil {
+			return err
+		}
 
-Invoke %sread_skill(name: "skill-name")%s via the MCP tool when needed.
+		rulePath := filepath.Join(rulesDir, handlers.RuleFile(kind, name))
+		wanted[rulePath] = true
+		// Rules are regenerated on every install; only journal the ones that change
+		if existing, err := os.ReadFile(rulePath); err == nil && bytes.Equal(existing, content) {
+			continue
+		}
+		if err := journal.Track(rulePath); err != nil {
+			return err
+		}
+		if err := os.WriteFile(rulePath, content, 0644); err != nil {
+			return fmt.Errorf("failed to write rule for %s %s: %w", kind, name, err)
+		}
+	}
 
-The tool returns the skill content as markdown. Any %s@filename%s references in the content are automatically resolved to absolute paths.
-`, "`", "`", skillsList, "`", "`", "`", "`")
+	entries, err := os.ReadDir(rulesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read rules directory: %w", err)
+	}
+	for _, entry := range entries {
+		rulePath := filepath.Join(rulesDir, entry.Name())
+		if entry.IsDir() || wanted[rulePath] || !handlers.IsGeneratedRule(rulePath, kind) {
+			continue
+		}
+		if err := journal.Track(rulePath); err != nil {
+			return err
+		}
+		if err := os.Remove(rulePath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stale %s rule: %w", kind, err)
+		}
+	}
 
-	return os.WriteFile(rulePath, []byte(content), 0644)
+	return nil
 }
 
 // registerSkillsMCPServer adds skills MCP server to ~/.cursor/mcp.json
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/clients"
+	"github.com/sleuth-io/sx/internal/handlers/fileasset"
+	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/metadata"
+)
+
+var agentOps = fileasset.NewOperations("agents", &asset.TypeAgent)
+
+// AgentHandler handles agent asset installation for Cursor.
+// Agents are stored in .cursor/agents/{name}.md, and an on-demand rule is
+// generated for each one by the client's EnsureAssetSupport.
+type AgentHandler struct {
+	metadata *metadata.Metadata
+}
+
+// NewAgentHandler creates a new agent handler
+func NewAgentHandler(meta *metadata.Metadata) *AgentHandler {
+	return &AgentHandler{metadata: meta}
+}
+
+// Install writes the agent's prompt to .cursor/agents/{name}.md
+func (h *AgentHandler) Install(ctx context.Context, zipData []byte, targetBase string) error {
+	if h.metadata.Agent == nil || h.metadata.Agent.PromptFile == "" {
+		return fmt.Errorf("no prompt file specified in metadata")
+	}
+
+	// Cursor rules have no equivalent for these, so they're reported rather than dropped silently
+	var unsupported []string
+	if len(h.metadata.Agent.Tools) > 0 {
+		unsupported = append(unsupported, "tools")
+	}
+	if h.metadata.Agent.Model != "" {
+		unsupported = append(unsupported, "model")
+	}
+	if h.metadata.Agent.Color != "" {
+		unsupported = append(unsupported, "color")
+	}
+	if len(unsupported) > 0 {
+		clients.Warn(ctx, fmt.Sprintf("Cursor agents don't support %s; ignoring them for %s",
+			strings.Join(unsupported, " or "), h.metadata.Asset.Name))
+		logger.Get().Warn("cursor agent settings not supported", "name", h.metadata.Asset.Name, "settings", unsupported)
+	}
+
+	return agentOps.Install(ctx, zipData, targetBase, h.metadata.Asset.Name, h.metadata.Agent.PromptFile)
+}
+
+// Remove removes an agent from .cursor/agents/. Its rule is removed the next
+// time rules are generated.
+func (h *AgentHandler) Remove(ctx context.Context, targetBase string) error {
+	return agentOps.Remove(ctx, targetBase, h.metadata.Asset.Name)
+}
+
+// VerifyInstalled checks if the agent is installed with the expected version
+func (h *AgentHandler) VerifyInstalled(targetBase string) (bool, string) {
+	return agentOps.VerifyInstalled(targetBase, h.metadata.Asset.Name, h.metadata.Asset.Version)
+}
+
+// InstalledAgent is an agent found in a .cursor/agents directory
+type InstalledAgent struct {
+	Name       string
+	PromptPath string
+	Metadata   *metadata.Metadata
+}
+
+// ScanInstalledAgents returns the agents installed under targetBase
+func ScanInstalledAgents(targetBase string) ([]InstalledAgent, error) {
+	installed, err := agentOps.ScanInstalled(targetBase)
+	if err != nil {
+		return nil, err
+	}
+
+	agents := make([]InstalledAgent, 0, len(installed))
+	for _, info := range installed {
+		promptPath := agentOps.GetAssetPath(targetBase, info.Name)
+		meta, err := metadata.ParseFile(strings.TrimSuffix(promptPath, ".md") + "-metadata.toml")
+		if err != nil {
+			continue
+		}
+		agents = append(agents, InstalledAgent{Name: info.Name, PromptPath: promptPath, Metadata: meta})
+	}
+	return agents, nil
+}
+
+// RenderAgentRule renders an agent as an on-demand Cursor rule: the agent's
+// prompt, applied when the request matches its triggers
+func RenderAgentRule(agent InstalledAgent) ([]byte, error) {
+	prompt, err := os.ReadFile(agent.PromptPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent %s: %w", agent.Name, err)
+	}
+
+	description := agent.Metadata.Asset.Description
+	if agent.Metadata.Agent != nil && len(agent.Metadata.Agent.Triggers) > 0 {
+		description = strings.Join(agent.Metadata.Agent.Triggers, "; ")
+	}
+	if description == "" {
+		description = "Act as the " + agent.Name + " agent"
+	}
+
+	return renderRule(RuleKindAgent, agent.Name, description, nil, fileasset.StripFrontmatter(prompt))
+}
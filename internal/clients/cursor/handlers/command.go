@@ -5,7 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/sleuth-io/sx/internal/clients"
+	"github.com/sleuth-io/sx/internal/handlers/fileasset"
+	"github.com/sleuth-io/sx/internal/journal"
+	"github.com/sleuth-io/sx/internal/logger"
 	"github.com/sleuth-io/sx/internal/metadata"
 	"github.com/sleuth-io/sx/internal/utils"
 )
@@ -38,19 +43,41 @@ func (h *CommandHandler) Install(ctx context.Context, zipData []byte, targetBase
 	if err != nil {
 		return fmt.Errorf("failed to read prompt file: %w", err)
 	}
+	if h.metadata.Command != nil {
+		promptContent = h.withCommandDetails(ctx, promptContent)
+		if h.metadata.Command.Dangerous {
+			promptContent = fileasset.WithDangerousPreamble(promptContent)
+		}
+	}
 
 	// Write to .cursor/commands/{name}.md
 	destPath := filepath.Join(commandsDir, h.metadata.Asset.Name+".md")
+	if err := journal.Track(destPath); err != nil {
+		return err
+	}
 	if err := os.WriteFile(destPath, promptContent, 0644); err != nil {
 		return fmt.Errorf("failed to write command file: %w", err)
 	}
 
+	// Aliases are extra entry points to the same prompt
+	if err := fileasset.RemoveAliases(commandsDir, h.metadata.Asset.Name); err != nil {
+		return err
+	}
+	if h.metadata.Command != nil {
+		return fileasset.InstallAliases(ctx, commandsDir, h.metadata.Asset.Name, destPath, h.metadata.Command.Aliases)
+	}
 	return nil
 }
 
-// Remove removes a slash command from Cursor
+// Remove removes a slash command and its aliases from Cursor
 func (h *CommandHandler) Remove(ctx context.Context, targetBase string) error {
+	if err := fileasset.RemoveAliases(filepath.Join(targetBase, "commands"), h.metadata.Asset.Name); err != nil {
+		return err
+	}
 	commandFile := filepath.Join(targetBase, "commands", h.metadata.Asset.Name+".md")
+	if err := journal.Track(commandFile); err != nil {
+		return err
+	}
 	if err := os.Remove(commandFile); err != nil {
 		if os.IsNotExist(err) {
 			return nil // Already removed
@@ -60,6 +87,38 @@ func (h *CommandHandler) Remove(ctx context.Context, targetBase string) error {
 	return nil
 }
 
+// withCommandDetails adds the command's description and argument hint to the
+// prompt. Cursor commands have no frontmatter, so they lead the prompt instead,
+// and settings Cursor can't apply (allowed-tools, model) are reported.
+func (h *CommandHandler) withCommandDetails(ctx context.Context, prompt []byte) []byte {
+	command := h.metadata.Command
+
+	var unsupported []string
+	if len(command.AllowedTools) > 0 {
+		unsupported = append(unsupported, "allowed-tools")
+	}
+	if command.Model != "" {
+		unsupported = append(unsupported, "model")
+	}
+	if len(unsupported) > 0 {
+		clients.Warn(ctx, fmt.Sprintf("Cursor commands don't support %s; ignoring them for %s",
+			strings.Join(unsupported, " or "), h.metadata.Asset.Name))
+		logger.Get().Warn("cursor command settings not supported", "name", h.metadata.Asset.Name, "settings", unsupported)
+	}
+
+	var header strings.Builder
+	if command.Description != "" {
+		header.WriteString(command.Description + "\n\n")
+	}
+	if command.ArgumentHint != "" {
+		header.WriteString("Arguments: " + command.ArgumentHint + "\n\n")
+	}
+	if header.Len() == 0 {
+		return prompt
+	}
+	return append([]byte(header.String()), prompt...)
+}
+
 func (h *CommandHandler) getPromptFile() string {
 	// Check both Skill and Command metadata sections (for skill → command transformation)
 	if h.metadata.Skill != nil && h.metadata.Skill.PromptFile != "" {
@@ -2,13 +2,15 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/clientconfig"
 	"github.com/sleuth-io/sx/internal/handlers/dirasset"
+	"github.com/sleuth-io/sx/internal/journal"
 	"github.com/sleuth-io/sx/internal/metadata"
 	"github.com/sleuth-io/sx/internal/utils"
 )
@@ -34,6 +36,9 @@ func (h *HookHandler) Install(ctx context.Context, zipData []byte, targetBase st
 
 	// Extract to .cursor/hooks/{name}/
 	installPath := filepath.Join(targetBase, "hooks", h.metadata.Asset.Name)
+	if err := journal.Track(installPath); err != nil {
+		return err
+	}
 	if err := os.RemoveAll(installPath); err != nil {
 		return fmt.Errorf("failed to remove existing hook: %w", err)
 	}
@@ -61,6 +66,9 @@ func (h *HookHandler) Remove(ctx context.Context, targetBase string) error {
 
 	// Remove directory
 	installPath := filepath.Join(targetBase, "hooks", h.metadata.Asset.Name)
+	if err := journal.Track(installPath); err != nil {
+		return err
+	}
 	if err := os.RemoveAll(installPath); err != nil {
 		return fmt.Errorf("failed to remove hook directory: %w", err)
 	}
@@ -99,16 +107,12 @@ type HooksConfig struct {
 func (h *HookHandler) updateHooksJSON(targetBase string) error {
 	hooksJSONPath := filepath.Join(targetBase, "hooks.json")
 
-	config, err := ReadHooksJSON(hooksJSONPath)
-	if err != nil {
-		return err
-	}
-
 	// Map event to Cursor lifecycle hook
 	cursorEvent := mapEventToCursorHook(h.metadata.Hook.Event)
 	if cursorEvent == "" {
 		return fmt.Errorf("unsupported hook event for Cursor: %s (supported: pre-commit, post-commit, pre-push, on-save, on-file-read)", h.metadata.Hook.Event)
 	}
+	entryKey := clientconfig.EntryKey("hooks", cursorEvent, h.metadata.Asset.Name)
 
 	// Build entry with absolute path to script
 	scriptPath := filepath.Join(targetBase, "hooks", h.metadata.Asset.Name, h.metadata.Hook.ScriptFile)
@@ -117,67 +121,91 @@ func (h *HookHandler) updateHooksJSON(targetBase string) error {
 		"_artifact": h.metadata.Asset.Name,
 	}
 
-	// Add to hooks array
-	if config.Hooks[cursorEvent] == nil {
-		config.Hooks[cursorEvent] = []map[string]interface{}{}
-	}
+	return clientconfig.Update(hooksJSONPath, func(doc *clientconfig.Document) error {
+		if _, ok := doc.Get("version"); !ok {
+			if err := doc.Set(1, "version"); err != nil {
+				return err
+			}
+		}
+
+		existing, _ := doc.Get("hooks", cursorEvent)
+		hooks, _ := existing.([]interface{})
 
-	// Remove existing entry for this asset (if any)
-	filtered := []map[string]interface{}{}
-	for _, hook := range config.Hooks[cursorEvent] {
-		if assetName, ok := hook["_artifact"].(string); !ok || assetName != h.metadata.Asset.Name {
+		// Replace existing entry for this asset (if any)
+		filtered := []interface{}{}
+		for _, hook := range hooks {
+			if h.isOwnEntry(hook) {
+				if !doc.MayReplace(entryKey, hook, entry) {
+					return nil // Keep the user's edited entry
+				}
+				continue
+			}
 			filtered = append(filtered, hook)
 		}
-	}
+		filtered = append(filtered, entry)
 
-	// Add new entry
-	filtered = append(filtered, entry)
-	config.Hooks[cursorEvent] = filtered
-
-	return WriteHooksJSON(hooksJSONPath, config)
+		if err := doc.Set(filtered, "hooks", cursorEvent); err != nil {
+			return err
+		}
+		doc.Claim(entryKey, entry)
+		return nil
+	})
 }
 
 func (h *HookHandler) removeFromHooksJSON(targetBase string) error {
 	hooksJSONPath := filepath.Join(targetBase, "hooks.json")
 
-	config, err := ReadHooksJSON(hooksJSONPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil // Nothing to remove
-		}
-		return err
-	}
+	return clientconfig.Update(hooksJSONPath, func(doc *clientconfig.Document) error {
+		existing, _ := doc.Get("hooks")
+		events, _ := existing.(map[string]interface{})
 
-	// Remove from all hook types
-	for eventName, hooks := range config.Hooks {
-		filtered := []map[string]interface{}{}
-		for _, hook := range hooks {
-			if assetName, ok := hook["_artifact"].(string); !ok || assetName != h.metadata.Asset.Name {
+		// Remove from all hook types
+		for eventName, value := range events {
+			hooks, ok := value.([]interface{})
+			if !ok {
+				continue
+			}
+			entryKey := clientconfig.EntryKey("hooks", eventName, h.metadata.Asset.Name)
+
+			filtered := []interface{}{}
+			for _, hook := range hooks {
+				if h.isOwnEntry(hook) && doc.MayReplace(entryKey, hook, nil) {
+					continue
+				}
 				filtered = append(filtered, hook)
 			}
+			doc.Release(entryKey)
+
+			if len(filtered) != len(hooks) {
+				if err := doc.Set(filtered, "hooks", eventName); err != nil {
+					return err
+				}
+			}
 		}
-		config.Hooks[eventName] = filtered
-	}
+		return nil
+	})
+}
 
-	return WriteHooksJSON(hooksJSONPath, config)
+// isOwnEntry reports whether a hooks.json entry belongs to this asset
+func (h *HookHandler) isOwnEntry(hook interface{}) bool {
+	hookMap, ok := hook.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	assetName, ok := hookMap["_artifact"].(string)
+	return ok && assetName == h.metadata.Asset.Name
 }
 
 // ReadHooksJSON reads and parses the hooks.json file
+// Comments and trailing commas are accepted
 func ReadHooksJSON(path string) (*HooksConfig, error) {
-	config := &HooksConfig{
-		Version: 1,
-		Hooks:   make(map[string][]map[string]interface{}),
-	}
-
-	data, err := os.ReadFile(path)
+	doc, err := clientconfig.Load(path)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return config, nil
-		}
 		return nil, err
 	}
 
-	if err := json.Unmarshal(data, config); err != nil {
+	config := &HooksConfig{Version: 1}
+	if err := doc.Decode(config); err != nil {
 		return nil, err
 	}
 
@@ -189,17 +217,39 @@ func ReadHooksJSON(path string) (*HooksConfig, error) {
 }
 
 // WriteHooksJSON writes the hooks config to the hooks.json file
+// Only events that changed are rewritten, so the rest of the file keeps its formatting
 func WriteHooksJSON(path string, config *HooksConfig) error {
-	if err := utils.EnsureDir(filepath.Dir(path)); err != nil {
-		return err
-	}
+	return clientconfig.Update(path, func(doc *clientconfig.Document) error {
+		if err := doc.Set(config.Version, "version"); err != nil {
+			return err
+		}
 
-	data, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return err
-	}
+		current, _ := doc.Get("hooks")
+		currentEvents, _ := current.(map[string]interface{})
+		for event := range currentEvents {
+			if _, ok := config.Hooks[event]; !ok {
+				if _, err := doc.Delete("hooks", event); err != nil {
+					return err
+				}
+			}
+		}
 
-	return os.WriteFile(path, data, 0644)
+		events := make([]string, 0, len(config.Hooks))
+		for event := range config.Hooks {
+			events = append(events, event)
+		}
+		sort.Strings(events)
+		for _, event := range events {
+			if err := doc.Set(config.Hooks[event], "hooks", event); err != nil {
+				return err
+			}
+		}
+
+		if _, ok := doc.Get("hooks"); !ok {
+			return doc.Set(map[string]interface{}{}, "hooks")
+		}
+		return nil
+	})
 }
 
 // mapEventToCursorHook maps Skills hook events to Cursor lifecycle hooks
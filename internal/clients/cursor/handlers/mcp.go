@@ -2,13 +2,15 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/clientconfig"
 	"github.com/sleuth-io/sx/internal/handlers/dirasset"
+	"github.com/sleuth-io/sx/internal/journal"
 	"github.com/sleuth-io/sx/internal/metadata"
 	"github.com/sleuth-io/sx/internal/utils"
 )
@@ -29,14 +31,11 @@ func NewMCPHandler(meta *metadata.Metadata) *MCPHandler {
 func (h *MCPHandler) Install(ctx context.Context, zipData []byte, targetBase string) error {
 	mcpConfigPath := filepath.Join(targetBase, "mcp.json")
 
-	// Read existing mcp.json
-	config, err := ReadMCPConfig(mcpConfigPath)
-	if err != nil {
-		return fmt.Errorf("failed to read mcp.json: %w", err)
-	}
-
 	// Extract MCP server files to .cursor/mcp-servers/{name}/
 	serverDir := filepath.Join(targetBase, "mcp-servers", h.metadata.Asset.Name)
+	if err := journal.Track(serverDir); err != nil {
+		return err
+	}
 	if err := utils.ExtractZip(zipData, serverDir); err != nil {
 		return fmt.Errorf("failed to extract MCP server: %w", err)
 	}
@@ -44,15 +43,13 @@ func (h *MCPHandler) Install(ctx context.Context, zipData []byte, targetBase str
 	// Generate MCP entry from metadata (with paths relative to extraction)
 	entry := h.generateMCPEntry(serverDir)
 
-	// Add to config
-	if config.MCPServers == nil {
-		config.MCPServers = make(map[string]interface{})
-	}
-	config.MCPServers[h.metadata.Asset.Name] = entry
-
-	// Write updated mcp.json
-	if err := WriteMCPConfig(mcpConfigPath, config); err != nil {
-		return fmt.Errorf("failed to write mcp.json: %w", err)
+	// Add to mcp.json unless the user has edited the entry
+	err := clientconfig.Update(mcpConfigPath, func(doc *clientconfig.Document) error {
+		_, err := doc.SetOwned(entry, "mcpServers", h.metadata.Asset.Name)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update mcp.json: %w", err)
 	}
 
 	return nil
@@ -62,22 +59,20 @@ func (h *MCPHandler) Install(ctx context.Context, zipData []byte, targetBase str
 func (h *MCPHandler) Remove(ctx context.Context, targetBase string) error {
 	mcpConfigPath := filepath.Join(targetBase, "mcp.json")
 
-	// Read existing mcp.json
-	config, err := ReadMCPConfig(mcpConfigPath)
+	// Remove entry from mcp.json
+	err := clientconfig.Update(mcpConfigPath, func(doc *clientconfig.Document) error {
+		_, err := doc.DeleteOwned("mcpServers", h.metadata.Asset.Name)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to read mcp.json: %w", err)
-	}
-
-	// Remove entry
-	delete(config.MCPServers, h.metadata.Asset.Name)
-
-	// Write updated mcp.json
-	if err := WriteMCPConfig(mcpConfigPath, config); err != nil {
-		return fmt.Errorf("failed to write mcp.json: %w", err)
+		return fmt.Errorf("failed to update mcp.json: %w", err)
 	}
 
 	// Remove server directory (if exists)
 	serverDir := filepath.Join(targetBase, "mcp-servers", h.metadata.Asset.Name)
+	if err := journal.Track(serverDir); err != nil {
+		return err
+	}
 	os.RemoveAll(serverDir) // Ignore errors if doesn't exist
 
 	return nil
@@ -122,34 +117,54 @@ type MCPConfig struct {
 }
 
 // ReadMCPConfig reads Cursor's mcp.json file
+// Comments and trailing commas are accepted
 func ReadMCPConfig(path string) (*MCPConfig, error) {
-	config := &MCPConfig{
-		MCPServers: make(map[string]interface{}),
-	}
-
-	data, err := os.ReadFile(path)
+	doc, err := clientconfig.Load(path)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return config, nil // Return empty config
-		}
 		return nil, err
 	}
 
-	if err := json.Unmarshal(data, config); err != nil {
+	config := &MCPConfig{}
+	if err := doc.Decode(config); err != nil {
 		return nil, err
 	}
+	if config.MCPServers == nil {
+		config.MCPServers = make(map[string]interface{})
+	}
 
 	return config, nil
 }
 
 // WriteMCPConfig writes Cursor's mcp.json file
+// Only servers that changed are rewritten, so the rest of the file keeps its formatting
 func WriteMCPConfig(path string, config *MCPConfig) error {
-	data, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
-		return err
-	}
+	return clientconfig.Update(path, func(doc *clientconfig.Document) error {
+		current, _ := doc.Get("mcpServers")
+		currentServers, _ := current.(map[string]interface{})
+		for name := range currentServers {
+			if _, ok := config.MCPServers[name]; !ok {
+				if _, err := doc.Delete("mcpServers", name); err != nil {
+					return err
+				}
+			}
+		}
 
-	return os.WriteFile(path, data, 0644)
+		names := make([]string, 0, len(config.MCPServers))
+		for name := range config.MCPServers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := doc.Set(config.MCPServers[name], "mcpServers", name); err != nil {
+				return err
+			}
+		}
+
+		if _, ok := doc.Get("mcpServers"); !ok {
+			return doc.Set(map[string]interface{}{}, "mcpServers")
+		}
+		return nil
+	})
 }
 
 // VerifyInstalled checks if the MCP server is properly installed
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/sleuth-io/sx/internal/clientconfig"
+	"github.com/sleuth-io/sx/internal/clients"
+	"github.com/sleuth-io/sx/internal/logger"
 	"github.com/sleuth-io/sx/internal/metadata"
 )
 
@@ -23,24 +26,23 @@ func NewMCPRemoteHandler(meta *metadata.Metadata) *MCPRemoteHandler {
 func (h *MCPRemoteHandler) Install(ctx context.Context, zipData []byte, targetBase string) error {
 	mcpConfigPath := filepath.Join(targetBase, "mcp.json")
 
-	// Read existing mcp.json
-	config, err := ReadMCPConfig(mcpConfigPath)
-	if err != nil {
-		return fmt.Errorf("failed to read mcp.json: %w", err)
+	// Cursor runs the OAuth flow for remote servers itself and has no setting for
+	// a client ID or callback port, so the hints are reported rather than dropped silently
+	if h.metadata.MCP.IsRemote() && h.metadata.MCP.OAuth != nil {
+		clients.Warn(ctx, fmt.Sprintf("Cursor doesn't support OAuth client settings; ignoring them for %s", h.metadata.Asset.Name))
+		logger.Get().Warn("cursor mcp oauth settings not supported", "name", h.metadata.Asset.Name)
 	}
 
 	// Generate MCP entry from metadata (no path conversion for remote)
 	entry := h.generateMCPEntry()
 
-	// Add to config
-	if config.MCPServers == nil {
-		config.MCPServers = make(map[string]interface{})
-	}
-	config.MCPServers[h.metadata.Asset.Name] = entry
-
-	// Write updated mcp.json
-	if err := WriteMCPConfig(mcpConfigPath, config); err != nil {
-		return fmt.Errorf("failed to write mcp.json: %w", err)
+	// Add to mcp.json unless the user has edited the entry
+	err := clientconfig.Update(mcpConfigPath, func(doc *clientconfig.Document) error {
+		_, err := doc.SetOwned(entry, "mcpServers", h.metadata.Asset.Name)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update mcp.json: %w", err)
 	}
 
 	return nil
@@ -50,18 +52,13 @@ func (h *MCPRemoteHandler) Install(ctx context.Context, zipData []byte, targetBa
 func (h *MCPRemoteHandler) Remove(ctx context.Context, targetBase string) error {
 	mcpConfigPath := filepath.Join(targetBase, "mcp.json")
 
-	// Read existing mcp.json
-	config, err := ReadMCPConfig(mcpConfigPath)
+	// Remove entry from mcp.json
+	err := clientconfig.Update(mcpConfigPath, func(doc *clientconfig.Document) error {
+		_, err := doc.DeleteOwned("mcpServers", h.metadata.Asset.Name)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to read mcp.json: %w", err)
-	}
-
-	// Remove entry
-	delete(config.MCPServers, h.metadata.Asset.Name)
-
-	// Write updated mcp.json
-	if err := WriteMCPConfig(mcpConfigPath, config); err != nil {
-		return fmt.Errorf("failed to write mcp.json: %w", err)
+		return fmt.Errorf("failed to update mcp.json: %w", err)
 	}
 
 	return nil
@@ -70,6 +67,17 @@ func (h *MCPRemoteHandler) Remove(ctx context.Context, targetBase string) error
 func (h *MCPRemoteHandler) generateMCPEntry() map[string]interface{} {
 	mcpConfig := h.metadata.MCP
 
+	// Cursor connects to remote servers by URL, picking the transport itself
+	if mcpConfig.IsRemote() {
+		entry := map[string]interface{}{
+			"url": mcpConfig.URL,
+		}
+		if len(mcpConfig.Headers) > 0 {
+			entry["headers"] = mcpConfig.Headers
+		}
+		return entry
+	}
+
 	// For remote MCPs, commands are external (npx, docker, etc.)
 	// No path conversion needed
 	args := make([]interface{}, len(mcpConfig.Args))
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sleuth-io/sx/internal/handlers/fileasset"
+)
+
+// Kinds of rules generated into .cursor/rules/
+const (
+	RuleKindSkill = "skill"
+	RuleKindAgent = "agent"
+)
+
+// ruleMarker identifies rules generated by sx, so stale ones can be cleaned up
+// without touching the user's own rules
+func ruleMarker(kind, name string) string {
+	return fmt.Sprintf("<!-- sx:%s %s -->", kind, name)
+}
+
+// RuleFile returns the file name of an asset's rule in .cursor/rules/
+func RuleFile(kind, name string) string {
+	return kind + "-" + name + ".mdc"
+}
+
+// IsGeneratedRule reports whether a rule file was generated by sx for an asset of the given kind
+func IsGeneratedRule(path, kind string) bool {
+	base := filepath.Base(path)
+	if !strings.HasPrefix(base, kind+"-") || !strings.HasSuffix(base, ".mdc") {
+		return false
+	}
+	content, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(content), "<!-- sx:"+kind+" ")
+}
+
+// renderRule renders an on-demand rule. Cursor applies it when a request
+// matches the description, or when a file matching globs is in context.
+func renderRule(kind, name, description string, globs []string, body []byte) ([]byte, error) {
+	content := fmt.Sprintf("%s\n<!-- AUTO-GENERATED by sx - Do not edit manually -->\n\n%s", ruleMarker(kind, name), body)
+	return fileasset.SetFrontmatter([]byte(content), []fileasset.Field{
+		{Key: "description", Value: description},
+		{Key: "globs", Value: strings.Join(globs, ",")},
+		{Key: "alwaysApply", Value: "false"},
+	})
+}
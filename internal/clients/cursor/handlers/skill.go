@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/sleuth-io/sx/internal/asset"
 	"github.com/sleuth-io/sx/internal/handlers/dirasset"
+	"github.com/sleuth-io/sx/internal/handlers/fileasset"
+	"github.com/sleuth-io/sx/internal/journal"
 	"github.com/sleuth-io/sx/internal/metadata"
 	"github.com/sleuth-io/sx/internal/utils"
 )
@@ -29,6 +32,11 @@ func NewSkillHandler(meta *metadata.Metadata) *SkillHandler {
 func (h *SkillHandler) Install(ctx context.Context, zipData []byte, targetBase string) error {
 	skillsDir := filepath.Join(targetBase, "skills", h.metadata.Asset.Name)
 
+	// Snapshot existing installation so the install can be rolled back
+	if err := journal.Track(skillsDir); err != nil {
+		return err
+	}
+
 	// Remove existing installation if present
 	if utils.IsDirectory(skillsDir) {
 		if err := os.RemoveAll(skillsDir); err != nil {
@@ -58,6 +66,10 @@ func (h *SkillHandler) Remove(ctx context.Context, targetBase string) error {
 		return nil
 	}
 
+	if err := journal.Track(skillsDir); err != nil {
+		return err
+	}
+
 	if err := os.RemoveAll(skillsDir); err != nil {
 		return fmt.Errorf("failed to remove skill: %w", err)
 	}
@@ -69,3 +81,67 @@ func (h *SkillHandler) Remove(ctx context.Context, targetBase string) error {
 func (h *SkillHandler) VerifyInstalled(targetBase string) (bool, string) {
 	return skillOps.VerifyInstalled(targetBase, h.metadata.Asset.Name, h.metadata.Asset.Version)
 }
+
+// inlineSkillLimit is the largest skill prompt inlined into its rule. Larger
+// skills are loaded on demand through the read_skill MCP tool.
+const inlineSkillLimit = 2 * 1024
+
+// InstalledSkill is a skill found in a .cursor/skills directory
+type InstalledSkill struct {
+	Name     string
+	Dir      string
+	Metadata *metadata.Metadata
+}
+
+// ScanInstalledSkills returns the skills installed under targetBase
+func ScanInstalledSkills(targetBase string) ([]InstalledSkill, error) {
+	installed, err := skillOps.ScanInstalled(targetBase)
+	if err != nil {
+		return nil, err
+	}
+
+	skills := make([]InstalledSkill, 0, len(installed))
+	for _, info := range installed {
+		dir := skillOps.GetAssetDir(targetBase, info.Name)
+		meta, err := metadata.ParseFile(filepath.Join(dir, "metadata.toml"))
+		if err != nil {
+			continue
+		}
+		skills = append(skills, InstalledSkill{Name: info.Name, Dir: dir, Metadata: meta})
+	}
+	return skills, nil
+}
+
+// RenderSkillRule renders a skill as an on-demand Cursor rule. Small,
+// self-contained skills are inlined; others point to the read_skill MCP tool.
+func RenderSkillRule(skill InstalledSkill) ([]byte, error) {
+	description := skill.Metadata.Asset.Description
+	var globs []string
+	promptFile := "SKILL.md"
+	if config := skill.Metadata.Skill; config != nil {
+		if len(config.Triggers) > 0 {
+			when := "Use when: " + strings.Join(config.Triggers, "; ") + "."
+			if description != "" {
+				when = strings.TrimSuffix(description, ".") + ". " + when
+			}
+			description = when
+		}
+		globs = config.Globs
+		if config.PromptFile != "" {
+			promptFile = config.PromptFile
+		}
+	}
+	if description == "" {
+		description = "The " + skill.Name + " skill"
+	}
+
+	// @file references are only resolved by read_skill, so those skills aren't inlined
+	prompt, err := os.ReadFile(filepath.Join(skill.Dir, promptFile))
+	if err == nil && len(prompt) <= inlineSkillLimit && !utils.FileRefPattern.Match(prompt) {
+		return renderRule(RuleKindSkill, skill.Name, description, globs, fileasset.StripFrontmatter(prompt))
+	}
+
+	body := fmt.Sprintf("Before working on this, load the full instructions of the `%s` skill by invoking `read_skill(name: \"%s\")` via the MCP tool, and follow them.\n",
+		skill.Name, skill.Name)
+	return renderRule(RuleKindSkill, skill.Name, description, globs, []byte(body))
+}
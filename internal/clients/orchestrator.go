@@ -2,7 +2,10 @@ package clients
 
 import (
 	"context"
+	"strconv"
 	"sync"
+
+	"github.com/sleuth-io/sx/internal/telemetry"
 )
 
 // Orchestrator coordinates installation across multiple clients
@@ -65,7 +68,12 @@ func (o *Orchestrator) InstallToClients(ctx context.Context,
 				Options: options,
 			}
 
-			resp, err := client.InstallAssets(ctx, req)
+			spanCtx, span := telemetry.Get().StartSpan(ctx, "sx.install.client",
+				"sx.client", client.ID(),
+				"sx.scope", string(scope.Type),
+				"sx.assets.count", strconv.Itoa(len(compatibleAssets)))
+			resp, err := client.InstallAssets(spanCtx, req)
+			span.End(err)
 			if err != nil {
 				// Client returned error - ensure all results marked as failed
 				for i := range resp.Results {
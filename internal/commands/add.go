@@ -39,7 +39,17 @@ Examples:
   sx add ./my-skill           # Add from local directory
   sx add https://...          # Add from URL
   sx add https://github.com/owner/repo/tree/main/path  # Add from GitHub
-  sx add my-skill             # Configure scope for existing asset`,
+  sx add my-skill             # Configure scope for existing asset
+  sx add ./my-skill --propose # Push to a review branch instead of publishing
+  sx add ./my-mcp --check     # Start the MCP server first and record its tools
+
+With --propose (or review-required = true in the vault's vault.toml), changes to a
+git vault are pushed to a branch named after the asset and version, and a pull
+request URL is printed. The asset goes live once the branch is merged.
+
+With --check, MCP servers are started as with 'sx mcp check' before publishing;
+the add fails if the server doesn't start, and the tools it advertises are
+recorded in [mcp] capabilities.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var zipFile string
@@ -50,6 +60,9 @@ Examples:
 		},
 	}
 
+	cmd.Flags().Bool("propose", false, "Push to a proposal branch for review instead of publishing (git vaults only)")
+	cmd.Flags().Bool("check", false, "Start MCP servers before publishing and record their tools")
+
 	return cmd
 }
 
@@ -92,7 +105,7 @@ func runAddWithOptions(cmd *cobra.Command, input string, promptInstall bool) err
 	}
 
 	// Create vault instance
-	vault, err := createVault()
+	vault, err := createVault(proposeRequested(cmd))
 	if err != nil {
 		return err
 	}
@@ -109,13 +122,18 @@ func runAddWithOptions(cmd *cobra.Command, input string, promptInstall bool) err
 		addErr = handleIdenticalAsset(ctx, out, status, vault, name, version, assetType)
 	} else {
 		// Add new or updated asset
-		addErr = addNewAsset(ctx, out, status, vault, name, assetType, version, zipFile, zipData, metadataExists)
+		addErr = addNewAsset(ctx, out, status, vault, name, assetType, version, zipFile, zipData, metadataExists, mcpCheckRequested(cmd))
 	}
 
 	if addErr != nil {
 		return addErr
 	}
 
+	// Proposed assets aren't live until merged, so there's nothing to install yet
+	if reportProposal(out, vault) {
+		return nil
+	}
+
 	// Prompt to run install (if enabled)
 	if promptInstall {
 		promptRunInstall(cmd, ctx, out)
@@ -127,7 +145,7 @@ func runAddWithOptions(cmd *cobra.Command, input string, promptInstall bool) err
 // configureExistingAsset handles configuring scope for an asset that already exists in the vault
 func configureExistingAsset(ctx context.Context, cmd *cobra.Command, out *outputHelper, status *components.Status, assetName string, promptInstall bool) error {
 	// Create vault instance
-	vault, err := createVault()
+	vault, err := createVault(proposeRequested(cmd))
 	if err != nil {
 		return err
 	}
@@ -229,7 +247,8 @@ func configureExistingAsset(ctx context.Context, cmd *cobra.Command, out *output
 			SourcePath: &lockfile.SourcePath{
 				Path: fmt.Sprintf("./assets/%s/%s", assetName, latestVersion),
 			},
-			Scopes: repositories,
+			Scopes:   repositories,
+			Audience: promptForAudience(out, nil),
 		}
 
 		// Add to lock file
@@ -237,6 +256,10 @@ func configureExistingAsset(ctx context.Context, cmd *cobra.Command, out *output
 			return fmt.Errorf("failed to update lock file: %w", err)
 		}
 
+		if reportProposal(out, vault) {
+			return nil
+		}
+
 		// Prompt to run install (if enabled)
 		if promptInstall {
 			promptRunInstall(cmd, ctx, out)
@@ -269,16 +292,16 @@ func configureExistingAsset(ctx context.Context, cmd *cobra.Command, out *output
 				return fmt.Errorf("failed to remove asset from lock file: %w", err)
 			}
 		} else if gitVault, ok := vault.(*vaultpkg.GitVault); ok {
-			lockFilePath := gitVault.GetLockFilePath()
-			if err := lockfile.RemoveAsset(lockFilePath, foundAsset.Name, foundAsset.Version); err != nil {
-				return fmt.Errorf("failed to remove asset from lock file: %w", err)
-			}
-			// Commit and push the removal
-			if err := gitVault.CommitAndPush(ctx, foundAsset); err != nil {
-				return fmt.Errorf("failed to push removal: %w", err)
+			// Remove, commit and push (retried if another writer pushed first)
+			if err := gitVault.RemoveAsset(ctx, foundAsset.Name, foundAsset.Version); err != nil {
+				return err
 			}
 		}
 
+		if reportProposal(out, vault) {
+			return nil
+		}
+
 		// Prompt to run install to clean up the removed asset (if enabled)
 		if promptInstall {
 			out.println()
@@ -289,7 +312,7 @@ func configureExistingAsset(ctx context.Context, cmd *cobra.Command, out *output
 
 			if confirmed {
 				out.println()
-				if err := runInstall(cmd, nil, false, "", false); err != nil {
+				if err := runInstall(cmd, nil, false, "", false, false); err != nil {
 					out.printfErr("Install failed: %v\n", err)
 				}
 			} else {
@@ -299,14 +322,19 @@ func configureExistingAsset(ctx context.Context, cmd *cobra.Command, out *output
 		return nil
 	}
 
-	// Update asset with new repositories
+	// Update asset with new repositories and audience
 	foundAsset.Scopes = scopes
+	foundAsset.Audience = promptForAudience(out, foundAsset.Audience)
 
 	// Update lock file
 	if err := updateLockFile(ctx, out, vault, foundAsset); err != nil {
 		return fmt.Errorf("failed to update lock file: %w", err)
 	}
 
+	if reportProposal(out, vault) {
+		return nil
+	}
+
 	// Prompt to run install (if enabled)
 	if promptInstall {
 		promptRunInstall(cmd, ctx, out)
@@ -329,7 +357,7 @@ func promptRunInstall(cmd *cobra.Command, ctx context.Context, out *outputHelper
 	}
 
 	out.println()
-	if err := runInstall(cmd, nil, false, "", false); err != nil {
+	if err := runInstall(cmd, nil, false, "", false, false); err != nil {
 		out.printfErr("Install failed: %v\n", err)
 	}
 }
@@ -519,13 +547,75 @@ func detectAssetInfo(out *outputHelper, status *components.Status, zipFile strin
 }
 
 // createVault loads config and creates a vault instance
-func createVault() (vaultpkg.Vault, error) {
+// With propose set, writes go to a proposal branch, which only git vaults support.
+func createVault(propose bool) (vaultpkg.Vault, error) {
 	cfg, err := config.Load()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load configuration: %w\nRun 'sx init' to configure", err)
 	}
 
-	return vaultpkg.NewFromConfig(cfg)
+	vault, err := vaultpkg.NewFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if propose {
+		gitVault, ok := vault.(*vaultpkg.GitVault)
+		if !ok {
+			return nil, fmt.Errorf("--propose is only supported for git vaults")
+		}
+		gitVault.SetPropose(true)
+	}
+
+	return vault, nil
+}
+
+// proposeRequested reports whether --propose was passed
+// Commands that reuse the add flow without the flag (e.g. init) never propose.
+func proposeRequested(cmd *cobra.Command) bool {
+	propose, _ := cmd.Flags().GetBool("propose")
+	return propose
+}
+
+// mcpCheckRequested reports whether --check was passed
+func mcpCheckRequested(cmd *cobra.Command) bool {
+	check, _ := cmd.Flags().GetBool("check")
+	return check
+}
+
+// recordMCPCapabilities starts the asset's MCP server and records the tools it
+// advertises in the metadata
+func recordMCPCapabilities(ctx context.Context, out *outputHelper, status *components.Status, meta *metadata.Metadata, zipData []byte) error {
+	out.println()
+	status.Start(fmt.Sprintf("Checking MCP server %s", meta.Asset.Name))
+	result, err := checkMCPAsset(ctx, meta, zipData)
+	if err != nil {
+		status.Fail("MCP server check failed")
+		return fmt.Errorf("MCP check failed for %s: %w", meta.Asset.Name, err)
+	}
+	status.Done("")
+
+	meta.MCP.Capabilities = result.ToolNames()
+	out.printf("✓ %s advertises %d tools\n", meta.Asset.Name, len(result.Tools))
+	return nil
+}
+
+// reportProposal prints the proposal branch and pull request URL if the vault
+// pushed changes for review, and returns whether it did
+func reportProposal(out *outputHelper, vault vaultpkg.Vault) bool {
+	gitVault, ok := vault.(*vaultpkg.GitVault)
+	if !ok || gitVault.Proposal() == nil {
+		return false
+	}
+
+	proposal := gitVault.Proposal()
+	out.println()
+	out.printf("Proposed %s@%s on branch %s\n", proposal.Asset, proposal.Version, proposal.Branch)
+	if proposal.CompareURL != "" {
+		out.printf("Open a pull request: %s\n", proposal.CompareURL)
+	}
+	out.println("The change goes live once the branch is merged. Run 'sx vault pending' to list open proposals.")
+	return true
 }
 
 // checkVersionAndContents queries vault for versions and checks if content is identical
@@ -551,11 +641,16 @@ func handleIdenticalAsset(ctx context.Context, out *outputHelper, status *compon
 	out.println()
 	out.printf("✓ Asset %s@%s already exists in vault with identical contents\n", name, version)
 
-	// Check if already in lock file to get current scopes
+	// Check if already in lock file to get current scopes and audience
 	var currentScopes []lockfile.Scope
+	var currentAudience []string
+	var missingRequires, autoScope string
 	lockFilePath := constants.SkillLockFile
 	if existingArt, exists := lockfile.FindAsset(lockFilePath, name); exists {
 		currentScopes = existingArt.Scopes
+		currentAudience = existingArt.Audience
+		missingRequires = existingArt.MissingRequires
+		autoScope = existingArt.AutoScope
 	}
 
 	// Prompt for repository configurations (pass current if exists)
@@ -577,7 +672,10 @@ func handleIdenticalAsset(ctx context.Context, out *outputHelper, status *compon
 		SourcePath: &lockfile.SourcePath{
 			Path: fmt.Sprintf("./assets/%s/%s", name, version),
 		},
-		Scopes: scopes,
+		Scopes:          scopes,
+		Audience:        promptForAudience(out, currentAudience),
+		MissingRequires: missingRequires,
+		AutoScope:       autoScope,
 	}
 
 	if err := updateLockFile(ctx, out, vault, lockAsset); err != nil {
@@ -588,7 +686,7 @@ func handleIdenticalAsset(ctx context.Context, out *outputHelper, status *compon
 }
 
 // addNewAsset adds a new or updated asset to the vault
-func addNewAsset(ctx context.Context, out *outputHelper, status *components.Status, vault vaultpkg.Vault, name string, assetType asset.Type, version, zipFile string, zipData []byte, metadataExists bool) error {
+func addNewAsset(ctx context.Context, out *outputHelper, status *components.Status, vault vaultpkg.Vault, name string, assetType asset.Type, version, zipFile string, zipData []byte, metadataExists, checkMCP bool) error {
 	// Prompt user for version
 	version, err := promptForVersion(out, version)
 	if err != nil {
@@ -598,6 +696,13 @@ func addNewAsset(ctx context.Context, out *outputHelper, status *components.Stat
 	// Create full metadata with confirmed version
 	meta := createMetadata(name, version, assetType, zipFile, zipData)
 
+	// Smoke-test MCP servers before publishing them
+	if checkMCP && meta.MCP != nil {
+		if err := recordMCPCapabilities(ctx, out, status, meta, zipData); err != nil {
+			return err
+		}
+	}
+
 	// Always update metadata.toml to ensure version is correct
 	zipData, err = updateMetadataInZip(meta, zipData, metadataExists)
 	if err != nil {
@@ -625,11 +730,15 @@ func addNewAsset(ctx context.Context, out *outputHelper, status *components.Stat
 
 	out.printf("✓ Successfully added %s@%s\n", meta.Asset.Name, meta.Asset.Version)
 
-	// Check if already in lock file to get current scopes
+	// Check if already in lock file to get current scopes and audience
 	var currentScopes []lockfile.Scope
+	var currentAudience []string
 	lockFilePath := constants.SkillLockFile
 	if existingArt, exists := lockfile.FindAsset(lockFilePath, lockAsset.Name); exists {
 		currentScopes = existingArt.Scopes
+		currentAudience = existingArt.Audience
+		lockAsset.MissingRequires = existingArt.MissingRequires
+		lockAsset.AutoScope = existingArt.AutoScope
 	}
 
 	// Prompt for scope configurations (how/where it's used)
@@ -643,8 +752,9 @@ func addNewAsset(ctx context.Context, out *outputHelper, status *components.Stat
 		return nil
 	}
 
-	// Set scopes on asset
+	// Set scopes and audience on asset
 	lockAsset.Scopes = scopes
+	lockAsset.Audience = promptForAudience(out, currentAudience)
 
 	// Update lock file with asset
 	if err := updateLockFile(ctx, out, vault, lockAsset); err != nil {
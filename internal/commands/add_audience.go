@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sleuth-io/sx/internal/lockfile"
+	"github.com/sleuth-io/sx/internal/ui"
+	"github.com/sleuth-io/sx/internal/ui/components"
+)
+
+// promptForAudience asks which groups of people an asset is for
+// Takes the current audience (nil for everyone) and returns the new one
+func promptForAudience(out *outputHelper, current []string) []string {
+	styledOut := ui.NewOutput(out.cmd.OutOrStdout(), out.cmd.ErrOrStderr())
+	ioc := components.NewIOContext(out.cmd.InOrStdin(), out.cmd.OutOrStdout())
+	return promptForAudienceWithUI(current, styledOut, ioc)
+}
+
+// promptForAudienceWithUI prompts for the audience using the UI components
+// Cancelling, or running out of input, keeps the current audience
+func promptForAudienceWithUI(current []string, styledOut *ui.Output, ioc *components.IOContext) []string {
+	styledOut.Newline()
+
+	var options []components.Option
+	if len(current) > 0 {
+		options = append(options, components.Option{
+			Label:       "Keep current audience",
+			Value:       "keep",
+			Description: strings.Join(current, ", "),
+		})
+	}
+	options = append(options, []components.Option{
+		{
+			Label:       "Everyone",
+			Value:       "everyone",
+			Description: "No group restrictions",
+		},
+		{
+			Label:       "Specific groups",
+			Value:       "groups",
+			Description: "e.g. frontend, sre, or !contractors to exclude",
+		},
+	}...)
+
+	selected, err := ioc.Select("Who is this asset for?", options)
+	if err != nil {
+		return current
+	}
+
+	switch selected.Value {
+	case "everyone":
+		return nil
+	case "groups":
+		input, err := ioc.Input("Groups (comma-separated, !group to exclude)", strings.Join(current, ", "))
+		if err != nil {
+			return current
+		}
+		groups, err := parseAudience(input)
+		if err != nil {
+			styledOut.Warning(fmt.Sprintf("Keeping current audience: %v", err))
+			return current
+		}
+		return groups
+	}
+
+	return current
+}
+
+// parseAudience parses a comma-separated list of groups
+func parseAudience(input string) ([]string, error) {
+	var groups []string
+	for _, group := range strings.Split(input, ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			groups = append(groups, group)
+		}
+	}
+
+	for _, group := range groups {
+		if err := lockfile.ValidateAudienceGroup(group); err != nil {
+			return nil, err
+		}
+	}
+	return groups, nil
+}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/ui"
+	"github.com/sleuth-io/sx/internal/ui/components"
+)
+
+func TestPromptForAudience(t *testing.T) {
+	tests := []struct {
+		name    string
+		current []string
+		input   string
+		want    string
+	}{
+		{"everyone by default", nil, "\n", ""},
+		{"specific groups", nil, "2\nfrontend, !contractors\n", "frontend,!contractors"},
+		{"keep current", []string{"sre"}, "1\n", "sre"},
+		{"clear current", []string{"sre"}, "2\n", ""},
+		{"invalid group keeps current", []string{"sre"}, "3\nnot a group\n", "sre"},
+		{"no input keeps current", []string{"sre"}, "", "sre"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			ioc := components.NewIOContext(bufio.NewReader(strings.NewReader(tt.input)), out)
+			got := promptForAudienceWithUI(tt.current, ui.NewOutput(out, out), ioc)
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("audience = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallFiltersByAudience(t *testing.T) {
+	env := NewTestEnv(t)
+
+	vaultDir := env.SetupPathVault()
+	env.AddSkillToVault(vaultDir, "oncall", "1.0.0")
+	env.AddSkillToVault(vaultDir, "style-guide", "1.0.0")
+	env.WriteLockFile(vaultDir, `lock-version = "1"
+version = "1.0.0"
+created-by = "test"
+
+[[assets]]
+name = "oncall"
+version = "1.0.0"
+type = "skill"
+audience = ["sre"]
+
+[assets.source-path]
+path = "assets/oncall/1.0.0"
+
+[[assets]]
+name = "style-guide"
+version = "1.0.0"
+type = "skill"
+audience = ["!sre"]
+
+[assets.source-path]
+path = "assets/style-guide/1.0.0"
+`)
+
+	install := func() {
+		t.Helper()
+		installCmd := NewInstallCommand()
+		installCmd.SetOut(&bytes.Buffer{})
+		installCmd.SetArgs([]string{})
+		if err := installCmd.Execute(); err != nil {
+			t.Fatalf("install failed: %v", err)
+		}
+	}
+	skillsDir := filepath.Join(env.GlobalClaudeDir(), "skills")
+
+	install()
+	env.AssertFileNotExists(filepath.Join(skillsDir, "oncall"))
+	env.AssertFileExists(filepath.Join(skillsDir, "style-guide"))
+
+	// Joining the group swaps which assets apply
+	env.WriteFile(filepath.Join(env.HomeDir, ".config", "sx", "config.json"),
+		fmt.Sprintf(`{"type":"path","repositoryUrl":"file://%s","groups":["sre"]}`, vaultDir))
+	install()
+	env.AssertFileExists(filepath.Join(skillsDir, "oncall"))
+	env.AssertFileNotExists(filepath.Join(skillsDir, "style-guide"))
+}
@@ -10,10 +10,14 @@ import (
 
 // formatRepository formats a repository entry for display
 func formatRepository(repo lockfile.Scope) string {
+	formatted := fmt.Sprintf("%s → %s", repo.Repo, strings.Join(repo.Paths, ", "))
 	if len(repo.Paths) == 0 {
-		return fmt.Sprintf("%s (entire repository)", repo.Repo)
+		formatted = fmt.Sprintf("%s (entire repository)", repo.Repo)
 	}
-	return fmt.Sprintf("%s → %s", repo.Repo, strings.Join(repo.Paths, ", "))
+	if len(repo.Branches) > 0 {
+		formatted += fmt.Sprintf(" on %s", strings.Join(repo.Branches, ", "))
+	}
+	return formatted
 }
 
 // formatPaths formats a list of paths for display
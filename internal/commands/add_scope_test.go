@@ -78,10 +78,11 @@ prompt-file = "SKILL.md"
 	// Option 1 = Make it available globally
 	t.Log("Step 2: Add test skill with global scope")
 	mockPrompter := NewMockPrompter().
-		ExpectConfirm("correct", true).         // Confirm detected asset
-		ExpectPrompt("Version", "1.0.0").       // Enter version
-		ExpectPrompt("choice", "1").            // Option 1: Make it available globally
-		ExpectConfirm("Run install now", false) // Don't run install
+		ExpectConfirm("correct", true).             // Confirm detected asset
+		ExpectPrompt("Version", "1.0.0").           // Enter version
+		ExpectPrompt("choice", "1").                // Option 1: Make it available globally
+		ExpectPrompt("Who is this asset for", "1"). // Audience: everyone or keep current
+		ExpectConfirm("Run install now", false)     // Don't run install
 
 	addCmd := NewAddCommand()
 	addCmd.SetArgs([]string{skillDir})
@@ -114,6 +115,7 @@ prompt-file = "SKILL.md"
 		ExpectConfirm("entire repository", true).            // Yes, entire repository
 		ExpectPrompt("choice", "4").                         // Option 4: Done with modifications
 		ExpectConfirm("Continue with these changes", true).  // Confirm changes
+		ExpectPrompt("Who is this asset for", "1").          // Audience: everyone or keep current
 		ExpectConfirm("Run install now", false)              // Don't run install
 
 	addCmd2 := NewAddCommand()
@@ -217,6 +219,7 @@ prompt-file = "SKILL.md"
 		ExpectConfirm("Add another path", false).             // No more paths
 		ExpectPrompt("choice", "4").                          // Done with modifications
 		ExpectConfirm("Continue with these changes", true).   // Confirm changes
+		ExpectPrompt("Who is this asset for", "1").           // Audience: everyone or keep current
 		ExpectConfirm("Run install now", false)               // Don't run install
 
 	addCmd := NewAddCommand()
@@ -238,8 +241,9 @@ prompt-file = "SKILL.md"
 	// After Step 2 added a repo, currentRepos != nil, so "Keep current" is option 1
 	t.Log("Step 3: Keep current settings when reconfiguring")
 	mockPrompter2 := NewMockPrompter().
-		ExpectPrompt("choice", "1").            // Option 1: Keep current settings
-		ExpectConfirm("Run install now", false) // Don't run install
+		ExpectPrompt("choice", "1").                // Option 1: Keep current settings
+		ExpectPrompt("Who is this asset for", "1"). // Audience: everyone or keep current
+		ExpectConfirm("Run install now", false)     // Don't run install
 
 	addCmd2 := NewAddCommand()
 	addCmd2.SetArgs([]string{"test-skill"})
@@ -329,10 +333,11 @@ prompt-file = "SKILL.md"
 	// Step 2: Add skill with global scope
 	t.Log("Step 2: Add test skill with global scope")
 	mockPrompter := NewMockPrompter().
-		ExpectConfirm("correct", true).         // Confirm detected asset
-		ExpectPrompt("Version", "1.0.0").       // Enter version
-		ExpectPrompt("choice", "1").            // Option 1: Make it available globally
-		ExpectConfirm("Run install now", false) // Don't run install
+		ExpectConfirm("correct", true).             // Confirm detected asset
+		ExpectPrompt("Version", "1.0.0").           // Enter version
+		ExpectPrompt("choice", "1").                // Option 1: Make it available globally
+		ExpectPrompt("Who is this asset for", "1"). // Audience: everyone or keep current
+		ExpectConfirm("Run install now", false)     // Don't run install
 
 	addCmd := NewAddCommand()
 	addCmd.SetArgs([]string{skillDir})
@@ -475,8 +480,9 @@ prompt-file = "SKILL.md"
 	// When asset exists in repo but not in lock file, it shows options for first-time install
 	t.Log("Step 3: Install asset globally by name")
 	mockPrompter2 := NewMockPrompter().
-		ExpectPrompt("choice", "1").            // Option 1: Make it available globally
-		ExpectConfirm("Run install now", false) // Don't run install
+		ExpectPrompt("choice", "1").                // Option 1: Make it available globally
+		ExpectPrompt("Who is this asset for", "1"). // Audience: everyone or keep current
+		ExpectConfirm("Run install now", false)     // Don't run install
 
 	addCmd2 := NewAddCommand()
 	addCmd2.SetArgs([]string{"test-skill"}) // Configure by name
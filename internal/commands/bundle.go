@@ -0,0 +1,320 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sleuth-io/sx/internal/assets"
+	"github.com/sleuth-io/sx/internal/buildinfo"
+	"github.com/sleuth-io/sx/internal/bundle"
+	"github.com/sleuth-io/sx/internal/cache"
+	"github.com/sleuth-io/sx/internal/config"
+	"github.com/sleuth-io/sx/internal/gitutil"
+	"github.com/sleuth-io/sx/internal/lockfile"
+	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/scope"
+	"github.com/sleuth-io/sx/internal/ui"
+	"github.com/sleuth-io/sx/internal/ui/components"
+	vaultpkg "github.com/sleuth-io/sx/internal/vault"
+)
+
+// NewBundleCommand creates the bundle command
+func NewBundleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Export and import offline bundles for air-gapped machines",
+		Long: `Bundles carry the lock file and every asset it references in a single tar file.
+
+Export a bundle on a machine that can reach the vault, copy it across, then import
+it and install without network access:
+
+  sx bundle export skills.tar
+  sx bundle import skills.tar
+  sx install --offline`,
+	}
+
+	cmd.AddCommand(newBundleExportCommand())
+	cmd.AddCommand(newBundleImportCommand())
+
+	return cmd
+}
+
+func newBundleExportCommand() *cobra.Command {
+	var all bool
+	var repoURL string
+
+	cmd := &cobra.Command{
+		Use:   "export <file.tar>",
+		Short: "Pack the lock file and asset zips into a bundle",
+		Long: `Fetch the lock file and every asset that applies to a scope and write them to a tar file.
+
+By default the scope is the current directory, as for 'sx install'. Use --repo to
+bundle a repository's assets from anywhere, or --all for every asset in the vault.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBundleExport(cmd, args[0], all, repoURL)
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Include every asset in the lock file regardless of scope")
+	cmd.Flags().StringVar(&repoURL, "repo", "", "Bundle the assets for this repository URL instead of the current directory")
+	cmd.MarkFlagsMutuallyExclusive("all", "repo")
+
+	return cmd
+}
+
+func newBundleImportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file.tar>",
+		Short: "Seed the local cache from a bundle",
+		Long: `Verify a bundle and copy its lock file and asset zips into the local cache,
+ready for 'sx install --offline'.
+
+If sx is not configured yet, the vault recorded in the bundle is configured so the
+cached lock file is found; no credentials are needed for offline installs.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBundleImport(cmd, args[0])
+		},
+	}
+
+	return cmd
+}
+
+// runBundleExport executes the bundle export command
+func runBundleExport(cmd *cobra.Command, file string, all bool, repoURL string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	styledOut := ui.NewOutput(cmd.OutOrStdout(), cmd.ErrOrStderr())
+	status := components.NewStatus(cmd.OutOrStdout())
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w\nRun 'sx init' to configure", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	vault, err := vaultpkg.NewFromConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create vault: %w", err)
+	}
+
+	// Always fetch the current lock file; a cached copy may be stale
+	status.Start("Fetching lock file")
+	lockFileData, _, _, err := vault.GetLockFile(ctx, "")
+	if err != nil {
+		status.Fail("Failed to fetch lock file")
+		return fmt.Errorf("failed to fetch lock file: %w", err)
+	}
+	lockFile, err := lockfile.Parse(lockFileData)
+	if err != nil {
+		status.Fail("Failed to parse lock file")
+		return fmt.Errorf("failed to parse lock file: %w", err)
+	}
+	if err := lockFile.Validate(); err != nil {
+		status.Fail("Lock file validation failed")
+		return fmt.Errorf("lock file validation failed: %w", err)
+	}
+	status.Clear()
+
+	// Select the assets for the requested scope
+	var matcher *scope.Matcher
+	scopeDesc := "all"
+	switch {
+	case all:
+	case repoURL != "":
+		matcher = scope.NewMatcher(&scope.Scope{Type: scope.TypeRepo, RepoURL: repoURL})
+		scopeDesc = repoURL
+	default:
+		gitContext, err := gitutil.DetectContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to detect git context: %w", err)
+		}
+		currentScope := currentScopeFromGit(gitContext)
+		matcher = scope.NewMatcher(currentScope)
+		scopeDesc = describeBundleScope(currentScope)
+	}
+
+	var selected []*lockfile.Asset
+	for i := range lockFile.Assets {
+		asset := &lockFile.Assets[i]
+		if matcher == nil || matcher.MatchesAsset(asset) {
+			selected = append(selected, asset)
+		}
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("no assets in the lock file apply to %s", scopeDesc)
+	}
+
+	// Dependencies travel with the assets that need them
+	sorted, err := assets.NewDependencyResolver(lockFile).Resolve(selected)
+	if err != nil {
+		return fmt.Errorf("dependency resolution failed: %w", err)
+	}
+
+	status.Start(fmt.Sprintf("Downloading %d assets", len(sorted)))
+	results, err := assets.NewAssetFetcher(vault).FetchAssets(ctx, sorted, 10)
+	if err != nil {
+		status.Fail("Download failed")
+		return fmt.Errorf("failed to fetch assets: %w", err)
+	}
+	for _, result := range results {
+		if result.Error != nil {
+			status.Fail("Download failed")
+			return fmt.Errorf("failed to fetch %s: %w", result.Asset.Name, result.Error)
+		}
+	}
+	status.Clear()
+
+	// The bundled lock file lists exactly the bundled assets, so an offline install
+	// never looks for an asset that isn't in the cache
+	bundledLock := *lockFile
+	bundledLock.Assets = make([]lockfile.Asset, len(sorted))
+	for i, asset := range sorted {
+		bundledLock.Assets[i] = *asset
+	}
+	bundledLockData, err := lockfile.Marshal(&bundledLock)
+	if err != nil {
+		return fmt.Errorf("failed to marshal lock file: %w", err)
+	}
+
+	b := bundle.New(bundle.Manifest{
+		CreatedAt: time.Now().UTC(),
+		CreatedBy: buildinfo.GetCreatedBy(),
+		VaultType: cfg.GetType(),
+		VaultURL:  cfg.RepositoryURL,
+		Scope:     scopeDesc,
+	}, bundledLockData)
+	for _, result := range results {
+		b.AddAsset(result.Asset.Name, result.Asset.Version, result.ZipData)
+	}
+
+	if err := writeBundleFile(file, b); err != nil {
+		return err
+	}
+
+	logger.Get().Info("bundle exported", "file", file, "assets", len(results), "scope", scopeDesc)
+	styledOut.Success(fmt.Sprintf("Exported %d assets for %s to %s", len(results), scopeDesc, file))
+	for _, result := range results {
+		styledOut.SuccessItem(fmt.Sprintf("%s@%s", result.Asset.Name, result.Asset.Version))
+	}
+	return nil
+}
+
+// runBundleImport executes the bundle import command
+func runBundleImport(cmd *cobra.Command, file string) error {
+	styledOut := ui.NewOutput(cmd.OutOrStdout(), cmd.ErrOrStderr())
+	log := logger.Get()
+
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer f.Close()
+
+	b, err := bundle.Read(f)
+	if err != nil {
+		return err
+	}
+
+	lockFile, err := lockfile.Parse(b.LockFile)
+	if err != nil {
+		return fmt.Errorf("failed to parse bundled lock file: %w", err)
+	}
+	if err := lockFile.Validate(); err != nil {
+		return fmt.Errorf("bundled lock file validation failed: %w", err)
+	}
+	for _, asset := range lockFile.Assets {
+		if _, ok := b.Zip(asset.Name, asset.Version); !ok {
+			return fmt.Errorf("bundle is missing %s@%s referenced by its lock file", asset.Name, asset.Version)
+		}
+	}
+
+	// The cached lock file is keyed by the configured vault URL
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = &config.Config{
+			Type:          config.RepositoryType(b.Manifest.VaultType),
+			RepositoryURL: b.Manifest.VaultURL,
+		}
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save configuration: %w", err)
+		}
+		styledOut.Info(fmt.Sprintf("Configured vault %s from the bundle", b.Manifest.VaultURL))
+	} else if cfg.RepositoryURL != b.Manifest.VaultURL {
+		styledOut.Warning(fmt.Sprintf("Bundle was exported from %s but %s is configured; importing for the configured vault",
+			b.Manifest.VaultURL, cfg.RepositoryURL))
+	}
+
+	for _, a := range b.Manifest.Assets {
+		data, _ := b.Zip(a.Name, a.Version)
+		if err := cache.SaveAssetToDisk(a.Name, a.Version, data, ""); err != nil {
+			return fmt.Errorf("failed to cache %s@%s: %w", a.Name, a.Version, err)
+		}
+	}
+
+	// Drop the ETag too, so an online install never treats the bundled lock file as
+	// the vault's current one
+	if err := cache.InvalidateLockFileCache(cfg.RepositoryURL); err != nil {
+		return err
+	}
+	if err := cache.SaveLockFile(cfg.RepositoryURL, b.LockFile); err != nil {
+		return fmt.Errorf("failed to cache lock file: %w", err)
+	}
+
+	log.Info("bundle imported", "file", file, "assets", len(b.Manifest.Assets), "scope", b.Manifest.Scope)
+	styledOut.Success(fmt.Sprintf("Imported %d assets for %s (exported %s)",
+		len(b.Manifest.Assets), b.Manifest.Scope, b.Manifest.CreatedAt.Local().Format("Jan 2, 2006")))
+	styledOut.Info("Run 'sx install --offline' to install them")
+	return nil
+}
+
+// writeBundleFile writes b to file, replacing it only once the bundle is complete
+func writeBundleFile(file string, b *bundle.Bundle) error {
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".sx-bundle-*")
+	if err != nil {
+		return fmt.Errorf("failed to create bundle file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write bundle file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write bundle file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return fmt.Errorf("failed to write bundle file: %w", err)
+	}
+	return nil
+}
+
+// describeBundleScope names a scope for the bundle manifest and output
+func describeBundleScope(s *scope.Scope) string {
+	switch s.Type {
+	case scope.TypeRepo:
+		return s.RepoURL
+	case scope.TypePath:
+		return s.RepoURL + "/" + s.RepoPath
+	default:
+		return "global"
+	}
+}
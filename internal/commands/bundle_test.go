@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBundleExportImportOffline(t *testing.T) {
+	env := NewTestEnv(t)
+	workingDir := env.MkdirAll(filepath.Join(env.TempDir, "working"))
+	env.Chdir(workingDir)
+
+	vaultDir := env.SetupPathVault()
+	env.AddSkillToVault(vaultDir, "bundled-skill", "1.0.0")
+	env.WriteLockFile(vaultDir, `lock-version = "1"
+version = "1.0.0"
+created-by = "test"
+
+[[assets]]
+name = "bundled-skill"
+version = "1.0.0"
+type = "skill"
+
+[assets.source-path]
+path = "assets/bundled-skill/1.0.0"
+`)
+
+	bundleFile := filepath.Join(env.TempDir, "skills.tar")
+	exportCmd := NewBundleCommand()
+	exportCmd.SetArgs([]string{"export", bundleFile})
+	exportCmd.SetOut(&bytes.Buffer{})
+	if err := exportCmd.Execute(); err != nil {
+		t.Fatalf("bundle export failed: %v", err)
+	}
+
+	// Simulate the air-gapped machine: no vault, no cache, no config
+	for _, dir := range []string{
+		vaultDir,
+		filepath.Join(env.HomeDir, ".cache"),
+		filepath.Join(env.HomeDir, ".config", "sx"),
+	} {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("offline install without a cached lock file fails", func(t *testing.T) {
+		env.SetupPathVault()
+		defer os.RemoveAll(filepath.Join(env.HomeDir, ".config", "sx"))
+
+		installCmd := NewInstallCommand()
+		installCmd.SetArgs([]string{"--offline"})
+		installCmd.SetOut(&bytes.Buffer{})
+		installCmd.SetErr(&bytes.Buffer{})
+		err := installCmd.Execute()
+		if err == nil || !strings.Contains(err.Error(), "sx bundle import") {
+			t.Errorf("expected a hint to import a bundle, got %v", err)
+		}
+	})
+
+	importCmd := NewBundleCommand()
+	importCmd.SetArgs([]string{"import", bundleFile})
+	var importOut bytes.Buffer
+	importCmd.SetOut(&importOut)
+	if err := importCmd.Execute(); err != nil {
+		t.Fatalf("bundle import failed: %v", err)
+	}
+	if !strings.Contains(importOut.String(), "Imported 1 assets") {
+		t.Errorf("unexpected import output: %s", importOut.String())
+	}
+
+	installCmd := NewInstallCommand()
+	installCmd.SetArgs([]string{"--offline"})
+	installCmd.SetOut(&bytes.Buffer{})
+	if err := installCmd.Execute(); err != nil {
+		t.Fatalf("offline install failed: %v", err)
+	}
+	env.AssertFileExists(filepath.Join(env.GlobalClaudeDir(), "skills", "bundled-skill", "SKILL.md"))
+}
+
+func TestBundleImportRejectsCorruptFiles(t *testing.T) {
+	env := NewTestEnv(t)
+	bundleFile := filepath.Join(env.TempDir, "not-a-bundle.tar")
+	env.WriteFile(bundleFile, "garbage")
+
+	cmd := NewBundleCommand()
+	cmd.SetArgs([]string{"import", bundleFile})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected import of a corrupt bundle to fail")
+	}
+}
@@ -0,0 +1,360 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sleuth-io/sx/internal/assets"
+	"github.com/sleuth-io/sx/internal/cache"
+	"github.com/sleuth-io/sx/internal/config"
+	"github.com/sleuth-io/sx/internal/git"
+	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/ui"
+	"github.com/sleuth-io/sx/internal/ui/components"
+)
+
+// NewCacheCommand creates the cache command
+func NewCacheCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and clean up the local asset cache (ls, prune, clear)",
+		Long: `Downloaded assets are kept in a content-addressed store so installs and
+'sx install --offline' don't need to fetch them again. Git vaults and git asset
+sources are kept as local clones.`,
+	}
+
+	cmd.AddCommand(newCacheLsCommand())
+	cmd.AddCommand(newCachePruneCommand())
+	cmd.AddCommand(newCacheClearCommand())
+
+	return cmd
+}
+
+func newCacheLsCommand() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "ls",
+		Short: "List cached assets and git clones",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCacheLs(cmd, jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func newCachePruneCommand() *cobra.Command {
+	var maxAge string
+	var maxSize string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Evict unused cache entries",
+		Long: `Evict cached assets and git clones that have not been used recently.
+
+Assets not used within --max-age are removed, then the least recently used assets
+are removed until the store fits in --max-size. Assets that are currently installed
+and the configured git vault's clone are never evicted.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCachePrune(cmd, maxAge, maxSize, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVar(&maxAge, "max-age", "30d", "Evict entries not used for this long (e.g. 12h, 30d; 0 to disable)")
+	cmd.Flags().StringVar(&maxSize, "max-size", "0", "Evict least recently used assets until the store fits (e.g. 500MB, 2GB; 0 to disable)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be evicted without removing anything")
+
+	return cmd
+}
+
+func newCacheClearCommand() *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Remove all cached assets, git clones, and lock files",
+		Long: `Remove all cached assets, git clones, and lock files. Installed assets are not
+affected; the next install downloads what it needs again.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCacheClear(cmd, yes)
+		},
+	}
+
+	cmd.Flags().BoolVar(&yes, "yes", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+// cachedAsset is a store entry as shown by 'sx cache ls'
+type cachedAsset struct {
+	cache.AssetEntry
+	Installed bool `json:"installed"`
+}
+
+// cachedGitRepo is a git clone as shown by 'sx cache ls'
+type cachedGitRepo struct {
+	cache.GitRepoEntry
+	URL string `json:"url,omitempty"`
+}
+
+// runCacheLs executes the cache ls command
+func runCacheLs(cmd *cobra.Command, jsonOutput bool) error {
+	entries, err := cache.ListAssets()
+	if err != nil {
+		return fmt.Errorf("failed to list cached assets: %w", err)
+	}
+	repos, err := cache.ListGitRepos()
+	if err != nil {
+		return fmt.Errorf("failed to list cached git clones: %w", err)
+	}
+
+	installed := installedAssetKeys()
+	cachedAssets := make([]cachedAsset, len(entries))
+	for i, e := range entries {
+		cachedAssets[i] = cachedAsset{AssetEntry: e, Installed: installed[e.Key()]}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	gitClient := git.NewClient()
+	cachedRepos := make([]cachedGitRepo, len(repos))
+	for i, r := range repos {
+		url, _ := gitClient.GetRemoteURL(ctx, r.Path)
+		cachedRepos[i] = cachedGitRepo{GitRepoEntry: r, URL: url}
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"assets":   cachedAssets,
+			"gitRepos": cachedRepos,
+		}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return nil
+	}
+
+	styledOut := ui.NewOutput(cmd.OutOrStdout(), cmd.ErrOrStderr())
+	if len(cachedAssets) == 0 && len(cachedRepos) == 0 {
+		styledOut.Info("Cache is empty")
+		return nil
+	}
+
+	if len(cachedAssets) > 0 {
+		size, _ := cache.StoreSize()
+		styledOut.Header(fmt.Sprintf("Assets (%d, %s)", len(cachedAssets), formatBytes(size)))
+		for _, a := range cachedAssets {
+			line := fmt.Sprintf("%-40s %s  %8s  used %s", a.Key(), a.SHA256[:12], formatBytes(a.Size), formatAge(a.LastUsed))
+			if a.Installed {
+				line += styledOut.MutedText("  (installed)")
+			}
+			styledOut.ListItem("•", line)
+		}
+	}
+
+	if len(cachedRepos) > 0 {
+		if len(cachedAssets) > 0 {
+			styledOut.Newline()
+		}
+		styledOut.Header(fmt.Sprintf("Git clones (%d)", len(cachedRepos)))
+		for _, r := range cachedRepos {
+			name := r.URL
+			if name == "" {
+				name = r.Path
+			}
+			styledOut.ListItem("•", fmt.Sprintf("%-40s %8s  used %s", name, formatBytes(r.Size), formatAge(r.LastUsed)))
+		}
+	}
+
+	return nil
+}
+
+// runCachePrune executes the cache prune command
+func runCachePrune(cmd *cobra.Command, maxAgeFlag, maxSizeFlag string, dryRun bool) error {
+	styledOut := ui.NewOutput(cmd.OutOrStdout(), cmd.ErrOrStderr())
+
+	maxAge, err := parseAge(maxAgeFlag)
+	if err != nil {
+		return fmt.Errorf("invalid --max-age: %w", err)
+	}
+	maxSize, err := parseSize(maxSizeFlag)
+	if err != nil {
+		return fmt.Errorf("invalid --max-size: %w", err)
+	}
+
+	policy := cache.PrunePolicy{
+		MaxAge:    maxAge,
+		MaxSize:   maxSize,
+		Keep:      installedAssetKeys(),
+		KeepRepos: make(map[string]bool),
+		DryRun:    dryRun,
+	}
+
+	// The configured git vault is needed by every install; keep its clone
+	if cfg, err := config.Load(); err == nil && cfg.Type == config.RepositoryTypeGit {
+		if clonePath, err := cache.GetGitRepoCachePath(cfg.RepositoryURL); err == nil {
+			policy.KeepRepos[clonePath] = true
+		}
+	}
+
+	result, err := cache.Prune(policy)
+	if err != nil {
+		return fmt.Errorf("failed to prune cache: %w", err)
+	}
+	logger.Get().Info("cache pruned", "assets", len(result.Assets), "blobs", result.Blobs,
+		"git_repos", len(result.GitRepos), "freed", result.Freed, "dry_run", dryRun)
+
+	if len(result.Assets) == 0 && len(result.GitRepos) == 0 && result.Blobs == 0 {
+		styledOut.Success("Nothing to prune")
+		return nil
+	}
+
+	verb := "Removed"
+	if dryRun {
+		verb = "Would remove"
+	}
+	for _, a := range result.Assets {
+		styledOut.ListItem("-", fmt.Sprintf("%s (used %s)", a.Key(), formatAge(a.LastUsed)))
+	}
+	for _, r := range result.GitRepos {
+		styledOut.ListItem("-", fmt.Sprintf("git clone %s (used %s)", r.Path, formatAge(r.LastUsed)))
+	}
+	styledOut.Success(fmt.Sprintf("%s %d assets and %d git clones, freeing %s",
+		verb, len(result.Assets), len(result.GitRepos), formatBytes(result.Freed)))
+	return nil
+}
+
+// runCacheClear executes the cache clear command
+func runCacheClear(cmd *cobra.Command, yes bool) error {
+	styledOut := ui.NewOutput(cmd.OutOrStdout(), cmd.ErrOrStderr())
+
+	if !yes {
+		confirmed, err := components.ConfirmWithIO("Remove all cached assets, git clones, and lock files?", false, cmd.InOrStdin(), cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			styledOut.Info("Cache clear cancelled")
+			return nil
+		}
+	}
+
+	freed, err := cache.Clear()
+	if err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	logger.Get().Info("cache cleared", "freed", freed)
+	styledOut.Success(fmt.Sprintf("Cleared cache, freeing %s", formatBytes(freed)))
+	return nil
+}
+
+// installedAssetKeys returns the name@version of every asset in the tracker
+func installedAssetKeys() map[string]bool {
+	keys := make(map[string]bool)
+	tracker, err := assets.LoadTracker()
+	if err != nil {
+		logger.Get().Warn("failed to load tracker", "error", err)
+		return keys
+	}
+	for _, a := range tracker.Assets {
+		keys[cache.AssetKey(a.Name, a.Version)] = true
+	}
+	return keys
+}
+
+// parseAge parses a duration, also accepting a "d" suffix for days
+func parseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.ParseFloat(days, 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%q is not a valid age", s)
+		}
+		return time.Duration(n * float64(24*time.Hour)), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%q is not a valid age", s)
+	}
+	return d, nil
+}
+
+// parseSize parses a byte size such as 500MB or 2GB
+func parseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" || s == "0" {
+		return 0, nil
+	}
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{
+		{"GB", 1 << 30},
+		{"MB", 1 << 20},
+		{"KB", 1 << 10},
+		{"G", 1 << 30},
+		{"M", 1 << 20},
+		{"K", 1 << 10},
+		{"B", 1},
+	} {
+		if rest, ok := strings.CutSuffix(s, unit.suffix); ok {
+			s, multiplier = strings.TrimSpace(rest), unit.size
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%q is not a valid size", s)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// formatBytes renders a byte count for humans
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+// formatAge renders how long ago t was
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
@@ -13,7 +13,7 @@ func RunDefaultCommand(cmd *cobra.Command, args []string) error {
 	// Check if sx.lock exists in current directory
 	if _, err := os.Stat(constants.SkillLockFile); err == nil {
 		// Lock file exists, run install (not in hook mode, no specific client)
-		return runInstall(cmd, args, false, "", false)
+		return runInstall(cmd, args, false, "", false, false)
 	}
 
 	// No lock file, just show help
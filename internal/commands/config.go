@@ -14,12 +14,14 @@ import (
 
 	"github.com/sleuth-io/sx/internal/asset"
 	"github.com/sleuth-io/sx/internal/assets"
+	"github.com/sleuth-io/sx/internal/audience"
 	"github.com/sleuth-io/sx/internal/buildinfo"
 	"github.com/sleuth-io/sx/internal/cache"
 	"github.com/sleuth-io/sx/internal/clients"
 	"github.com/sleuth-io/sx/internal/config"
 	"github.com/sleuth-io/sx/internal/gitutil"
 	"github.com/sleuth-io/sx/internal/lockfile"
+	"github.com/sleuth-io/sx/internal/prereq"
 	"github.com/sleuth-io/sx/internal/scope"
 	"github.com/sleuth-io/sx/internal/utils"
 )
@@ -49,11 +51,12 @@ type PlatformInfo struct {
 }
 
 type ConfigInfo struct {
-	Path          string `json:"path"`
-	Exists        bool   `json:"exists"`
-	Type          string `json:"type,omitempty"`
-	RepositoryURL string `json:"repositoryUrl,omitempty"`
-	ServerURL     string `json:"serverUrl,omitempty"`
+	Path          string   `json:"path"`
+	Exists        bool     `json:"exists"`
+	Type          string   `json:"type,omitempty"`
+	RepositoryURL string   `json:"repositoryUrl,omitempty"`
+	ServerURL     string   `json:"serverUrl,omitempty"`
+	Groups        []string `json:"groups,omitempty"` // Audience groups the user belongs to
 }
 
 type DirectoryInfo struct {
@@ -92,6 +95,7 @@ const (
 	StatusOutdated     AssetStatus = "outdated"      // Installed but different version
 	StatusNotInstalled AssetStatus = "not_installed" // In lock file but not installed
 	StatusOrphaned     AssetStatus = "orphaned"      // Installed but not in lock file
+	StatusLinked       AssetStatus = "linked"        // Installed from a working directory with sx link
 )
 
 type AssetInfo struct {
@@ -101,6 +105,8 @@ type AssetInfo struct {
 	Type             string      `json:"type"`
 	Clients          []string    `json:"clients"`
 	Status           AssetStatus `json:"status"`
+	Overlay          string      `json:"overlay,omitempty"` // Hash of the repo overlay applied on install
+	MissingRequires  []string    `json:"missingRequires,omitempty"`
 }
 
 // NewConfigCommand creates the config command
@@ -154,12 +160,14 @@ func gatherConfigInfo(showAll bool) ConfigOutput {
 			currentScope = &scope.Scope{
 				Type:    scope.TypeRepo,
 				RepoURL: gitContext.RepoURL,
+				Branch:  gitContext.Branch,
 			}
 		} else {
 			currentScope = &scope.Scope{
 				Type:     scope.TypePath,
 				RepoURL:  gitContext.RepoURL,
 				RepoPath: gitContext.RelativePath,
+				Branch:   gitContext.Branch,
 			}
 		}
 		output.CurrentScope = currentScope
@@ -196,6 +204,7 @@ func gatherConfigDetails() ConfigInfo {
 		if cfg.Type == config.RepositoryTypeSleuth {
 			info.ServerURL = cfg.GetServerURL()
 		}
+		info.Groups = audience.Resolve(context.Background(), cfg).Groups
 	}
 
 	return info
@@ -343,8 +352,22 @@ func getLatestVersion(assets []*lockfile.Asset) *lockfile.Asset {
 
 // determineAssetStatus determines the installation status of an asset
 func determineAssetStatus(asset *lockfile.Asset, scopeName string, tracker *assets.Tracker) (AssetStatus, string, []string) {
+	if installed := findInstalledAsset(asset, scopeName, tracker); installed != nil {
+		if installed.IsLinked() {
+			return StatusLinked, installed.Version, installed.Clients
+		}
+		if installed.Version == asset.Version {
+			return StatusInstalled, "", installed.Clients
+		}
+		return StatusOutdated, installed.Version, installed.Clients
+	}
+	return StatusNotInstalled, "", asset.Clients
+}
+
+// findInstalledAsset finds the tracker entry for a lock file asset shown under scopeName
+func findInstalledAsset(asset *lockfile.Asset, scopeName string, tracker *assets.Tracker) *assets.InstalledAsset {
 	if tracker == nil {
-		return StatusNotInstalled, "", asset.Clients
+		return nil
 	}
 
 	var installed *assets.InstalledAsset
@@ -357,13 +380,13 @@ func determineAssetStatus(asset *lockfile.Asset, scopeName string, tracker *asse
 		for _, repo := range asset.Scopes {
 			if scope.MatchRepoURLs(repo.Repo, scopeName) {
 				// Check repo-scoped installation
-				installed = tracker.FindAssetWithMatcher(asset.Name, repo.Repo, "", scope.MatchRepoURLs)
+				installed = tracker.FindAssetWithMatcher(asset.Name, repo.Repo, "", scope.MatchRepoPattern)
 				if installed != nil {
 					break
 				}
 				// Also check path-scoped installations
 				for _, path := range repo.Paths {
-					installed = tracker.FindAssetWithMatcher(asset.Name, repo.Repo, path, scope.MatchRepoURLs)
+					installed = tracker.FindAssetWithMatcher(asset.Name, repo.Repo, path, scope.MatchRepoPattern)
 					if installed != nil {
 						break
 					}
@@ -375,13 +398,7 @@ func determineAssetStatus(asset *lockfile.Asset, scopeName string, tracker *asse
 		}
 	}
 
-	if installed != nil {
-		if installed.Version == asset.Version {
-			return StatusInstalled, "", installed.Clients
-		}
-		return StatusOutdated, installed.Version, installed.Clients
-	}
-	return StatusNotInstalled, "", asset.Clients
+	return installed
 }
 
 // gatherUnifiedAssets builds a unified list of assets from the lock file with installation status
@@ -439,6 +456,10 @@ func gatherUnifiedAssets(currentScope *scope.Scope, showAll bool) []ScopeAssets
 				Clients:          clients,
 				InstalledVersion: installedVersion,
 			}
+			if installed := findInstalledAsset(latest, scopeName, tracker); installed != nil {
+				info.Overlay = installed.OverlayHash
+				info.MissingRequires = prereq.Check(context.Background(), installed.Requires)
+			}
 
 			s.Assets = append(s.Assets, info)
 		}
@@ -486,12 +507,16 @@ func gatherUnifiedAssets(currentScope *scope.Scope, showAll bool) []ScopeAssets
 					targetScope = &scopes[len(scopes)-1]
 				}
 
+				status := StatusOrphaned
+				if installed.IsLinked() {
+					status = StatusLinked
+				}
 				targetScope.Assets = append(targetScope.Assets, AssetInfo{
 					Name:    installed.Name,
 					Version: installed.Version,
 					Type:    installed.Type,
 					Clients: installed.Clients,
-					Status:  StatusOrphaned,
+					Status:  status,
 				})
 			}
 		}
@@ -566,6 +591,9 @@ func printText(output ConfigOutput, showAll bool) error {
 	if output.Config.ServerURL != "" {
 		fmt.Printf("Server URL: %s\n", output.Config.ServerURL)
 	}
+	if len(output.Config.Groups) > 0 {
+		fmt.Printf("Groups: %s\n", strings.Join(output.Config.Groups, ", "))
+	}
 	fmt.Println()
 
 	// Directories
@@ -636,9 +664,17 @@ func printText(output ConfigOutput, showAll bool) error {
 					statusStr = " (not installed)"
 				case StatusOrphaned:
 					statusStr = " (removed from lock file)"
+				case StatusLinked:
+					statusStr = " (linked working copy)"
+				}
+				if asset.Overlay != "" {
+					statusStr += " (overlaid)"
 				}
 
 				fmt.Printf("  - %s (%s) [%s]%s%s\n", asset.Name, asset.Version, asset.Type, statusStr, clientsStr)
+				for _, missing := range asset.MissingRequires {
+					fmt.Printf("      missing prerequisite: %s\n", missing)
+				}
 			}
 			fmt.Println()
 		}
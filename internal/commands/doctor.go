@@ -0,0 +1,754 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sleuth-io/sx/internal/assets"
+	"github.com/sleuth-io/sx/internal/audience"
+	"github.com/sleuth-io/sx/internal/buildinfo"
+	"github.com/sleuth-io/sx/internal/cache"
+	"github.com/sleuth-io/sx/internal/clients"
+	"github.com/sleuth-io/sx/internal/clients/cursor/handlers"
+	"github.com/sleuth-io/sx/internal/config"
+	"github.com/sleuth-io/sx/internal/gitutil"
+	"github.com/sleuth-io/sx/internal/journal"
+	"github.com/sleuth-io/sx/internal/lockfile"
+	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/scope"
+	"github.com/sleuth-io/sx/internal/ui"
+	"github.com/sleuth-io/sx/internal/utils"
+	vaultpkg "github.com/sleuth-io/sx/internal/vault"
+)
+
+// maxClockSkew is how far the local clock may drift from the server before doctor warns
+const maxClockSkew = 5 * time.Minute
+
+// CheckStatus is the outcome of a single doctor check
+type CheckStatus string
+
+const (
+	CheckPass CheckStatus = "pass"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+)
+
+// DoctorCheck is the result of one diagnostic check
+type DoctorCheck struct {
+	Name    string      `json:"name"`
+	Status  CheckStatus `json:"status"`
+	Message string      `json:"message"`
+	Fix     string      `json:"fix,omitempty"`   // Hint for fixing the problem by hand
+	Fixed   bool        `json:"fixed,omitempty"` // Set when --fix repaired the problem
+
+	fixFunc func() error // Automatic fix applied by --fix, if any
+}
+
+// DoctorOutput is the full doctor report for JSON serialization
+type DoctorOutput struct {
+	Version  VersionInfo   `json:"version"`
+	Platform PlatformInfo  `json:"platform"`
+	Checks   []DoctorCheck `json:"checks"`
+	Summary  DoctorSummary `json:"summary"`
+}
+
+// DoctorSummary counts checks by status
+type DoctorSummary struct {
+	Pass int `json:"pass"`
+	Warn int `json:"warn"`
+	Fail int `json:"fail"`
+}
+
+// NewDoctorCommand creates the doctor command
+func NewDoctorCommand() *cobra.Command {
+	var fix bool
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose problems with configuration, vault access, and installed assets",
+		Long: `Doctor runs a set of checks and reports pass, warn, or fail for each, with a hint
+on how to fix problems:
+
+  - configuration is present and valid
+  - the vault is reachable and credentials work
+  - the lock file parses and validates
+  - installed assets match what the tracker records
+  - sx hooks are installed in each client
+  - the skills MCP server is registered for Cursor
+  - the cache is intact
+  - the local clock agrees with the server
+
+Use --fix to repair what can be repaired automatically, and --json to attach
+the report to a support ticket.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true, // Failed checks are not usage errors
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(cmd, fix, jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVar(&fix, "fix", false, "Automatically fix problems where possible")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+// doctor holds state shared between checks, so later checks can build on earlier ones
+type doctor struct {
+	cmd *cobra.Command
+	ctx context.Context
+
+	cfg          *config.Config
+	lockFile     *lockfile.LockFile
+	currentScope *scope.Scope
+	clients      []clients.Client
+
+	checks      []DoctorCheck
+	reinstalled bool
+	jsonOutput  bool
+}
+
+// runDoctor executes the doctor command
+func runDoctor(cmd *cobra.Command, fix, jsonOutput bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	d := &doctor{cmd: cmd, ctx: ctx, jsonOutput: jsonOutput}
+	d.run()
+
+	if fix {
+		d.applyFixes()
+	}
+
+	output := d.output()
+	log := logger.Get()
+	log.Info("doctor completed", "pass", output.Summary.Pass, "warn", output.Summary.Warn, "fail", output.Summary.Fail, "fix", fix)
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	} else {
+		printDoctorText(cmd, output, fix)
+	}
+
+	if output.Summary.Fail > 0 {
+		return fmt.Errorf("%d check(s) failed", output.Summary.Fail)
+	}
+	return nil
+}
+
+// run executes every check in order
+func (d *doctor) run() {
+	d.checkConfig()
+	d.checkVault()
+	d.checkLockFile()
+	d.checkClients()
+	d.checkHooks()
+	d.checkCursorMCP()
+	d.checkInstalledAssets()
+	d.checkCache()
+	d.checkClockSkew()
+}
+
+// add records a check result
+func (d *doctor) add(c DoctorCheck) {
+	d.checks = append(d.checks, c)
+}
+
+// applyFixes runs the automatic fix of every failing or warning check
+func (d *doctor) applyFixes() {
+	log := logger.Get()
+	for i := range d.checks {
+		c := &d.checks[i]
+		if c.Status == CheckPass || c.fixFunc == nil {
+			continue
+		}
+		if err := c.fixFunc(); err != nil {
+			log.Error("doctor fix failed", "check", c.Name, "error", err)
+			c.Message = fmt.Sprintf("%s (fix failed: %v)", c.Message, err)
+			continue
+		}
+		log.Info("doctor fix applied", "check", c.Name)
+		c.Fixed = true
+		c.Status = CheckPass
+	}
+}
+
+// output builds the report
+func (d *doctor) output() DoctorOutput {
+	cwd, _ := os.Getwd()
+	output := DoctorOutput{
+		Version: VersionInfo{
+			Version: buildinfo.Version,
+			Commit:  buildinfo.Commit,
+			Date:    buildinfo.Date,
+		},
+		Platform: PlatformInfo{
+			OS:         runtime.GOOS,
+			Arch:       runtime.GOARCH,
+			WorkingDir: cwd,
+		},
+		Checks: d.checks,
+	}
+	for _, c := range d.checks {
+		switch c.Status {
+		case CheckPass:
+			output.Summary.Pass++
+		case CheckWarn:
+			output.Summary.Warn++
+		case CheckFail:
+			output.Summary.Fail++
+		}
+	}
+	return output
+}
+
+// checkConfig verifies the config file exists and is valid
+func (d *doctor) checkConfig() {
+	const name = "Configuration"
+
+	cfg, err := config.Load()
+	if err != nil {
+		d.add(DoctorCheck{Name: name, Status: CheckFail, Message: err.Error(), Fix: "Run 'sx init' to configure a vault"})
+		return
+	}
+	if err := cfg.Validate(); err != nil {
+		d.add(DoctorCheck{Name: name, Status: CheckFail, Message: fmt.Sprintf("invalid configuration: %v", err), Fix: "Run 'sx init' to reconfigure"})
+		return
+	}
+
+	d.cfg = cfg
+	d.add(DoctorCheck{Name: name, Status: CheckPass, Message: fmt.Sprintf("%s vault at %s", cfg.GetType(), describeVaultURL(cfg))})
+}
+
+// checkVault fetches the lock file to verify the vault is reachable and credentials work
+func (d *doctor) checkVault() {
+	const name = "Vault access"
+
+	if d.cfg == nil {
+		d.add(DoctorCheck{Name: name, Status: CheckWarn, Message: "skipped (no valid configuration)"})
+		return
+	}
+
+	vault, err := vaultpkg.NewFromConfig(d.cfg)
+	if err != nil {
+		d.add(DoctorCheck{Name: name, Status: CheckFail, Message: fmt.Sprintf("failed to create vault: %v", err), Fix: "Run 'sx init' to reconfigure"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, 30*time.Second)
+	defer cancel()
+
+	data, _, _, err := vault.GetLockFile(ctx, "")
+	if err != nil {
+		d.add(DoctorCheck{Name: name, Status: CheckFail, Message: err.Error(), Fix: vaultFixHint(d.cfg, err)})
+		return
+	}
+
+	lf, err := lockfile.Parse(data)
+	if err != nil {
+		d.add(DoctorCheck{Name: name, Status: CheckPass, Message: "vault reachable"})
+		d.add(DoctorCheck{Name: "Lock file", Status: CheckFail, Message: fmt.Sprintf("failed to parse lock file: %v", err), Fix: "Fix the lock file in the vault"})
+		return
+	}
+
+	d.lockFile = lf
+	d.add(DoctorCheck{Name: name, Status: CheckPass, Message: "vault reachable"})
+}
+
+// vaultFixHint suggests a fix for a vault access error
+func vaultFixHint(cfg *config.Config, err error) string {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "HTTP 401") || strings.Contains(msg, "HTTP 403"):
+		return "Your Sleuth token is invalid or expired. Run 'sx init' to log in again"
+	case strings.Contains(msg, "Permission denied (publickey)"):
+		return "The git server rejected your SSH key. Pass --ssh-key or set SX_SSH_KEY, and check the key with 'ssh -T' against the git host"
+	case strings.Contains(msg, "Host key verification failed"):
+		return "The git host is not in known_hosts. Connect once with 'ssh' to accept its host key"
+	case strings.Contains(msg, "Authentication failed") || strings.Contains(msg, "could not read Username"):
+		return "Git credentials are missing or invalid. Check your credential helper or use an SSH URL"
+	case cfg.Type == config.RepositoryTypePath:
+		return "Check that the vault directory exists and is readable"
+	default:
+		return "Check your network connection and the vault URL ('sx config')"
+	}
+}
+
+// checkLockFile validates the lock file fetched by checkVault
+func (d *doctor) checkLockFile() {
+	const name = "Lock file"
+
+	if d.lockFile == nil {
+		// checkVault already reported why there is no lock file
+		return
+	}
+
+	if err := d.lockFile.Validate(); err != nil {
+		d.add(DoctorCheck{Name: name, Status: CheckFail, Message: fmt.Sprintf("validation failed: %v", err), Fix: "Fix the lock file in the vault"})
+		d.lockFile = nil
+		return
+	}
+
+	d.add(DoctorCheck{Name: name, Status: CheckPass, Message: fmt.Sprintf("%d asset(s)", len(d.lockFile.Assets))})
+}
+
+// checkClients verifies at least one enabled client is installed
+func (d *doctor) checkClients() {
+	const name = "Clients"
+
+	detected := clients.Global().DetectInstalled()
+	if d.cfg != nil {
+		d.clients = filterClientsByConfig(d.cfg, detected)
+	} else {
+		d.clients = detected
+	}
+
+	if len(d.clients) == 0 {
+		fix := "Install Claude Code or Cursor"
+		if len(detected) > 0 {
+			fix = "Enable a detected client with 'sx init' (enabled clients are limited in config)"
+		}
+		d.add(DoctorCheck{Name: name, Status: CheckFail, Message: "no enabled AI coding clients detected", Fix: fix})
+		return
+	}
+
+	names := make([]string, 0, len(d.clients))
+	for _, c := range d.clients {
+		names = append(names, c.DisplayName())
+	}
+	d.add(DoctorCheck{Name: name, Status: CheckPass, Message: strings.Join(names, ", ")})
+}
+
+// checkHooks verifies the sx system hooks are present for each client
+func (d *doctor) checkHooks() {
+	for _, client := range d.clients {
+		name := fmt.Sprintf("%s hooks", client.DisplayName())
+		dir := getClientDirectory(client.ID())
+
+		missing := missingSystemHooks(client.ID(), dir)
+		if len(missing) == 0 {
+			d.add(DoctorCheck{Name: name, Status: CheckPass, Message: "installed"})
+			continue
+		}
+
+		d.add(DoctorCheck{
+			Name:    name,
+			Status:  CheckFail,
+			Message: fmt.Sprintf("missing: %s", strings.Join(missing, ", ")),
+			Fix:     "Run 'sx install' or 'sx doctor --fix' to reinstall hooks",
+			fixFunc: func() error {
+				return client.InstallHooks(d.ctx)
+			},
+		})
+	}
+}
+
+// missingSystemHooks returns the sx hooks not found in a client's config
+func missingSystemHooks(clientID, clientDir string) []string {
+	var missing []string
+
+	switch clientID {
+	case clients.ClientIDClaudeCode:
+		data, _ := os.ReadFile(filepath.Join(clientDir, "settings.json"))
+		content := string(data)
+		if !strings.Contains(content, "sx install") && !strings.Contains(content, "skills install") {
+			missing = append(missing, "SessionStart (auto-install)")
+		}
+		if !strings.Contains(content, "report-usage") {
+			missing = append(missing, "PostToolUse (usage reporting)")
+		}
+	case clients.ClientIDCursor:
+		if !checkHooksInstalled(clientID, clientDir) {
+			missing = append(missing, "beforeSubmitPrompt (auto-install)")
+		}
+	}
+
+	return missing
+}
+
+// checkCursorMCP verifies the skills MCP server is registered in Cursor's global mcp.json
+func (d *doctor) checkCursorMCP() {
+	const name = "Cursor MCP server"
+
+	var cursorClient clients.Client
+	for _, c := range d.clients {
+		if c.ID() == clients.ClientIDCursor {
+			cursorClient = c
+		}
+	}
+	if cursorClient == nil {
+		return
+	}
+
+	fixFunc := func() error {
+		return cursorClient.EnsureAssetSupport(d.ctx, &clients.InstallScope{Type: clients.ScopeGlobal})
+	}
+
+	mcpConfigPath := filepath.Join(getClientDirectory(clients.ClientIDCursor), "mcp.json")
+	mcpConfig, err := handlers.ReadMCPConfig(mcpConfigPath)
+	if err != nil {
+		d.add(DoctorCheck{Name: name, Status: CheckFail, Message: fmt.Sprintf("failed to read %s: %v", mcpConfigPath, err), Fix: "Fix the JSON syntax in mcp.json"})
+		return
+	}
+
+	entry, ok := mcpConfig.MCPServers["skills"].(map[string]interface{})
+	if !ok {
+		d.add(DoctorCheck{
+			Name:    name,
+			Status:  CheckFail,
+			Message: "skills server not registered in mcp.json",
+			Fix:     "Run 'sx install' or 'sx doctor --fix' to register it",
+			fixFunc: fixFunc,
+		})
+		return
+	}
+
+	// The entry points at the sx binary; it goes stale if sx is moved or reinstalled elsewhere
+	if command, _ := entry["command"].(string); command != "" && filepath.IsAbs(command) && !utils.FileExists(command) {
+		d.add(DoctorCheck{
+			Name:    name,
+			Status:  CheckWarn,
+			Message: fmt.Sprintf("registered command %s does not exist", command),
+			Fix:     "Remove the \"skills\" entry from mcp.json and run 'sx install'",
+		})
+		return
+	}
+
+	d.add(DoctorCheck{Name: name, Status: CheckPass, Message: "registered"})
+}
+
+// checkInstalledAssets compares the tracker with what is actually on disk
+func (d *doctor) checkInstalledAssets() {
+	const name = "Installed assets"
+
+	tracker, err := assets.LoadTracker()
+	if err != nil {
+		trackerPath, _ := assets.GetTrackerPath()
+		d.add(DoctorCheck{
+			Name:    name,
+			Status:  CheckFail,
+			Message: fmt.Sprintf("tracker is corrupt: %v", err),
+			Fix:     fmt.Sprintf("Delete %s and run 'sx install'", trackerPath),
+			fixFunc: func() error {
+				if err := assets.DeleteTracker(); err != nil {
+					return err
+				}
+				return d.reinstall()
+			},
+		})
+		return
+	}
+
+	if d.lockFile == nil || len(d.clients) == 0 {
+		d.add(DoctorCheck{Name: name, Status: CheckWarn, Message: "skipped (no lock file or clients)"})
+		return
+	}
+
+	gitContext, err := gitutil.DetectContext(d.ctx)
+	if err != nil {
+		d.add(DoctorCheck{Name: name, Status: CheckWarn, Message: fmt.Sprintf("skipped (failed to detect git context: %v)", err)})
+		return
+	}
+	d.currentScope = currentScopeFromGit(gitContext)
+
+	// Expect the same assets install would install here
+	fetcher := assets.NewOfflineAssetFetcher()
+	if vault, err := vaultpkg.NewFromConfig(d.cfg); err == nil {
+		fetcher = assets.NewAssetFetcher(vault)
+	}
+	identity := audience.Resolve(d.ctx, d.cfg)
+	applicable := selectApplicableAssets(d.ctx, d.lockFile, identity, d.clients, scope.NewMatcher(d.currentScope), fetcher, gitContext)
+
+	var missing, outdated []string
+	for _, art := range applicable {
+		existing := tracker.FindAsset(assetKeyForInstall(art, d.currentScope))
+		if existing == nil {
+			if !skippedForRequires(d.ctx, art, fetcher) {
+				missing = append(missing, art.Name+" (never installed)")
+			}
+			continue
+		}
+		if existing.Version != art.Version {
+			outdated = append(outdated, fmt.Sprintf("%s (%s → %s)", art.Name, existing.Version, art.Version))
+			continue
+		}
+
+		artScope := buildInstallScopeForAsset(art, gitContext)
+		for _, client := range d.clients {
+			if !containsString(existing.Clients, client.ID()) {
+				continue
+			}
+			for _, result := range client.VerifyAssets(d.ctx, []*lockfile.Asset{art}, artScope) {
+				if !result.Installed {
+					missing = append(missing, fmt.Sprintf("%s for %s: %s", art.Name, client.DisplayName(), result.Message))
+				}
+			}
+		}
+	}
+
+	switch {
+	case len(missing) > 0:
+		d.add(DoctorCheck{
+			Name:    name,
+			Status:  CheckFail,
+			Message: fmt.Sprintf("%d asset(s) not installed: %s", len(missing), strings.Join(missing, "; ")),
+			Fix:     "Run 'sx install --repair'",
+			fixFunc: d.reinstall,
+		})
+	case len(outdated) > 0:
+		d.add(DoctorCheck{
+			Name:    name,
+			Status:  CheckWarn,
+			Message: fmt.Sprintf("%d asset(s) out of date: %s", len(outdated), strings.Join(outdated, "; ")),
+			Fix:     "Run 'sx install'",
+			fixFunc: d.reinstall,
+		})
+	default:
+		d.add(DoctorCheck{Name: name, Status: CheckPass, Message: "all assets for this context are installed"})
+	}
+}
+
+// reinstall runs install in repair mode; shared by fixes so it only runs once
+func (d *doctor) reinstall() error {
+	if d.reinstalled {
+		return nil
+	}
+	d.reinstalled = true
+
+	// Keep stdout for the JSON report by sending install's output to stderr
+	cmd := d.cmd
+	if d.jsonOutput {
+		cmd = &cobra.Command{}
+		cmd.SetIn(d.cmd.InOrStdin())
+		cmd.SetOut(d.cmd.ErrOrStderr())
+		cmd.SetErr(d.cmd.ErrOrStderr())
+		cmd.SetContext(d.cmd.Context())
+	}
+	return runInstall(cmd, nil, false, "", true, false)
+}
+
+// checkCache verifies the cache directory and its contents are usable
+func (d *doctor) checkCache() {
+	const name = "Cache"
+
+	cacheDir, err := cache.GetCacheDir()
+	if err != nil {
+		d.add(DoctorCheck{Name: name, Status: CheckFail, Message: err.Error(), Fix: "Set SX_CACHE_DIR to a writable directory"})
+		return
+	}
+
+	// The cache must be writable
+	if err := utils.EnsureDir(cacheDir); err != nil {
+		d.add(DoctorCheck{Name: name, Status: CheckFail, Message: fmt.Sprintf("cannot create %s: %v", cacheDir, err), Fix: "Fix the directory permissions or set SX_CACHE_DIR"})
+		return
+	}
+	probe, err := os.CreateTemp(cacheDir, ".doctor-*")
+	if err != nil {
+		d.add(DoctorCheck{Name: name, Status: CheckFail, Message: fmt.Sprintf("%s is not writable: %v", cacheDir, err), Fix: "Fix the directory permissions or set SX_CACHE_DIR"})
+		return
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	var problems []string
+	var fixes []func() error
+
+	// A cached lock file that no longer parses would be reused on 304 responses
+	if d.cfg != nil {
+		if data, err := cache.LoadLockFile(d.cfg.RepositoryURL); err == nil {
+			if _, err := lockfile.Parse(data); err != nil {
+				repoURL := d.cfg.RepositoryURL
+				problems = append(problems, "cached lock file is corrupt")
+				fixes = append(fixes, func() error { return cache.InvalidateLockFileCache(repoURL) })
+			}
+		}
+
+		// A git vault clone without .git cannot be updated
+		if d.cfg.Type == config.RepositoryTypeGit {
+			if clonePath, err := cache.GetGitRepoCachePath(d.cfg.RepositoryURL); err == nil && utils.IsDirectory(clonePath) {
+				if !utils.IsDirectory(filepath.Join(clonePath, ".git")) {
+					problems = append(problems, "git vault clone is corrupt")
+					fixes = append(fixes, func() error { return os.RemoveAll(clonePath) })
+				}
+			}
+		}
+	}
+
+	// An install that was interrupted and never rolled back
+	if journalDir, err := journal.GetJournalDir(); err == nil && utils.FileExists(filepath.Join(journalDir, "pending", "journal.json")) {
+		problems = append(problems, "an interrupted install was not rolled back")
+		fixes = append(fixes, func() error {
+			// Begin rolls back the stale journal; committing the empty one clears it
+			j, err := journal.Begin(context.Background())
+			if err != nil {
+				return err
+			}
+			return j.Commit()
+		})
+	}
+
+	if len(problems) == 0 {
+		d.add(DoctorCheck{Name: name, Status: CheckPass, Message: cacheDir})
+		return
+	}
+
+	d.add(DoctorCheck{
+		Name:    name,
+		Status:  CheckWarn,
+		Message: strings.Join(problems, "; "),
+		Fix:     "Run 'sx doctor --fix' to clean up the cache",
+		fixFunc: func() error {
+			for _, fix := range fixes {
+				if err := fix(); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// checkClockSkew compares the local clock with the Sleuth server's
+// Large skew breaks token validation and makes cache timestamps misleading
+func (d *doctor) checkClockSkew() {
+	const name = "Clock"
+
+	if d.cfg == nil || d.cfg.Type != config.RepositoryTypeSleuth {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, d.cfg.GetServerURL(), nil)
+	if err != nil {
+		d.add(DoctorCheck{Name: name, Status: CheckWarn, Message: fmt.Sprintf("could not check: %v", err)})
+		return
+	}
+	before := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		d.add(DoctorCheck{Name: name, Status: CheckWarn, Message: fmt.Sprintf("could not check: %v", err)})
+		return
+	}
+	resp.Body.Close()
+
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		d.add(DoctorCheck{Name: name, Status: CheckWarn, Message: "could not check: server did not return a Date header"})
+		return
+	}
+
+	// Compare against the midpoint of the request to discount latency
+	local := before.Add(time.Since(before) / 2)
+	skew := local.Sub(serverTime)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxClockSkew {
+		d.add(DoctorCheck{
+			Name:    name,
+			Status:  CheckWarn,
+			Message: fmt.Sprintf("local clock differs from server by %s", skew.Round(time.Second)),
+			Fix:     "Enable automatic time synchronization (NTP) on this machine",
+		})
+		return
+	}
+
+	d.add(DoctorCheck{Name: name, Status: CheckPass, Message: fmt.Sprintf("in sync with server (±%s)", skew.Round(time.Second))})
+}
+
+// describeVaultURL returns the URL that identifies the configured vault
+func describeVaultURL(cfg *config.Config) string {
+	if cfg.Type == config.RepositoryTypeSleuth {
+		return cfg.GetServerURL()
+	}
+	return cfg.RepositoryURL
+}
+
+// currentScopeFromGit builds the scope for the current directory
+func currentScopeFromGit(gitContext *gitutil.GitContext) *scope.Scope {
+	if !gitContext.IsRepo {
+		return &scope.Scope{Type: scope.TypeGlobal}
+	}
+	if gitContext.RelativePath == "." {
+		return &scope.Scope{Type: scope.TypeRepo, RepoURL: gitContext.RepoURL, Branch: gitContext.Branch}
+	}
+	return &scope.Scope{Type: scope.TypePath, RepoURL: gitContext.RepoURL, RepoPath: gitContext.RelativePath, Branch: gitContext.Branch}
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// printDoctorText prints the report for humans
+func printDoctorText(cmd *cobra.Command, output DoctorOutput, fix bool) {
+	styledOut := ui.NewOutput(cmd.OutOrStdout(), cmd.ErrOrStderr())
+	symbols := styledOut.Theme().Symbols()
+
+	styledOut.Header("sx doctor")
+	styledOut.Newline()
+
+	for _, c := range output.Checks {
+		sym := symbols.Success
+		switch c.Status {
+		case CheckWarn:
+			sym = symbols.Warning
+		case CheckFail:
+			sym = symbols.Error
+		}
+
+		line := fmt.Sprintf("%s: %s", styledOut.BoldText(c.Name), c.Message)
+		if c.Fixed {
+			line += " " + styledOut.SuccessText("(fixed)")
+		}
+		styledOut.ListItem(sym, line)
+		if c.Status != CheckPass && c.Fix != "" {
+			styledOut.Muted(fmt.Sprintf("      Fix: %s", c.Fix))
+		}
+	}
+
+	styledOut.Newline()
+	summary := fmt.Sprintf("%d passed, %d warning(s), %d failed", output.Summary.Pass, output.Summary.Warn, output.Summary.Fail)
+	switch {
+	case output.Summary.Fail > 0:
+		styledOut.Error(summary)
+	case output.Summary.Warn > 0:
+		styledOut.Warning(summary)
+	default:
+		styledOut.Success(summary)
+	}
+
+	if !fix && hasAutomaticFixes(output.Checks) {
+		styledOut.Info("Run 'sx doctor --fix' to fix what can be fixed automatically")
+	}
+}
+
+// hasAutomaticFixes reports whether any failing check can be fixed with --fix
+func hasAutomaticFixes(checks []DoctorCheck) bool {
+	for _, c := range checks {
+		if c.Status != CheckPass && c.fixFunc != nil {
+			return true
+		}
+	}
+	return false
+}
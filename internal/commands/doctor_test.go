@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func runDoctorJSON(t *testing.T, args ...string) (DoctorOutput, error) {
+	t.Helper()
+
+	cmd := NewDoctorCommand()
+	cmd.SetArgs(append([]string{"--json"}, args...))
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&bytes.Buffer{})
+	err := cmd.Execute()
+
+	var output DoctorOutput
+	if jsonErr := json.Unmarshal(stdout.Bytes(), &output); jsonErr != nil {
+		t.Fatalf("failed to parse doctor output: %v\n%s", jsonErr, stdout.String())
+	}
+	return output, err
+}
+
+func findCheck(output DoctorOutput, name string) *DoctorCheck {
+	for i := range output.Checks {
+		if output.Checks[i].Name == name {
+			return &output.Checks[i]
+		}
+	}
+	return nil
+}
+
+func TestDoctor(t *testing.T) {
+	env := NewTestEnv(t)
+	workingDir := env.MkdirAll(filepath.Join(env.TempDir, "working"))
+	env.Chdir(workingDir)
+
+	t.Run("missing config fails with a hint", func(t *testing.T) {
+		output, err := runDoctorJSON(t)
+		if err == nil {
+			t.Error("expected doctor to fail without config")
+		}
+		c := findCheck(output, "Configuration")
+		if c == nil || c.Status != CheckFail || c.Fix == "" {
+			t.Errorf("unexpected configuration check: %+v", c)
+		}
+	})
+
+	vaultDir := env.SetupPathVault()
+	env.AddSkillToVault(vaultDir, "doctor-skill", "1.0.0")
+	env.WriteLockFile(vaultDir, `lock-version = "1"
+version = "1.0.0"
+created-by = "test"
+
+[[assets]]
+name = "doctor-skill"
+version = "1.0.0"
+type = "skill"
+
+[assets.source-path]
+path = "assets/doctor-skill/1.0.0"
+`)
+
+	if err := NewInstallCommand().Execute(); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	t.Run("healthy install passes", func(t *testing.T) {
+		output, err := runDoctorJSON(t)
+		if err != nil {
+			t.Fatalf("doctor failed: %v\n%+v", err, output.Checks)
+		}
+		for _, name := range []string{"Configuration", "Vault access", "Lock file", "Claude Code hooks", "Installed assets"} {
+			if c := findCheck(output, name); c == nil || c.Status != CheckPass {
+				t.Errorf("expected %s to pass, got %+v", name, c)
+			}
+		}
+	})
+
+	// Break the install behind the tracker's back
+	skillDir := filepath.Join(env.GlobalClaudeDir(), "skills", "doctor-skill")
+	if err := os.RemoveAll(skillDir); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("missing asset is detected and fixed", func(t *testing.T) {
+		output, err := runDoctorJSON(t)
+		if err == nil {
+			t.Error("expected doctor to fail with a missing asset")
+		}
+		if c := findCheck(output, "Installed assets"); c == nil || c.Status != CheckFail {
+			t.Fatalf("expected Installed assets to fail, got %+v", c)
+		}
+
+		output, err = runDoctorJSON(t, "--fix")
+		if err != nil {
+			t.Fatalf("doctor --fix failed: %v\n%+v", err, output.Checks)
+		}
+		if c := findCheck(output, "Installed assets"); c == nil || !c.Fixed {
+			t.Errorf("expected Installed assets to be fixed, got %+v", c)
+		}
+		env.AssertFileExists(filepath.Join(skillDir, "SKILL.md"))
+	})
+
+	// Assets install deliberately leaves out aren't reported as never installed
+	env.AddSkillToVault(vaultDir, "sre-skill", "1.0.0")
+	deployerDir := env.AddSkillToVault(vaultDir, "deployer", "1.0.0")
+	env.WriteFile(filepath.Join(deployerDir, "metadata.toml"), `[asset]
+name = "deployer"
+version = "1.0.0"
+type = "skill"
+
+[skill]
+prompt-file = "SKILL.md"
+requires = ["sx-no-such-tool"]
+`)
+	env.WriteLockFile(vaultDir, `lock-version = "1"
+version = "1.0.0"
+created-by = "test"
+
+[[assets]]
+name = "doctor-skill"
+version = "1.0.0"
+type = "skill"
+
+[assets.source-path]
+path = "assets/doctor-skill/1.0.0"
+
+[[assets]]
+name = "sre-skill"
+version = "1.0.0"
+type = "skill"
+audience = ["sre"]
+
+[assets.source-path]
+path = "assets/sre-skill/1.0.0"
+
+[[assets]]
+name = "deployer"
+version = "1.0.0"
+type = "skill"
+missing-requires = "skip"
+
+[assets.source-path]
+path = "assets/deployer/1.0.0"
+`)
+	if err := NewInstallCommand().Execute(); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	t.Run("assets left out by install are not missing", func(t *testing.T) {
+		output, err := runDoctorJSON(t)
+		if err != nil {
+			t.Fatalf("doctor failed: %v\n%+v", err, output.Checks)
+		}
+		if c := findCheck(output, "Installed assets"); c == nil || c.Status != CheckPass {
+			t.Errorf("expected Installed assets to pass, got %+v", c)
+		}
+	})
+}
@@ -12,7 +12,6 @@ import (
 
 	"github.com/sleuth-io/sx/internal/clients"
 	"github.com/sleuth-io/sx/internal/config"
-	"github.com/sleuth-io/sx/internal/registry"
 	"github.com/sleuth-io/sx/internal/ui"
 	"github.com/sleuth-io/sx/internal/ui/components"
 	"github.com/sleuth-io/sx/internal/utils"
@@ -541,8 +540,9 @@ func promptClientSelection(styledOut *ui.Output, existingEnabledClients []string
 func promptFeaturedSkills(cmd *cobra.Command, ctx context.Context) {
 	styledOut := ui.NewOutput(cmd.OutOrStdout(), cmd.ErrOrStderr())
 
-	skills, err := registry.FeaturedSkills()
-	if err != nil || len(skills) == 0 {
+	// The company's curated registries, if configured, replace the built-in list
+	skills, _ := loadRegistrySkills(ctx)
+	if len(skills) == 0 {
 		return
 	}
 
@@ -559,7 +559,7 @@ func promptFeaturedSkills(cmd *cobra.Command, ctx context.Context) {
 		for i, skill := range skills {
 			options[i+1] = components.Option{
 				Label:       skill.Name,
-				Value:       skill.URL,
+				Value:       skill.InstallURL(),
 				Description: skill.Description,
 			}
 		}
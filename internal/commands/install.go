@@ -3,23 +3,34 @@ package commands
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/sleuth-io/sx/internal/assets"
+	"github.com/sleuth-io/sx/internal/audience"
 	"github.com/sleuth-io/sx/internal/cache"
+	"github.com/sleuth-io/sx/internal/clientconfig"
 	"github.com/sleuth-io/sx/internal/clients"
 	"github.com/sleuth-io/sx/internal/clients/cursor"
 	"github.com/sleuth-io/sx/internal/config"
 	"github.com/sleuth-io/sx/internal/constants"
 	"github.com/sleuth-io/sx/internal/gitutil"
+	"github.com/sleuth-io/sx/internal/journal"
 	"github.com/sleuth-io/sx/internal/lockfile"
 	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/mcpruntime"
+	"github.com/sleuth-io/sx/internal/overlay"
 	"github.com/sleuth-io/sx/internal/scope"
+	"github.com/sleuth-io/sx/internal/telemetry"
 	"github.com/sleuth-io/sx/internal/ui"
 	"github.com/sleuth-io/sx/internal/ui/components"
 	vaultpkg "github.com/sleuth-io/sx/internal/vault"
@@ -30,20 +41,38 @@ func NewInstallCommand() *cobra.Command {
 	var hookMode bool
 	var clientID string
 	var fixMode bool
+	var offline bool
 
 	cmd := &cobra.Command{
 		Use:   "install",
 		Short: "Read lock file, fetch assets, and install locally",
 		Long: fmt.Sprintf(`Read the %s file, fetch assets from the configured vault,
-and install them to ~/.claude/ directory.`, constants.SkillLockFile),
+and install them to ~/.claude/ directory.
+
+With --offline, the cached lock file and asset zips are used exclusively and the
+vault is never contacted. Seed the cache on an air-gapped machine with
+'sx bundle import'.
+
+Repo-scoped assets can be tweaked per repository with an overlay directory,
+.sx/overlays/<asset>/. Its files replace the asset's files of the same name, and
+files ending in .append (e.g. SKILL.md.append) are appended to them. Editing an
+overlay reinstalls the asset on the next install.
+
+Packaged MCP servers that declare [mcp] runtime or setup have their dependencies
+installed (npm ci, uv sync, go build, ...) in a per-version cache directory they
+then run from. Setup runs code on this machine, so each asset version has to be
+approved once, interactively or with --approve-setup. --repair re-runs setups
+that are missing or incomplete.`, constants.SkillLockFile),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runInstall(cmd, args, hookMode, clientID, fixMode)
+			return runInstall(cmd, args, hookMode, clientID, fixMode, offline)
 		},
 	}
 
 	cmd.Flags().BoolVar(&hookMode, "hook-mode", false, "Run in hook mode (outputs JSON for Claude Code)")
 	cmd.Flags().StringVar(&clientID, "client", "", "Client ID that triggered the hook (used with --hook-mode)")
 	cmd.Flags().BoolVar(&fixMode, "repair", false, "Verify assets are actually installed and fix any discrepancies")
+	cmd.Flags().BoolVar(&offline, "offline", false, "Install from the local cache only, without contacting the vault")
+	cmd.Flags().Bool("approve-setup", false, "Allow packaged MCP servers to run their setup commands")
 	_ = cmd.Flags().MarkHidden("hook-mode") // Hide from help output since it's internal
 	_ = cmd.Flags().MarkHidden("client")    // Hide from help output since it's internal
 
@@ -51,10 +80,14 @@ and install them to ~/.claude/ directory.`, constants.SkillLockFile),
 }
 
 // runInstall executes the install command
-func runInstall(cmd *cobra.Command, args []string, hookMode bool, hookClientID string, repairMode bool) error {
+func runInstall(cmd *cobra.Command, args []string, hookMode bool, hookClientID string, repairMode, offline bool) (retErr error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
 	defer cancel()
 
+	// Cancel on Ctrl-C instead of exiting so partial changes can be rolled back
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	log := logger.Get()
 	styledOut := ui.NewOutput(cmd.OutOrStdout(), cmd.ErrOrStderr())
 	styledOut.SetSilent(hookMode) // Suppress normal output in hook mode
@@ -85,44 +118,105 @@ func runInstall(cmd *cobra.Command, args []string, hookMode bool, hookClientID s
 		return fmt.Errorf("failed to load configuration: %w\nRun 'sx init' to configure", err)
 	}
 
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid configuration: %w", err)
+	// Validate configuration (offline installs never talk to the vault, so
+	// credentials aren't needed; the vault URL only keys the cache)
+	if !offline {
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("invalid configuration: %w", err)
+		}
 	}
 
-	// Create vault instance
-	vault, err := vaultpkg.NewFromConfig(cfg)
+	// Start the root telemetry span; it is exported when install returns
+	otlpEndpoint := cfg.GetOTLPEndpoint()
+	if offline {
+		otlpEndpoint = "" // No network access at all
+	}
+	exporter := telemetry.Init(otlpEndpoint, cfg.OTLPHeaders)
+	ctx, installSpan := exporter.StartSpan(ctx, "sx.install",
+		"sx.vault.type", cfg.GetType(),
+		"sx.hook_mode", strconv.FormatBool(hookMode),
+		"sx.hook_client", hookClientID,
+		"sx.offline", strconv.FormatBool(offline))
+	defer func() {
+		installSpan.End(retErr)
+		flushTelemetry(exporter)
+	}()
+
+	// Journal every file the install touches so a failure leaves nothing half-applied
+	installJournal, err := beginInstallJournal(ctx, out)
 	if err != nil {
-		return fmt.Errorf("failed to create vault: %w", err)
+		// Hooks fire often, so the install already running will do the work
+		if hookMode {
+			log.Info("skipping install", "reason", err)
+			return nil
+		}
+		return fmt.Errorf("failed to start install: %w", err)
 	}
+	defer func() {
+		if retErr == nil && ctx.Err() != nil {
+			retErr = fmt.Errorf("install interrupted: %w", ctx.Err())
+		}
+		finishInstallJournal(installJournal, retErr, styledOut)
+	}()
 
-	// Fetch lock file with spinner
-	status.Start("Fetching lock file")
-
-	cachedETag, _ := cache.LoadETag(cfg.RepositoryURL)
+	// Surface client config warnings and ask before overwriting entries the user edited
+	defer setupClientConfigPrompts(cmd, styledOut, hookMode)()
 
-	lockFileData, newETag, notModified, err := vault.GetLockFile(ctx, cachedETag)
-	if err != nil {
-		status.Fail("Failed to fetch lock file")
-		return fmt.Errorf("failed to fetch lock file: %w", err)
+	// Create vault instance and asset fetcher
+	var vault vaultpkg.Vault
+	fetcher := assets.NewOfflineAssetFetcher()
+	if !offline {
+		vault, err = vaultpkg.NewFromConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to create vault: %w", err)
+		}
+		fetcher = assets.NewAssetFetcher(vault)
 	}
 
-	if notModified {
+	// Fetch lock file with spinner
+	var lockFileData []byte
+	if offline {
+		status.Start("Loading cached lock file")
 		lockFileData, err = cache.LoadLockFile(cfg.RepositoryURL)
 		if err != nil {
-			status.Fail("Failed to load cached lock file")
+			status.Fail("No cached lock file")
+			if os.IsNotExist(err) {
+				return fmt.Errorf("no cached lock file for %s\nRun 'sx install' while online or 'sx bundle import' first", cfg.RepositoryURL)
+			}
 			return fmt.Errorf("failed to load cached lock file: %w", err)
 		}
 	} else {
-		// Save ETag and lock file content
-		log := logger.Get()
-		if newETag != "" {
-			if err := cache.SaveETag(cfg.RepositoryURL, newETag); err != nil {
-				log.Error("failed to save ETag", "error", err)
-			}
+		status.Start("Fetching lock file")
+		_, lockSpan := exporter.StartSpan(ctx, "sx.install.lock_fetch")
+
+		cachedETag, _ := cache.LoadETag(cfg.RepositoryURL)
+
+		var newETag string
+		var notModified bool
+		lockFileData, newETag, notModified, err = vault.GetLockFile(ctx, cachedETag)
+		lockSpan.SetAttr("sx.lock.not_modified", strconv.FormatBool(notModified))
+		lockSpan.End(err)
+		if err != nil {
+			status.Fail("Failed to fetch lock file")
+			return fmt.Errorf("failed to fetch lock file: %w", err)
 		}
-		if err := cache.SaveLockFile(cfg.RepositoryURL, lockFileData); err != nil {
-			log.Error("failed to cache lock file", "error", err)
+
+		if notModified {
+			lockFileData, err = cache.LoadLockFile(cfg.RepositoryURL)
+			if err != nil {
+				status.Fail("Failed to load cached lock file")
+				return fmt.Errorf("failed to load cached lock file: %w", err)
+			}
+		} else {
+			// Save ETag and lock file content
+			if newETag != "" {
+				if err := cache.SaveETag(cfg.RepositoryURL, newETag); err != nil {
+					log.Error("failed to save ETag", "error", err)
+				}
+			}
+			if err := cache.SaveLockFile(cfg.RepositoryURL, lockFileData); err != nil {
+				log.Error("failed to cache lock file", "error", err)
+			}
 		}
 	}
 
@@ -157,12 +251,14 @@ func runInstall(cmd *cobra.Command, args []string, hookMode bool, hookClientID s
 				Type:     scope.TypeRepo,
 				RepoURL:  gitContext.RepoURL,
 				RepoPath: "",
+				Branch:   gitContext.Branch,
 			}
 		} else {
 			currentScope = &scope.Scope{
 				Type:     scope.TypePath,
 				RepoURL:  gitContext.RepoURL,
 				RepoPath: gitContext.RelativePath,
+				Branch:   gitContext.Branch,
 			}
 		}
 	} else {
@@ -218,33 +314,21 @@ func runInstall(cmd *cobra.Command, args []string, hookMode bool, hookClientID s
 		}
 	}
 
-	// Filter assets by client compatibility and scope
-	var applicableAssets []*lockfile.Asset
-	for i := range lockFile.Assets {
-		asset := &lockFile.Assets[i]
-
-		// Check if ANY target client supports this asset AND matches scope
-		supported := false
-		for _, client := range targetClients {
-			if asset.MatchesClient(client.ID()) &&
-				client.SupportsAssetType(asset.Type) &&
-				matcherScope.MatchesAsset(asset) {
-				supported = true
-				break
-			}
-		}
+	// Work out who we're installing for, to match assets' audience
+	identity := audience.Resolve(ctx, cfg)
 
-		if supported {
-			applicableAssets = append(applicableAssets, asset)
-		}
-	}
+	// Filter assets by audience, client compatibility, scope and language
+	applicableAssets := selectApplicableAssets(ctx, lockFile, identity, targetClients, matcherScope, fetcher, gitContext)
 
 	// Resolve dependencies (even if empty, we need to check for cleanup)
 	var sortedAssets []*lockfile.Asset
 	if len(applicableAssets) > 0 {
+		_, resolveSpan := exporter.StartSpan(ctx, "sx.install.resolve",
+			"sx.assets.count", strconv.Itoa(len(applicableAssets)))
 		resolver := assets.NewDependencyResolver(lockFile)
 		var err error
 		sortedAssets, err = resolver.Resolve(applicableAssets)
+		resolveSpan.End(err)
 		if err != nil {
 			return fmt.Errorf("dependency resolution failed: %w", err)
 		}
@@ -264,7 +348,11 @@ func runInstall(cmd *cobra.Command, args []string, hookMode bool, hookClientID s
 		repairTracker(ctx, tracker, sortedAssets, targetClients, gitContext, currentScope, out)
 	}
 
-	assetsToInstall := determineAssetsToInstall(tracker, sortedAssets, currentScope, targetClientIDs, out)
+	// Local overlays from .sx/overlays/ are part of what's installed, so a changed
+	// overlay triggers a reinstall just like a new version does
+	overlays := loadOverlays(sortedAssets, gitContext, out)
+
+	assetsToInstall := determineAssetsToInstall(tracker, sortedAssets, currentScope, targetClientIDs, overlays, out)
 
 	// Clean up assets that were removed from lock file (must run even if no assets to install!)
 	cleanupRemovedAssets(ctx, tracker, sortedAssets, gitContext, currentScope, targetClients, out)
@@ -272,7 +360,7 @@ func runInstall(cmd *cobra.Command, args []string, hookMode bool, hookClientID s
 	// Early exit if nothing to install
 	if len(assetsToInstall) == 0 {
 		// Save state even if nothing changed
-		saveInstallationState(tracker, sortedAssets, currentScope, targetClientIDs, out)
+		saveInstallationState(tracker, sortedAssets, currentScope, targetClientIDs, overlays, nil, nil, nil, out)
 
 		// Install client-specific hooks (e.g., auto-update, usage tracking)
 		installClientHooks(ctx, targetClients, out)
@@ -305,8 +393,10 @@ func runInstall(cmd *cobra.Command, args []string, hookMode bool, hookClientID s
 
 	// Download only the assets that need to be installed
 	status.Start(fmt.Sprintf("Downloading %d assets", len(assetsToInstall)))
-	fetcher := assets.NewAssetFetcher(vault)
+	_, downloadSpan := exporter.StartSpan(ctx, "sx.install.download",
+		"sx.assets.count", strconv.Itoa(len(assetsToInstall)))
 	results, err := fetcher.FetchAssets(ctx, assetsToInstall, 10)
+	downloadSpan.End(err)
 	if err != nil {
 		return fmt.Errorf("failed to fetch assets: %w", err)
 	}
@@ -341,11 +431,20 @@ func runInstall(cmd *cobra.Command, args []string, hookMode bool, hookClientID s
 		return fmt.Errorf("no assets downloaded successfully")
 	}
 
+	// Patch downloaded assets with the repository's overlays
+	applyOverlays(successfulDownloads, overlays, out)
+
+	// Check the tools each asset requires; assets set to skip without them are left out
+	successfulDownloads, requires := checkRequires(ctx, successfulDownloads)
+
+	// Install dependencies of packaged MCP servers (re-run from scratch on repair)
+	successfulDownloads, runtimes := prepareMCPRuntimes(ctx, cmd, successfulDownloads, cfg, hookMode, repairMode, out)
+
 	// Install assets to their appropriate locations
-	installResult := installAssets(ctx, successfulDownloads, gitContext, currentScope, targetClients, out)
+	installResult := installAssets(ctx, successfulDownloads, gitContext, currentScope, targetClients, runtimes, out)
 
 	// Save new installation state (saves ALL assets from lock file, not just changed ones)
-	saveInstallationState(tracker, sortedAssets, currentScope, targetClientIDs, out)
+	saveInstallationState(tracker, sortedAssets, currentScope, targetClientIDs, overlays, runtimes, requires, installResult.Skipped, out)
 
 	// Ensure skills support is configured for all clients (creates local rules files, etc.)
 	ensureAssetSupport(ctx, targetClients, buildInstallScope(currentScope, gitContext), out)
@@ -354,17 +453,27 @@ func runInstall(cmd *cobra.Command, args []string, hookMode bool, hookClientID s
 	if len(installResult.Installed) > 0 {
 		styledOut.Success(fmt.Sprintf("Installed %d assets", len(installResult.Installed)))
 		for _, name := range installResult.Installed {
-			styledOut.SuccessItem(name)
+			label := name
 			// Log version for this asset
 			for _, art := range successfulDownloads {
 				if art.Asset.Name == name {
 					log.Info("asset installed", "name", name, "version", art.Asset.Version, "type", art.Metadata.Asset.Type, "scope", currentScope.Type)
+					if art.Metadata.IsDangerous() {
+						label += " " + styledOut.WarningText("(dangerous command, asks for confirmation)")
+					}
 					break
 				}
 			}
+			styledOut.SuccessItem(label)
 		}
 	}
 
+	requires.report(styledOut)
+
+	if len(installResult.Skipped) > 0 {
+		styledOut.Warning(fmt.Sprintf("Skipped %d assets because a client config file is malformed; fix it and run 'sx install' again", len(installResult.Skipped)))
+	}
+
 	if len(installResult.Failed) > 0 {
 		styledOut.Error(fmt.Sprintf("Failed to install %d assets", len(installResult.Failed)))
 		for i, name := range installResult.Failed {
@@ -380,8 +489,9 @@ func runInstall(cmd *cobra.Command, args []string, hookMode bool, hookClientID s
 	// Log summary
 	log.Info("install completed", "installed", len(installResult.Installed), "failed", len(installResult.Failed))
 
-	// If in hook mode and assets were installed, output JSON message
-	if hookMode && len(installResult.Installed) > 0 {
+	// If in hook mode and assets were installed or are missing prerequisites, output JSON message
+	notice := requires.notice()
+	if hookMode && (len(installResult.Installed) > 0 || notice != "") {
 		// Build asset list message with type info
 		type assetInfo struct {
 			name string
@@ -414,14 +524,14 @@ func runInstall(cmd *cobra.Command, args []string, hookMode bool, hookClientID s
 			// Single asset - more compact message
 			message = fmt.Sprintf("%ssx%s installed the %s%s %s%s. %sRestart Claude Code to use it.%s",
 				bold, resetBold, blue, installedAssets[0].name, installedAssets[0].typ, reset, red, reset)
-		} else if len(installedAssets) <= 3 {
+		} else if len(installedAssets) > 1 && len(installedAssets) <= 3 {
 			// List all items
 			message = fmt.Sprintf("%ssx%s installed:\n", bold, resetBold)
 			for _, asset := range installedAssets {
 				message += fmt.Sprintf("- The %s%s %s%s\n", blue, asset.name, asset.typ, reset)
 			}
 			message += fmt.Sprintf("\n%sRestart Claude Code to use them.%s", red, reset)
-		} else {
+		} else if len(installedAssets) > 3 {
 			// Show first 3 and count remaining
 			message = fmt.Sprintf("%ssx%s installed:\n", bold, resetBold)
 			for i := 0; i < 3; i++ {
@@ -430,6 +540,12 @@ func runInstall(cmd *cobra.Command, args []string, hookMode bool, hookClientID s
 			remaining := len(installedAssets) - 3
 			message += fmt.Sprintf("and %d more\n\n%sRestart Claude Code to use them.%s", remaining, red, reset)
 		}
+		if notice != "" {
+			if message != "" {
+				message += "\n"
+			}
+			message += notice
+		}
 
 		// Output JSON response
 		response := map[string]interface{}{
@@ -446,6 +562,111 @@ func runInstall(cmd *cobra.Command, args []string, hookMode bool, hookClientID s
 	return nil
 }
 
+// flushTelemetry exports buffered spans without holding up the install for long
+// Failures are logged by the exporter and otherwise ignored
+func flushTelemetry(exporter *telemetry.Exporter) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_ = exporter.Flush(ctx)
+}
+
+// beginInstallJournal starts the install journal and snapshots the tracker
+// Returns a nil journal (install proceeds without rollback support) if the journal
+// cannot be created, and journal.ErrInstallInProgress if another install holds the lock
+func beginInstallJournal(ctx context.Context, out *outputHelper) (*journal.Journal, error) {
+	log := logger.Get()
+
+	j, err := journal.Begin(ctx)
+	if errors.Is(err, journal.ErrInstallInProgress) {
+		return nil, err
+	}
+	if err != nil {
+		out.printfErr("Warning: failed to start install journal, rollback will not be available: %v\n", err)
+		log.Error("failed to start install journal", "error", err)
+		return nil, nil
+	}
+
+	if trackerPath, err := assets.GetTrackerPath(); err == nil {
+		if err := j.Snapshot(trackerPath); err != nil {
+			log.Error("failed to snapshot tracker", "error", err)
+		}
+	}
+
+	return j, nil
+}
+
+// finishInstallJournal commits the journal on success or rolls every change back on failure
+// A successful install that changed nothing keeps the previous rollback point.
+func finishInstallJournal(j *journal.Journal, installErr error, styledOut *ui.Output) {
+	if j == nil {
+		return
+	}
+
+	log := logger.Get()
+	changes := clientChanges(j)
+
+	if installErr == nil {
+		if changes == 0 {
+			if err := j.Discard(); err != nil {
+				log.Error("failed to discard install journal", "error", err)
+			}
+			return
+		}
+		if err := j.Commit(); err != nil {
+			log.Error("failed to commit install journal", "error", err)
+		}
+		return
+	}
+
+	if err := j.Rollback(); err != nil {
+		styledOut.Error(fmt.Sprintf("Failed to roll back install: %v", err))
+		log.Error("install rollback failed", "error", err)
+		return
+	}
+
+	if changes > 0 {
+		styledOut.Warning(fmt.Sprintf("Install failed, rolled back %d change(s)", changes))
+	}
+	log.Info("install rolled back", "paths", len(j.Entries), "reason", installErr)
+}
+
+// clientChanges counts the journaled client changes (the tracker snapshot is
+// bookkeeping, not a change)
+func clientChanges(j *journal.Journal) int {
+	trackerPath, _ := assets.GetTrackerPath()
+	changes := 0
+	for _, e := range j.Entries {
+		if e.Path != trackerPath {
+			changes++
+		}
+	}
+	return changes
+}
+
+// setupClientConfigPrompts routes client config warnings to the output and, when
+// interactive, asks whether to overwrite sx-managed entries the user has edited
+// Returns a function that restores the defaults (keep user edits, log only)
+func setupClientConfigPrompts(cmd *cobra.Command, styledOut *ui.Output, hookMode bool) func() {
+	clientconfig.SetWarningHandler(styledOut.Warning)
+
+	if !hookMode && ui.IsStdinTTY() {
+		clientconfig.SetConflictResolver(func(c clientconfig.Conflict) bool {
+			action := "Overwrite it with the new version?"
+			if c.Proposed == nil {
+				action = "Remove it anyway?"
+			}
+			msg := fmt.Sprintf("%s in %s was edited outside sx. %s", c.Entry, c.File, action)
+			overwrite, err := components.ConfirmWithIO(msg, false, cmd.InOrStdin(), cmd.OutOrStdout())
+			return err == nil && overwrite
+		})
+	}
+
+	return func() {
+		clientconfig.SetConflictResolver(nil)
+		clientconfig.SetWarningHandler(nil)
+	}
+}
+
 // loadTracker loads the global tracker
 func loadTracker(out *outputHelper) *assets.Tracker {
 	tracker, err := assets.LoadTracker()
@@ -462,7 +683,7 @@ func loadTracker(out *outputHelper) *assets.Tracker {
 }
 
 // determineAssetsToInstall finds which assets need to be installed (new or changed)
-func determineAssetsToInstall(tracker *assets.Tracker, sortedAssets []*lockfile.Asset, currentScope *scope.Scope, targetClientIDs []string, out *outputHelper) []*lockfile.Asset {
+func determineAssetsToInstall(tracker *assets.Tracker, sortedAssets []*lockfile.Asset, currentScope *scope.Scope, targetClientIDs []string, overlays map[string]*overlay.Overlay, out *outputHelper) []*lockfile.Asset {
 	log := logger.Get()
 
 	var assetsToInstall []*lockfile.Asset
@@ -474,6 +695,9 @@ func determineAssetsToInstall(tracker *assets.Tracker, sortedAssets []*lockfile.
 				log.Info("asset version update", "name", art.Name, "old_version", existing.Version, "new_version", art.Version)
 			}
 			assetsToInstall = append(assetsToInstall, art)
+		} else if existing := tracker.FindAsset(key); existing != nil && !existing.IsLinked() && existing.OverlayHash != overlayHash(overlays, art.Name) {
+			log.Info("asset overlay changed", "name", art.Name)
+			assetsToInstall = append(assetsToInstall, art)
 		}
 	}
 
@@ -500,14 +724,23 @@ func cleanupRemovedAssets(ctx context.Context, tracker *assets.Tracker, sortedAs
 	// Combine both scoped and global assets
 	allRelevantAssets := append(currentInScope, globalAssets...)
 
+	// Global installs are also removed once their asset is scoped (e.g. by auto-scope),
+	// since the scoped install replaces them
 	lockFileNames := make(map[string]bool)
+	globalNames := make(map[string]bool)
 	for _, art := range sortedAssets {
 		lockFileNames[art.Name] = true
+		if art.IsGlobal() {
+			globalNames[art.Name] = true
+		}
 	}
 
 	var removedAssets []assets.InstalledAsset
 	for _, installed := range allRelevantAssets {
-		if !lockFileNames[installed.Name] {
+		if installed.IsLinked() {
+			continue
+		}
+		if !lockFileNames[installed.Name] || (installed.Repository == "" && !globalNames[installed.Name]) {
 			removedAssets = append(removedAssets, installed)
 		}
 	}
@@ -551,7 +784,7 @@ func repairTracker(ctx context.Context, tracker *assets.Tracker, sortedAssets []
 	for _, art := range sortedAssets {
 		key := assetKeyForInstall(art, currentScope)
 		existing := tracker.FindAsset(key)
-		if existing != nil && existing.Version != art.Version {
+		if existing != nil && existing.Version != art.Version && !existing.IsLinked() {
 			out.printf("  ↻ %s version mismatch (tracker: %s, lock file: %s)\n", art.Name, existing.Version, art.Version)
 			log.Info("asset version mismatch", "name", art.Name, "tracker_version", existing.Version, "lock_version", art.Version)
 			// Remove from tracker so it will be reinstalled with correct version
@@ -560,8 +793,24 @@ func repairTracker(ctx context.Context, tracker *assets.Tracker, sortedAssets []
 		}
 	}
 
+	// Servers whose setup directory is gone or incomplete have to be set up again
+	for _, art := range sortedAssets {
+		key := assetKeyForInstall(art, currentScope)
+		existing := tracker.FindAsset(key)
+		if existing != nil && existing.RuntimeDir != "" && !mcpruntime.Ready(existing.RuntimeDir) {
+			out.printf("  ✗ %s setup is missing or incomplete\n", art.Name)
+			log.Info("mcp runtime not ready", "name", art.Name, "dir", existing.RuntimeDir)
+			tracker.RemoveAsset(key)
+			totalMissing++
+		}
+	}
+
 	// Verify each asset at its proper install location (based on asset's scope)
 	for _, art := range sortedAssets {
+		if existing := tracker.FindAsset(assetKeyForInstall(art, currentScope)); existing != nil && existing.IsLinked() {
+			continue
+		}
+
 		// Get the proper scope for this asset
 		artScope := buildInstallScopeForAsset(art, gitContext)
 
@@ -614,7 +863,7 @@ func repairTracker(ctx context.Context, tracker *assets.Tracker, sortedAssets []
 }
 
 // installAssets installs assets to all detected clients using the orchestrator
-func installAssets(ctx context.Context, successfulDownloads []*assets.AssetWithMetadata, gitContext *gitutil.GitContext, currentScope *scope.Scope, targetClients []clients.Client, out *outputHelper) *assets.InstallResult {
+func installAssets(ctx context.Context, successfulDownloads []*assets.AssetWithMetadata, gitContext *gitutil.GitContext, currentScope *scope.Scope, targetClients []clients.Client, runtimes *runtimeSetup, out *outputHelper) *assets.InstallResult {
 	out.println("Installing assets...")
 
 	// Install each asset to its proper scope
@@ -645,6 +894,9 @@ func installAssets(ctx context.Context, successfulDownloads []*assets.AssetWithM
 		}
 	}
 
+	// Assets whose setup failed never reached the clients; report them as failed
+	addSetupFailures(allResults, runtimes, targetClients)
+
 	// Process and report results
 	return processInstallationResults(allResults, out)
 }
@@ -712,9 +964,19 @@ func processInstallationResults(allResults map[string]clients.InstallResponse, o
 			switch result.Status {
 			case clients.StatusSuccess:
 				out.printf("  ✓ %s → %s\n", result.AssetName, client.DisplayName())
+				for _, warning := range result.Warnings {
+					out.printfErr("  ! %s → %s: %s\n", result.AssetName, client.DisplayName(), warning)
+				}
 				successfullyInstalled[result.AssetName] = true
 			case clients.StatusFailed:
 				out.printfErr("  ✗ %s → %s: %v\n", result.AssetName, client.DisplayName(), result.Error)
+				// A malformed config only fails the assets that needed it; the rest of the install goes ahead
+				if errors.Is(result.Error, clientconfig.ErrSkippedMalformed) {
+					if !slices.Contains(installResult.Skipped, result.AssetName) {
+						installResult.Skipped = append(installResult.Skipped, result.AssetName)
+					}
+					continue
+				}
 				installResult.Failed = append(installResult.Failed, result.AssetName)
 				installResult.Errors = append(installResult.Errors, result.Error)
 			case clients.StatusSkipped:
@@ -729,7 +991,7 @@ func processInstallationResults(allResults map[string]clients.InstallResponse, o
 	}
 
 	// Add error if ANY client failed
-	if clients.HasAnyErrors(allResults) {
+	if len(installResult.Failed) > 0 && clients.HasAnyErrors(allResults) {
 		installResult.Errors = append(installResult.Errors, fmt.Errorf("installation failed for one or more clients"))
 	}
 
@@ -760,16 +1022,56 @@ func ensureAssetSupport(ctx context.Context, targetClients []clients.Client, sco
 }
 
 // saveInstallationState saves the current installation state to tracker file
-func saveInstallationState(tracker *assets.Tracker, sortedAssets []*lockfile.Asset, currentScope *scope.Scope, targetClientIDs []string, out *outputHelper) {
+func saveInstallationState(tracker *assets.Tracker, sortedAssets []*lockfile.Asset, currentScope *scope.Scope, targetClientIDs []string, overlays map[string]*overlay.Overlay, runtimes *runtimeSetup, requires *requiresCheck, skipped []string, out *outputHelper) {
 	for _, art := range sortedAssets {
+		// Assets skipped for a malformed client config are retried once it's fixed
+		if slices.Contains(skipped, art.Name) {
+			continue
+		}
+
 		key := assetKeyForInstall(art, currentScope)
+		existing := tracker.FindAsset(key)
+		if existing != nil && existing.IsLinked() {
+			continue
+		}
+
+		// Keep the runtime of assets that weren't reinstalled, and leave failed
+		// setups untracked so the next install retries them
+		var runtimeDir string
+		var requiredTools []string
+		if existing != nil {
+			runtimeDir = existing.RuntimeDir
+			requiredTools = existing.Requires
+		}
+		if runtimes != nil {
+			if _, failed := runtimes.Failed[art.Name]; failed {
+				continue
+			}
+			if dir, ok := runtimes.Dirs[art.Name]; ok {
+				runtimeDir = dir
+			}
+		}
+
+		// Likewise assets skipped for missing prerequisites are retried once the tools are there
+		if requires != nil {
+			if requires.Skipped[art.Name] {
+				continue
+			}
+			if tools, ok := requires.Requires[art.Name]; ok {
+				requiredTools = tools
+			}
+		}
+
 		tracker.UpsertAsset(assets.InstalledAsset{
-			Name:       art.Name,
-			Version:    art.Version,
-			Type:       art.Type.Key,
-			Repository: key.Repository,
-			Path:       key.Path,
-			Clients:    targetClientIDs,
+			Name:        art.Name,
+			Version:     art.Version,
+			Type:        art.Type.Key,
+			Repository:  key.Repository,
+			Path:        key.Path,
+			Clients:     targetClientIDs,
+			OverlayHash: overlayHash(overlays, art.Name),
+			RuntimeDir:  runtimeDir,
+			Requires:    requiredTools,
 		})
 	}
 
@@ -780,6 +1082,41 @@ func saveInstallationState(tracker *assets.Tracker, sortedAssets []*lockfile.Ass
 	}
 }
 
+// selectApplicableAssets returns the lock file assets install applies here: those
+// for the user's audience, supported by a target client, matching the scope, and
+// for assets with auto-scope = "languages", matching the repository's languages
+func selectApplicableAssets(ctx context.Context, lockFile *lockfile.LockFile, identity *audience.Identity, targetClients []clients.Client, matcherScope *scope.Matcher, fetcher *assets.AssetFetcher, gitContext *gitutil.GitContext) []*lockfile.Asset {
+	log := logger.Get()
+
+	var applicable []*lockfile.Asset
+	for i := range lockFile.Assets {
+		asset := &lockFile.Assets[i]
+
+		if !identity.Matches(asset.Audience) {
+			log.Debug("asset not for this user's groups", "name", asset.Name, "audience", asset.Audience, "groups", identity.Groups)
+			continue
+		}
+
+		// Check if ANY target client supports this asset AND matches scope
+		supported := false
+		for _, client := range targetClients {
+			if asset.MatchesClient(client.ID()) &&
+				client.SupportsAssetType(asset.Type) &&
+				matcherScope.MatchesAsset(asset) {
+				supported = true
+				break
+			}
+		}
+
+		if supported {
+			applicable = append(applicable, asset)
+		}
+	}
+
+	// Limit assets with auto-scope = "languages" to repositories using their languages
+	return autoScopeByLanguage(ctx, applicable, fetcher, gitContext)
+}
+
 // filterClientsByConfig returns only the clients that are both detected as installed
 // and enabled in the config. If EnabledClients is empty/nil, all detected clients are returned.
 func filterClientsByConfig(cfg *config.Config, detectedClients []clients.Client) []clients.Client {
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/assets"
+)
+
+func TestInstallSkipsAssetsForMalformedConfig(t *testing.T) {
+	env := NewTestEnv(t)
+	vaultDir := env.SetupPathVault()
+	env.AddSkillToVault(vaultDir, "helper", "1.0.0")
+
+	hookDir := filepath.Join(vaultDir, "assets", "lint", "1.0.0")
+	env.WriteFile(filepath.Join(hookDir, "metadata.toml"), `[asset]
+name = "lint"
+version = "1.0.0"
+type = "hook"
+
+[hook]
+event = "pre-commit"
+script-file = "hook.sh"
+`)
+	env.WriteFile(filepath.Join(hookDir, "hook.sh"), "#!/bin/sh\nexit 0\n")
+
+	env.WriteLockFile(vaultDir, `lock-version = "1"
+version = "1.0.0"
+created-by = "test"
+
+[[assets]]
+name = "helper"
+version = "1.0.0"
+type = "skill"
+
+[assets.source-path]
+path = "assets/helper/1.0.0"
+
+[[assets]]
+name = "lint"
+version = "1.0.0"
+type = "hook"
+
+[assets.source-path]
+path = "assets/lint/1.0.0"
+`)
+
+	// The hook goes into settings.json, which the user has broken
+	settingsPath := filepath.Join(env.GlobalClaudeDir(), "settings.json")
+	broken := "{\n  \"hooks\": {\n"
+	env.WriteFile(settingsPath, broken)
+
+	installCmd := NewInstallCommand()
+	var out bytes.Buffer
+	installCmd.SetOut(&out)
+	installCmd.SetErr(&out)
+	installCmd.SetArgs([]string{})
+	if err := installCmd.Execute(); err != nil {
+		t.Fatalf("install should go ahead without the hook: %v", err)
+	}
+
+	if !strings.Contains(out.String(), "Skipped 1 assets") {
+		t.Errorf("install should report the skipped hook, got:\n%s", out.String())
+	}
+	env.AssertFileExists(filepath.Join(env.GlobalClaudeDir(), "skills", "helper", "SKILL.md"))
+	if data, _ := os.ReadFile(settingsPath); string(data) != broken {
+		t.Errorf("malformed settings.json should be left untouched, got:\n%s", data)
+	}
+
+	tracker, err := assets.LoadTracker()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracked := make(map[string]bool)
+	for _, installed := range tracker.Assets {
+		tracked[installed.Name] = true
+	}
+	if !tracked["helper"] {
+		t.Error("skill should be tracked as installed")
+	}
+	if tracked["lint"] {
+		t.Error("hook should be left untracked so it's retried once settings.json is fixed")
+	}
+}
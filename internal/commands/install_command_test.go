@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallCommandAliasesAndDangerous(t *testing.T) {
+	env := NewTestEnv(t)
+	vaultDir := env.SetupPathVault()
+
+	assetDir := filepath.Join(vaultDir, "assets", "deploy", "1.0.0")
+	env.WriteFile(filepath.Join(assetDir, "metadata.toml"), `[asset]
+name = "deploy"
+version = "1.0.0"
+type = "command"
+
+[command]
+prompt-file = "COMMAND.md"
+aliases = ["ship"]
+dangerous = true
+`)
+	env.WriteFile(filepath.Join(assetDir, "COMMAND.md"), "Deploy to production.\n")
+
+	lockFile := `lock-version = "1"
+version = "1.0.0"
+created-by = "test"
+`
+	env.WriteLockFile(vaultDir, lockFile+`
+[[assets]]
+name = "deploy"
+version = "1.0.0"
+type = "command"
+
+[assets.source-path]
+path = "assets/deploy/1.0.0"
+`)
+	env.MkdirAll(env.GlobalClaudeDir())
+
+	var stdout, stderr bytes.Buffer
+	install := func() {
+		t.Helper()
+		stdout.Reset()
+		stderr.Reset()
+		installCmd := NewInstallCommand()
+		installCmd.SetOut(&stdout)
+		installCmd.SetErr(&stderr)
+		installCmd.SetArgs([]string{})
+		if err := installCmd.Execute(); err != nil {
+			t.Fatalf("install failed: %v", err)
+		}
+	}
+	install()
+
+	commandsDir := filepath.Join(env.GlobalClaudeDir(), "commands")
+	prompt, err := os.ReadFile(filepath.Join(commandsDir, "deploy.md"))
+	if err != nil {
+		t.Fatalf("command not installed: %v", err)
+	}
+	if !strings.Contains(string(prompt), "ask the user to") || !strings.HasSuffix(string(prompt), "Deploy to production.\n") {
+		t.Errorf("dangerous command should get the confirmation preamble, got:\n%s", prompt)
+	}
+
+	alias, err := os.ReadFile(filepath.Join(commandsDir, "ship.md"))
+	if err != nil {
+		t.Fatalf("alias not installed: %v", err)
+	}
+	if !strings.Contains(string(alias), filepath.Join(commandsDir, "deploy.md")) {
+		t.Errorf("alias should point to the command's prompt, got:\n%s", alias)
+	}
+
+	if !strings.Contains(stdout.String()+stderr.String(), "dangerous command") {
+		t.Errorf("install summary should mark the dangerous command, got:\n%s", stdout.String())
+	}
+
+	// Removing the command from the lock file removes its aliases too
+	env.WriteLockFile(vaultDir, lockFile)
+	install()
+	env.AssertFileNotExists(filepath.Join(commandsDir, "deploy.md"))
+	env.AssertFileNotExists(filepath.Join(commandsDir, "ship.md"))
+}
+
+func TestInstallRendersClaudeFrontmatter(t *testing.T) {
+	env := NewTestEnv(t)
+	vaultDir := env.SetupPathVault()
+
+	agentDir := filepath.Join(vaultDir, "assets", "reviewer", "1.0.0")
+	env.WriteFile(filepath.Join(agentDir, "metadata.toml"), `[asset]
+name = "reviewer"
+version = "1.0.0"
+type = "agent"
+description = "Reviews code"
+
+[agent]
+prompt-file = "AGENT.md"
+tools = ["Read", "Grep"]
+model = "sonnet"
+color = "blue"
+`)
+	env.WriteFile(filepath.Join(agentDir, "AGENT.md"), "You review code.\n")
+
+	commandDir := filepath.Join(vaultDir, "assets", "fix-issue", "1.0.0")
+	env.WriteFile(filepath.Join(commandDir, "metadata.toml"), `[asset]
+name = "fix-issue"
+version = "1.0.0"
+type = "command"
+
+[command]
+prompt-file = "COMMAND.md"
+description = "Fix a GitHub issue"
+argument-hint = "[issue-number]"
+allowed-tools = ["Bash(gh issue view:*)"]
+`)
+	env.WriteFile(filepath.Join(commandDir, "COMMAND.md"), "Fix issue $ARGUMENTS.\n")
+
+	env.WriteLockFile(vaultDir, `lock-version = "1"
+version = "1.0.0"
+created-by = "test"
+
+[[assets]]
+name = "reviewer"
+version = "1.0.0"
+type = "agent"
+
+[assets.source-path]
+path = "assets/reviewer/1.0.0"
+
+[[assets]]
+name = "fix-issue"
+version = "1.0.0"
+type = "command"
+
+[assets.source-path]
+path = "assets/fix-issue/1.0.0"
+`)
+	env.MkdirAll(env.GlobalClaudeDir())
+
+	installCmd := NewInstallCommand()
+	installCmd.SetOut(&bytes.Buffer{})
+	installCmd.SetErr(&bytes.Buffer{})
+	installCmd.SetArgs([]string{})
+	if err := installCmd.Execute(); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	agent, err := os.ReadFile(filepath.Join(env.GlobalClaudeDir(), "agents", "reviewer.md"))
+	if err != nil {
+		t.Fatalf("agent not installed: %v", err)
+	}
+	wantAgent := "---\nname: reviewer\ndescription: Reviews code\ntools: Read, Grep\nmodel: sonnet\ncolor: blue\n---\nYou review code.\n"
+	if string(agent) != wantAgent {
+		t.Errorf("agent =\n%s\nwant:\n%s", agent, wantAgent)
+	}
+
+	command, err := os.ReadFile(filepath.Join(env.GlobalClaudeDir(), "commands", "fix-issue.md"))
+	if err != nil {
+		t.Fatalf("command not installed: %v", err)
+	}
+	wantCommand := "---\ndescription: Fix a GitHub issue\nargument-hint: '[issue-number]'\nallowed-tools: Bash(gh issue view:*)\n---\nFix issue $ARGUMENTS.\n"
+	if string(command) != wantCommand {
+		t.Errorf("command =\n%s\nwant:\n%s", command, wantCommand)
+	}
+}
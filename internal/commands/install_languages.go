@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/sleuth-io/sx/internal/assets"
+	"github.com/sleuth-io/sx/internal/gitutil"
+	"github.com/sleuth-io/sx/internal/languages"
+	"github.com/sleuth-io/sx/internal/lockfile"
+	"github.com/sleuth-io/sx/internal/logger"
+)
+
+// autoScopeByLanguage applies auto-scope = "languages": such assets are only
+// installed in repositories using one of their supported-languages, and global
+// ones are installed into the repository rather than for every repo. Outside a
+// repository they're left out. Assets that don't declare languages are kept as is.
+func autoScopeByLanguage(ctx context.Context, candidates []*lockfile.Asset, fetcher *assets.AssetFetcher, gitContext *gitutil.GitContext) []*lockfile.Asset {
+	log := logger.Get()
+
+	// Detected once, and only if an asset needs it
+	var detected []string
+	var detectErr error
+	detectedOnce := false
+
+	var result []*lockfile.Asset
+	for _, asset := range candidates {
+		if !asset.AutoScopesByLanguage() {
+			result = append(result, asset)
+			continue
+		}
+
+		// The supported languages are in the asset's metadata; fetched assets
+		// are cached, so this only downloads once per version
+		_, meta, err := fetcher.FetchAsset(ctx, asset)
+		if err != nil {
+			log.Warn("failed to read metadata for auto-scope, keeping asset", "name", asset.Name, "error", err)
+			result = append(result, asset)
+			continue
+		}
+		if meta.Skill == nil || len(meta.Skill.SupportedLanguages) == 0 {
+			result = append(result, asset)
+			continue
+		}
+
+		if !gitContext.IsRepo {
+			log.Debug("auto-scoped asset skipped outside a repository", "name", asset.Name)
+			continue
+		}
+		if !detectedOnce {
+			detected, detectErr = languages.Detect(ctx, gitContext.RepoRoot)
+			detectedOnce = true
+			if detectErr != nil {
+				log.Warn("failed to detect repository languages", "repo", gitContext.RepoRoot, "error", detectErr)
+			} else {
+				log.Debug("detected repository languages", "repo", gitContext.RepoRoot, "languages", detected)
+			}
+		}
+		if detectErr != nil {
+			result = append(result, asset)
+			continue
+		}
+		if !languages.Matches(meta.Skill.SupportedLanguages, detected) {
+			log.Debug("asset not for this repository's languages", "name", asset.Name, "supported", meta.Skill.SupportedLanguages, "detected", detected)
+			continue
+		}
+
+		if asset.IsGlobal() && gitContext.RepoURL != "" {
+			scoped := *asset
+			scoped.Scopes = []lockfile.Scope{{Repo: gitContext.RepoURL}}
+			asset = &scoped
+		}
+		result = append(result, asset)
+	}
+
+	return result
+}
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func TestInstallAutoScopesByLanguage(t *testing.T) {
+	env := NewTestEnv(t)
+	vaultDir := env.SetupPathVault()
+
+	skills := map[string]string{
+		"python-tips": `["python"]`,
+		"rust-tips":   `["rust"]`,
+	}
+	for name, languages := range skills {
+		assetDir := filepath.Join(vaultDir, "assets", name, "1.0.0")
+		env.WriteFile(filepath.Join(assetDir, "metadata.toml"), `[asset]
+name = "`+name+`"
+version = "1.0.0"
+type = "skill"
+
+[skill]
+prompt-file = "SKILL.md"
+supported-languages = `+languages+`
+`)
+		env.WriteFile(filepath.Join(assetDir, "SKILL.md"), "# "+name)
+	}
+	env.WriteLockFile(vaultDir, `lock-version = "1"
+version = "1.0.0"
+created-by = "test"
+
+[[assets]]
+name = "python-tips"
+version = "1.0.0"
+type = "skill"
+auto-scope = "languages"
+
+[assets.source-path]
+path = "assets/python-tips/1.0.0"
+
+[[assets]]
+name = "rust-tips"
+version = "1.0.0"
+type = "skill"
+auto-scope = "languages"
+
+[assets.source-path]
+path = "assets/rust-tips/1.0.0"
+`)
+	env.MkdirAll(env.GlobalClaudeDir())
+
+	repoDir := env.SetupGitRepo("service", "https://github.com/example/service")
+	env.WriteFile(filepath.Join(repoDir, "pyproject.toml"), "[project]\nname = \"service\"\n")
+	env.runGit(repoDir, "add", ".")
+	env.runGit(repoDir, "commit", "-m", "python")
+	env.Chdir(repoDir)
+
+	installCmd := NewInstallCommand()
+	installCmd.SetOut(&bytes.Buffer{})
+	installCmd.SetErr(&bytes.Buffer{})
+	installCmd.SetArgs([]string{})
+	if err := installCmd.Execute(); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	// The Python skill is installed into the repository instead of globally, and
+	// the Rust skill is left out
+	env.AssertFileExists(filepath.Join(repoDir, ".claude", "skills", "python-tips", "SKILL.md"))
+	env.AssertFileNotExists(filepath.Join(env.GlobalClaudeDir(), "skills", "python-tips"))
+	env.AssertFileNotExists(filepath.Join(repoDir, ".claude", "skills", "rust-tips"))
+	env.AssertFileNotExists(filepath.Join(env.GlobalClaudeDir(), "skills", "rust-tips"))
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"github.com/sleuth-io/sx/internal/assets"
+	"github.com/sleuth-io/sx/internal/gitutil"
+	"github.com/sleuth-io/sx/internal/lockfile"
+	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/overlay"
+)
+
+// loadOverlays reads the repository's overlays for the assets being installed.
+// Global assets are shared by every repository, so overlays only apply to
+// repo- and path-scoped assets.
+func loadOverlays(sortedAssets []*lockfile.Asset, gitContext *gitutil.GitContext, out *outputHelper) map[string]*overlay.Overlay {
+	overlays := make(map[string]*overlay.Overlay)
+	if !gitContext.IsRepo {
+		return overlays
+	}
+
+	log := logger.Get()
+	for _, art := range sortedAssets {
+		o, err := overlay.Load(gitContext.RepoRoot, art.Name)
+		if err != nil {
+			out.printfErr("Warning: ignoring overlay for %s: %v\n", art.Name, err)
+			log.Warn("failed to load overlay", "name", art.Name, "error", err)
+			continue
+		}
+		if o == nil {
+			continue
+		}
+		if art.IsGlobal() {
+			out.printfErr("Warning: ignoring overlay for %s: global assets can't be overlaid per repository\n", art.Name)
+			continue
+		}
+		overlays[art.Name] = o
+	}
+	return overlays
+}
+
+// overlayHash returns the hash of an asset's overlay, or "" when it has none
+func overlayHash(overlays map[string]*overlay.Overlay, name string) string {
+	if o := overlays[name]; o != nil {
+		return o.Hash
+	}
+	return ""
+}
+
+// applyOverlays patches downloaded assets with their overlays. An overlay that
+// doesn't apply is dropped (and so not recorded in the tracker), leaving the
+// vault version to be installed.
+func applyOverlays(downloads []*assets.AssetWithMetadata, overlays map[string]*overlay.Overlay, out *outputHelper) {
+	log := logger.Get()
+	for _, download := range downloads {
+		o := overlays[download.Asset.Name]
+		if o == nil {
+			continue
+		}
+
+		zipData, err := o.Apply(download.ZipData)
+		if err != nil {
+			out.printfErr("Warning: ignoring overlay for %s: %v\n", download.Asset.Name, err)
+			log.Warn("failed to apply overlay", "name", download.Asset.Name, "error", err)
+			delete(overlays, download.Asset.Name)
+			continue
+		}
+		download.ZipData = zipData
+		log.Info("overlay applied", "name", download.Asset.Name, "overlay", o.Path, "files", o.Files())
+	}
+}
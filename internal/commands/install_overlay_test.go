@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/assets"
+)
+
+func TestInstallAppliesRepoOverlay(t *testing.T) {
+	env := NewTestEnv(t)
+
+	vaultDir := env.SetupPathVault()
+	env.AddSkillToVault(vaultDir, "code-review", "1.0.0")
+	env.WriteLockFile(vaultDir, `lock-version = "1"
+version = "1.0.0"
+created-by = "test"
+
+[[assets]]
+name = "code-review"
+version = "1.0.0"
+type = "skill"
+
+[assets.source-path]
+path = "assets/code-review/1.0.0"
+
+[[assets.scopes]]
+repo = "https://github.com/testorg/testrepo"
+`)
+
+	projectDir := env.SetupGitRepo("project", "https://github.com/testorg/testrepo")
+	env.Chdir(projectDir)
+	overlayDir := filepath.Join(projectDir, ".sx", "overlays", "code-review")
+	env.WriteFile(filepath.Join(overlayDir, "SKILL.md.append"), "Follow CONTRIBUTING.md.\n")
+	env.WriteFile(filepath.Join(overlayDir, "README.md"), "# Our review")
+
+	install := func() {
+		t.Helper()
+		installCmd := NewInstallCommand()
+		installCmd.SetOut(&bytes.Buffer{})
+		installCmd.SetArgs([]string{})
+		if err := installCmd.Execute(); err != nil {
+			t.Fatalf("install failed: %v", err)
+		}
+	}
+	readSkill := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(projectDir, ".claude", "skills", "code-review", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	trackedHash := func() string {
+		t.Helper()
+		tracker, err := assets.LoadTracker()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tracker.Assets) != 1 {
+			t.Fatalf("tracker = %+v, want one asset", tracker.Assets)
+		}
+		return tracker.Assets[0].OverlayHash
+	}
+
+	install()
+	if got := readSkill("SKILL.md"); got != "You are code-review\nFollow CONTRIBUTING.md.\n" {
+		t.Errorf("SKILL.md = %q", got)
+	}
+	if got := readSkill("README.md"); got != "# Our review" {
+		t.Errorf("README.md = %q", got)
+	}
+	firstHash := trackedHash()
+	if firstHash == "" {
+		t.Fatal("tracker didn't record the overlay hash")
+	}
+
+	// Changing the overlay reinstalls without a new vault version
+	env.WriteFile(filepath.Join(overlayDir, "SKILL.md.append"), "Be brief.\n")
+	install()
+	if got := readSkill("SKILL.md"); !strings.HasSuffix(got, "Be brief.\n") {
+		t.Errorf("SKILL.md = %q, want the updated overlay", got)
+	}
+	if trackedHash() == firstHash {
+		t.Error("overlay hash wasn't updated")
+	}
+
+	// Removing the overlay restores the vault version
+	if err := os.RemoveAll(filepath.Join(projectDir, ".sx")); err != nil {
+		t.Fatal(err)
+	}
+	install()
+	if got := readSkill("SKILL.md"); got != "You are code-review" {
+		t.Errorf("SKILL.md = %q, want the vault version", got)
+	}
+	if hash := trackedHash(); hash != "" {
+		t.Errorf("overlay hash = %q, want none", hash)
+	}
+}
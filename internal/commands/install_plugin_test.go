@@ -0,0 +1,170 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallAsClaudePlugin(t *testing.T) {
+	env := NewTestEnv(t)
+	vaultDir := env.SetupPathVault()
+	env.AddSkillToVault(vaultDir, "go-testing", "1.0.0")
+
+	lockFile := `lock-version = "1"
+version = "1.0.0"
+created-by = "test"
+`
+	env.WriteLockFile(vaultDir, lockFile+`
+[[assets]]
+name = "go-testing"
+version = "1.0.0"
+type = "skill"
+
+[assets.source-path]
+path = "assets/go-testing/1.0.0"
+`)
+
+	install := func(args ...string) {
+		t.Helper()
+		installCmd := NewInstallCommand()
+		installCmd.SetOut(&bytes.Buffer{})
+		installCmd.SetErr(&bytes.Buffer{})
+		installCmd.SetArgs(args)
+		if err := installCmd.Execute(); err != nil {
+			t.Fatalf("install failed: %v", err)
+		}
+	}
+
+	// Installed into ~/.claude first, then moved into the plugin on repair
+	install()
+	legacySkill := filepath.Join(env.GlobalClaudeDir(), "skills", "go-testing")
+	env.AssertFileExists(legacySkill)
+
+	configDir := filepath.Join(env.HomeDir, ".config", "sx")
+	env.WriteFile(filepath.Join(configDir, "config.json"),
+		`{"type":"path","repositoryUrl":"file://`+vaultDir+`","claudePlugin":true}`)
+	install("--repair")
+
+	marketplace := filepath.Join(configDir, "claude-marketplace")
+	plugin := filepath.Join(marketplace, "plugins", "sx-global")
+	env.AssertFileExists(filepath.Join(plugin, "skills", "go-testing", "SKILL.md"))
+	env.AssertFileNotExists(legacySkill)
+
+	var manifest struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	readJSON(t, filepath.Join(plugin, ".claude-plugin", "plugin.json"), &manifest)
+	if manifest.Name != "sx-global" || !strings.HasPrefix(manifest.Version, "0.0.0+") {
+		t.Errorf("unexpected plugin manifest: %+v", manifest)
+	}
+
+	var listing struct {
+		Name    string `json:"name"`
+		Plugins []struct {
+			Name   string `json:"name"`
+			Source string `json:"source"`
+		} `json:"plugins"`
+	}
+	readJSON(t, filepath.Join(marketplace, ".claude-plugin", "marketplace.json"), &listing)
+	if listing.Name != "sx" || len(listing.Plugins) != 1 || listing.Plugins[0].Source != "./plugins/sx-global" {
+		t.Errorf("unexpected marketplace: %+v", listing)
+	}
+
+	var settings struct {
+		EnabledPlugins         map[string]bool                   `json:"enabledPlugins"`
+		ExtraKnownMarketplaces map[string]map[string]interface{} `json:"extraKnownMarketplaces"`
+	}
+	settingsPath := filepath.Join(env.GlobalClaudeDir(), "settings.json")
+	readJSON(t, settingsPath, &settings)
+	if !settings.EnabledPlugins["sx-global@sx"] {
+		t.Errorf("plugin not enabled: %+v", settings.EnabledPlugins)
+	}
+	if _, ok := settings.ExtraKnownMarketplaces["sx"]; !ok {
+		t.Errorf("marketplace not registered: %+v", settings.ExtraKnownMarketplaces)
+	}
+
+	// Removing the last asset removes the plugin and its registration
+	env.WriteLockFile(vaultDir, lockFile)
+	install()
+	env.AssertFileNotExists(plugin)
+	settings.EnabledPlugins = nil
+	settings.ExtraKnownMarketplaces = nil
+	readJSON(t, settingsPath, &settings)
+	if len(settings.EnabledPlugins) != 0 || len(settings.ExtraKnownMarketplaces) != 0 {
+		t.Errorf("plugin still registered: %+v", settings)
+	}
+}
+
+func TestInstallClaudePluginMCPUsesPluginRoot(t *testing.T) {
+	env := NewTestEnv(t)
+	vaultDir := env.SetupPathVault()
+
+	assetDir := filepath.Join(vaultDir, "assets", "github", "1.0.0")
+	env.WriteFile(filepath.Join(assetDir, "metadata.toml"), `[asset]
+name = "github"
+version = "1.0.0"
+type = "mcp"
+
+[mcp]
+command = "bin/server"
+args = ["dist/index.js", "--stdio"]
+`)
+	env.WriteFile(filepath.Join(assetDir, "bin", "server"), "#!/bin/sh\nexec node dist/index.js \"$@\"\n")
+	env.WriteFile(filepath.Join(assetDir, "dist", "index.js"), "console.log('github')")
+	env.WriteLockFile(vaultDir, `lock-version = "1"
+version = "1.0.0"
+created-by = "test"
+
+[[assets]]
+name = "github"
+version = "1.0.0"
+type = "mcp"
+
+[assets.source-path]
+path = "assets/github/1.0.0"
+`)
+	configDir := filepath.Join(env.HomeDir, ".config", "sx")
+	env.WriteFile(filepath.Join(configDir, "config.json"),
+		`{"type":"path","repositoryUrl":"file://`+vaultDir+`","claudePlugin":true}`)
+
+	installCmd := NewInstallCommand()
+	installCmd.SetOut(&bytes.Buffer{})
+	installCmd.SetErr(&bytes.Buffer{})
+	if err := installCmd.Execute(); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	// Like hook scripts, server paths go through the plugin root since Claude Code copies plugins
+	plugin := filepath.Join(configDir, "claude-marketplace", "plugins", "sx-global")
+	var config struct {
+		MCPServers map[string]struct {
+			Command string   `json:"command"`
+			Args    []string `json:"args"`
+		} `json:"mcpServers"`
+	}
+	readJSON(t, filepath.Join(plugin, ".mcp.json"), &config)
+	server := config.MCPServers["github"]
+	if want := "${CLAUDE_PLUGIN_ROOT}/mcp-servers/github/bin/server"; server.Command != want {
+		t.Errorf("command = %q, want %q", server.Command, want)
+	}
+	wantArgs := []string{"${CLAUDE_PLUGIN_ROOT}/mcp-servers/github/dist/index.js", "--stdio"}
+	if strings.Join(server.Args, " ") != strings.Join(wantArgs, " ") {
+		t.Errorf("args = %v, want %v", server.Args, wantArgs)
+	}
+}
+
+func readJSON(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("failed to parse %s: %v", path, err)
+	}
+}
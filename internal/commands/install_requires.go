@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sleuth-io/sx/internal/assets"
+	"github.com/sleuth-io/sx/internal/lockfile"
+	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/prereq"
+	"github.com/sleuth-io/sx/internal/ui"
+)
+
+// requiresCheck records which downloaded assets are missing tools they require
+type requiresCheck struct {
+	// Requires maps asset names to the tools their downloaded version requires
+	Requires map[string][]string
+	// Missing maps asset names to their unmet requirements
+	Missing map[string][]string
+	// Skipped holds assets left out because their lock file entry says to skip
+	// them when requirements are missing
+	Skipped map[string]bool
+}
+
+// checkRequires checks the tools each downloaded asset requires. Assets set to
+// skip on missing requirements are dropped from downloads; the rest are
+// installed with a warning.
+func checkRequires(ctx context.Context, downloads []*assets.AssetWithMetadata) ([]*assets.AssetWithMetadata, *requiresCheck) {
+	check := &requiresCheck{
+		Requires: make(map[string][]string),
+		Missing:  make(map[string][]string),
+		Skipped:  make(map[string]bool),
+	}
+	log := logger.Get()
+
+	var kept []*assets.AssetWithMetadata
+	for _, download := range downloads {
+		name := download.Asset.Name
+		requires := download.Metadata.GetRequires()
+		check.Requires[name] = requires
+
+		missing := prereq.Check(ctx, requires)
+		if len(missing) == 0 {
+			kept = append(kept, download)
+			continue
+		}
+
+		check.Missing[name] = missing
+		if download.Asset.SkipsOnMissingRequires() {
+			log.Warn("skipping asset with missing prerequisites", "name", name, "missing", missing)
+			check.Skipped[name] = true
+			continue
+		}
+		log.Warn("installing asset with missing prerequisites", "name", name, "missing", missing)
+		kept = append(kept, download)
+	}
+
+	return kept, check
+}
+
+// skippedForRequires reports whether install leaves asset out because it's set to
+// skip when required tools are missing and some are. Assets whose metadata can't
+// be fetched are assumed to be installed.
+func skippedForRequires(ctx context.Context, asset *lockfile.Asset, fetcher *assets.AssetFetcher) bool {
+	if !asset.SkipsOnMissingRequires() {
+		return false
+	}
+	_, meta, err := fetcher.FetchAsset(ctx, asset)
+	if err != nil {
+		logger.Get().Warn("failed to read metadata for requires check", "name", asset.Name, "error", err)
+		return false
+	}
+	return len(prereq.Check(ctx, meta.GetRequires())) > 0
+}
+
+// names returns the assets with missing requirements, sorted
+func (c *requiresCheck) names() []string {
+	names := make([]string, 0, len(c.Missing))
+	for name := range c.Missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// report prints the assets with missing requirements in the install summary
+func (c *requiresCheck) report(styledOut *ui.Output) {
+	if len(c.Missing) == 0 {
+		return
+	}
+
+	styledOut.Warning(fmt.Sprintf("%d assets are missing prerequisites", len(c.Missing)))
+	for _, name := range c.names() {
+		action := "installed anyway"
+		if c.Skipped[name] {
+			action = "skipped"
+		}
+		styledOut.ListItem("!", fmt.Sprintf("%s (%s): %s", name, action, strings.Join(c.Missing[name], ", ")))
+	}
+}
+
+// notice is a short summary for the hook-mode system message
+func (c *requiresCheck) notice() string {
+	if len(c.Missing) == 0 {
+		return ""
+	}
+
+	var parts []string
+	for _, name := range c.names() {
+		part := fmt.Sprintf("%s needs %s", name, strings.Join(c.Missing[name], ", "))
+		if c.Skipped[name] {
+			part += " (skipped)"
+		}
+		parts = append(parts, part)
+	}
+	return "Missing prerequisites: " + strings.Join(parts, "; ")
+}
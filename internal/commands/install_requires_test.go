@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/assets"
+)
+
+func TestInstallChecksRequires(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	env := NewTestEnv(t)
+	vaultDir := env.SetupPathVault()
+
+	skills := map[string]string{
+		"git-helper": `["git"]`,
+		"deployer":   `["sx-no-such-tool"]`,
+		"migrator":   `["git>=999"]`,
+	}
+	for name, requires := range skills {
+		assetDir := filepath.Join(vaultDir, "assets", name, "1.0.0")
+		env.WriteFile(filepath.Join(assetDir, "metadata.toml"), `[asset]
+name = "`+name+`"
+version = "1.0.0"
+type = "skill"
+
+[skill]
+prompt-file = "SKILL.md"
+requires = `+requires+`
+`)
+		env.WriteFile(filepath.Join(assetDir, "SKILL.md"), "# "+name)
+	}
+	env.WriteLockFile(vaultDir, `lock-version = "1"
+version = "1.0.0"
+created-by = "test"
+
+[[assets]]
+name = "git-helper"
+version = "1.0.0"
+type = "skill"
+
+[assets.source-path]
+path = "assets/git-helper/1.0.0"
+
+[[assets]]
+name = "deployer"
+version = "1.0.0"
+type = "skill"
+missing-requires = "skip"
+
+[assets.source-path]
+path = "assets/deployer/1.0.0"
+
+[[assets]]
+name = "migrator"
+version = "1.0.0"
+type = "skill"
+
+[assets.source-path]
+path = "assets/migrator/1.0.0"
+`)
+	env.MkdirAll(env.GlobalClaudeDir())
+
+	var stdout bytes.Buffer
+	installCmd := NewInstallCommand()
+	installCmd.SetOut(&stdout)
+	installCmd.SetErr(&bytes.Buffer{})
+	installCmd.SetArgs([]string{"--hook-mode"})
+	if err := installCmd.Execute(); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	// Missing prerequisites warn by default and skip when the lock file says so
+	env.AssertFileExists(filepath.Join(env.GlobalClaudeDir(), "skills", "migrator", "SKILL.md"))
+	env.AssertFileNotExists(filepath.Join(env.GlobalClaudeDir(), "skills", "deployer"))
+
+	tracker, err := assets.LoadTracker()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracked := make(map[string]assets.InstalledAsset)
+	for _, installed := range tracker.Assets {
+		tracked[installed.Name] = installed
+	}
+	if _, ok := tracked["deployer"]; ok {
+		t.Error("skipped asset should be left untracked so it's retried")
+	}
+	if got := tracked["migrator"].Requires; len(got) != 1 || got[0] != "git>=999" {
+		t.Errorf("tracked requires = %v, want [git>=999]", got)
+	}
+
+	var response struct {
+		SystemMessage string `json:"systemMessage"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		t.Fatalf("hook output isn't JSON: %v\n%s", err, stdout.String())
+	}
+	for _, want := range []string{"deployer needs sx-no-such-tool not found on PATH (skipped)", "migrator needs git"} {
+		if !strings.Contains(response.SystemMessage, want) {
+			t.Errorf("system message %q doesn't mention %q", response.SystemMessage, want)
+		}
+	}
+	if strings.Contains(response.SystemMessage, "git-helper needs") {
+		t.Error("git-helper's requirements are met")
+	}
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sleuth-io/sx/internal/assets"
+	"github.com/sleuth-io/sx/internal/clients"
+	"github.com/sleuth-io/sx/internal/config"
+	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/mcpruntime"
+	"github.com/sleuth-io/sx/internal/ui"
+	"github.com/sleuth-io/sx/internal/ui/components"
+)
+
+// runtimeSetup records the outcome of setting up packaged MCP servers
+type runtimeSetup struct {
+	// Dirs maps asset names to the directory their server runs from
+	Dirs map[string]string
+	// Failed maps asset names to why setup didn't run or didn't succeed
+	Failed map[string]error
+}
+
+// approveSetupRequested reports whether --approve-setup was passed
+func approveSetupRequested(cmd *cobra.Command) bool {
+	approve, _ := cmd.Flags().GetBool("approve-setup")
+	return approve
+}
+
+// prepareMCPRuntimes runs the setup commands of packaged MCP servers and points
+// their metadata at the prepared directory. Setup runs code from the vault on
+// this machine, so each asset's contents must be approved first: with
+// --approve-setup, interactively, or by an earlier approval in the config.
+// Assets whose setup fails are dropped from downloads and reported in the result.
+func prepareMCPRuntimes(ctx context.Context, cmd *cobra.Command, downloads []*assets.AssetWithMetadata, cfg *config.Config, hookMode, force bool, out *outputHelper) ([]*assets.AssetWithMetadata, *runtimeSetup) {
+	setup := &runtimeSetup{Dirs: make(map[string]string), Failed: make(map[string]error)}
+	log := logger.Get()
+
+	var ready []*assets.AssetWithMetadata
+	for _, download := range downloads {
+		if !mcpruntime.NeedsSetup(download.Metadata) {
+			ready = append(ready, download)
+			continue
+		}
+
+		name := download.Asset.Name
+		key := mcpruntime.ApprovalKey(name, mcpruntime.Hash(download.ZipData))
+		if !cfg.IsSetupApproved(key) {
+			if !approveSetup(cmd, download, hookMode) {
+				setup.Failed[name] = fmt.Errorf("setup not approved; run 'sx install --approve-setup' to allow it")
+				continue
+			}
+			cfg.ApprovedSetup = append(cfg.ApprovedSetup, key)
+			if err := config.Save(cfg); err != nil {
+				log.Warn("failed to save setup approval", "name", name, "error", err)
+			}
+		}
+
+		out.printf("Setting up %s...\n", name)
+		dir, err := mcpruntime.Prepare(ctx, download.Metadata, download.ZipData, force)
+		if err != nil {
+			log.Error("mcp setup failed", "name", name, "error", err)
+			setup.Failed[name] = err
+			continue
+		}
+		log.Info("mcp setup ready", "name", name, "dir", dir)
+
+		// Install a copy of the metadata that runs the server from the prepared
+		// directory; the clients still register it under its usual name
+		meta := *download.Metadata
+		meta.MCP = mcpruntime.Resolve(download.Metadata.MCP, dir)
+		download.Metadata = &meta
+		setup.Dirs[name] = dir
+		ready = append(ready, download)
+	}
+
+	return ready, setup
+}
+
+// approveSetup asks whether an asset's setup commands may run
+func approveSetup(cmd *cobra.Command, download *assets.AssetWithMetadata, hookMode bool) bool {
+	if approveSetupRequested(cmd) {
+		return true
+	}
+	if hookMode || !ui.IsStdinTTY() {
+		return false
+	}
+
+	commands := download.Metadata.MCP.SetupCommands()
+	msg := fmt.Sprintf("%s runs setup commands on this machine:\n  %s\nAllow them?",
+		download.Asset.Name, strings.Join(commands, "\n  "))
+	approved, err := components.ConfirmWithIO(msg, false, cmd.InOrStdin(), cmd.OutOrStdout())
+	return err == nil && approved
+}
+
+// addSetupFailures reports failed setups as failed installs for every target client
+func addSetupFailures(results map[string]clients.InstallResponse, setup *runtimeSetup, targetClients []clients.Client) {
+	for name, err := range setup.Failed {
+		for _, client := range targetClients {
+			resp := results[client.ID()]
+			resp.Results = append(resp.Results, clients.AssetResult{
+				AssetName: name,
+				Status:    clients.StatusFailed,
+				Error:     fmt.Errorf("setup failed: %w", err),
+				Message:   fmt.Sprintf("Setup failed: %v", err),
+			})
+			results[client.ID()] = resp
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/assets"
+	"github.com/sleuth-io/sx/internal/mcpruntime"
+)
+
+func TestInstallRunsApprovedMCPSetup(t *testing.T) {
+	env := NewTestEnv(t)
+	vaultDir := env.SetupPathVault()
+
+	assetDir := filepath.Join(vaultDir, "assets", "github", "1.0.0")
+	env.WriteFile(filepath.Join(assetDir, "metadata.toml"), `[asset]
+name = "github"
+version = "1.0.0"
+type = "mcp"
+
+[mcp]
+command = "node"
+args = ["index.js"]
+setup = ["git --version"]
+`)
+	env.WriteFile(filepath.Join(assetDir, "index.js"), "console.log('github')")
+	env.WriteLockFile(vaultDir, `lock-version = "1"
+version = "1.0.0"
+created-by = "test"
+
+[[assets]]
+name = "github"
+version = "1.0.0"
+type = "mcp"
+
+[assets.source-path]
+path = "assets/github/1.0.0"
+`)
+	env.MkdirAll(env.GlobalClaudeDir())
+
+	install := func(args ...string) {
+		t.Helper()
+		installCmd := NewInstallCommand()
+		installCmd.SetOut(&bytes.Buffer{})
+		installCmd.SetErr(&bytes.Buffer{})
+		installCmd.SetArgs(args)
+		_ = installCmd.Execute()
+	}
+	trackedRuntime := func() (string, bool) {
+		t.Helper()
+		tracker, err := assets.LoadTracker()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, installed := range tracker.Assets {
+			if installed.Name == "github" {
+				return installed.RuntimeDir, true
+			}
+		}
+		return "", false
+	}
+
+	// Setup runs code from the vault, so it needs approval first
+	install()
+	if _, tracked := trackedRuntime(); tracked {
+		t.Fatal("asset with unapproved setup was recorded as installed")
+	}
+
+	install("--approve-setup")
+	runtimeDir, tracked := trackedRuntime()
+	if !tracked || !mcpruntime.Ready(runtimeDir) {
+		t.Fatalf("runtime dir = %q, want a completed setup", runtimeDir)
+	}
+
+	data, err := os.ReadFile(filepath.Join(env.GlobalClaudeDir(), ".mcp.json"))
+	if err != nil {
+		t.Fatalf("MCP config not written: %v", err)
+	}
+	var config struct {
+		MCPServers map[string]struct {
+			Args []string `json:"args"`
+		} `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	args := config.MCPServers["github"].Args
+	if len(args) != 1 || args[0] != filepath.Join(runtimeDir, "index.js") {
+		t.Errorf("args = %v, want the server to run from %s", args, runtimeDir)
+	}
+
+	// The approval is remembered for the same contents, and repair redoes a
+	// setup whose directory went missing
+	if err := os.RemoveAll(runtimeDir); err != nil {
+		t.Fatal(err)
+	}
+	install("--repair")
+	if !mcpruntime.Ready(runtimeDir) {
+		t.Error("repair didn't set up the server again")
+	}
+	if !strings.Contains(runtimeDir, "mcp-runtimes") {
+		t.Errorf("runtime dir = %s, want it in the cache", runtimeDir)
+	}
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -142,7 +143,7 @@ prompt-file = "SKILL.md"
 	// Verify rules file was generated in the local working directory
 	// (Cursor doesn't load global rules, so we create them locally)
 	localCursorDir := filepath.Join(workingDir, ".cursor")
-	rulesFile := filepath.Join(localCursorDir, "rules", "skills.md")
+	rulesFile := filepath.Join(localCursorDir, "rules", "skill-test-skill.mdc")
 	if _, err := os.Stat(rulesFile); os.IsNotExist(err) {
 		t.Errorf("Rules file was not generated at: %s", rulesFile)
 	} else {
@@ -152,14 +153,14 @@ prompt-file = "SKILL.md"
 			t.Errorf("Failed to read rules file: %v", err)
 		} else {
 			rulesStr := string(rulesContent)
-			if !strings.Contains(rulesStr, "test-skill") {
-				t.Errorf("Rules file doesn't contain test-skill")
+			if !strings.Contains(rulesStr, "description: A test skill") {
+				t.Errorf("Rules file doesn't describe test-skill")
 			}
-			if !strings.Contains(rulesStr, "read_skill") {
-				t.Errorf("Rules file doesn't mention read_skill MCP tool")
+			if !strings.Contains(rulesStr, "helpful assistant for testing") {
+				t.Errorf("Rules file doesn't inline the small skill")
 			}
-			if !strings.Contains(rulesStr, "alwaysApply: true") {
-				t.Errorf("Rules file missing frontmatter")
+			if !strings.Contains(rulesStr, "alwaysApply: false") {
+				t.Errorf("Rules file should only apply on demand")
 			}
 		}
 	}
@@ -211,7 +212,7 @@ prompt-file = "SKILL.md"
 
 	// Verify local rules file was created in the NEW directory
 	newLocalCursorDir := filepath.Join(newWorkingDir, ".cursor")
-	newRulesFile := filepath.Join(newLocalCursorDir, "rules", "skills.md")
+	newRulesFile := filepath.Join(newLocalCursorDir, "rules", "skill-test-skill.mdc")
 	if _, err := os.Stat(newRulesFile); os.IsNotExist(err) {
 		t.Errorf("Rules file was not generated in new directory at: %s", newRulesFile)
 	} else {
@@ -221,10 +222,10 @@ prompt-file = "SKILL.md"
 			t.Errorf("Failed to read rules file in new directory: %v", err)
 		} else {
 			rulesStr := string(rulesContent)
-			if !strings.Contains(rulesStr, "test-skill") {
+			if !strings.Contains(rulesStr, "sx:skill test-skill") {
 				t.Errorf("Rules file in new directory doesn't contain global test-skill")
 			}
-			if !strings.Contains(rulesStr, "alwaysApply: true") {
+			if !strings.Contains(rulesStr, "alwaysApply: false") {
 				t.Errorf("Rules file in new directory missing frontmatter")
 			}
 			t.Log("✓ Local rules file created in new directory with global skills")
@@ -670,3 +671,142 @@ prompt-file = "SKILL.md"
 
 	t.Log("✓ Cursor auto-install deduplication test passed!")
 }
+
+// TestCursorAgentIntegration tests that agents are installed for Cursor as on-demand rules
+func TestCursorAgentIntegration(t *testing.T) {
+	env := NewTestEnv(t)
+	vaultDir := env.SetupPathVault()
+	cursorDir := env.MkdirAll(filepath.Join(env.HomeDir, ".cursor"))
+	workingDir := env.MkdirAll(filepath.Join(env.TempDir, "working"))
+	env.Chdir(workingDir)
+
+	agentDir := filepath.Join(vaultDir, "assets", "reviewer", "1.0.0")
+	env.WriteFile(filepath.Join(agentDir, "metadata.toml"), `[asset]
+name = "reviewer"
+version = "1.0.0"
+type = "agent"
+description = "Reviews code"
+
+[agent]
+prompt-file = "AGENT.md"
+triggers = ["reviewing a pull request", "checking code quality"]
+`)
+	env.WriteFile(filepath.Join(agentDir, "AGENT.md"), "---\nname: reviewer\n---\nYou review code.\n")
+
+	lockFile := `lock-version = "1"
+version = "1.0.0"
+created-by = "test"
+`
+	env.WriteLockFile(vaultDir, lockFile+`
+[[assets]]
+name = "reviewer"
+version = "1.0.0"
+type = "agent"
+
+[assets.source-path]
+path = "assets/reviewer/1.0.0"
+`)
+
+	install := func() {
+		t.Helper()
+		installCmd := NewInstallCommand()
+		installCmd.SetOut(&bytes.Buffer{})
+		installCmd.SetErr(&bytes.Buffer{})
+		installCmd.SetArgs([]string{})
+		if err := installCmd.Execute(); err != nil {
+			t.Fatalf("install failed: %v", err)
+		}
+	}
+	install()
+
+	agentFile := filepath.Join(cursorDir, "agents", "reviewer.md")
+	env.AssertFileExists(agentFile)
+
+	// Cursor doesn't load global rules, so the rule is generated locally
+	rulePath := filepath.Join(workingDir, ".cursor", "rules", "agent-reviewer.mdc")
+	rule, err := os.ReadFile(rulePath)
+	if err != nil {
+		t.Fatalf("agent rule not generated: %v", err)
+	}
+	wantHeader := "---\ndescription: reviewing a pull request; checking code quality\nalwaysApply: false\n---\n"
+	if !strings.HasPrefix(string(rule), wantHeader) {
+		t.Errorf("rule should be on-demand with the triggers as its description, got:\n%s", rule)
+	}
+	if !strings.HasSuffix(string(rule), "\nYou review code.\n") || strings.Contains(string(rule), "name: reviewer") {
+		t.Errorf("rule should contain the agent prompt without its frontmatter, got:\n%s", rule)
+	}
+
+	// Removing the agent from the lock file removes it and its rule
+	env.WriteLockFile(vaultDir, lockFile)
+	install()
+	env.AssertFileNotExists(agentFile)
+	env.AssertFileNotExists(rulePath)
+}
+
+// TestCursorSkillRules tests that each skill gets its own on-demand rule and
+// that the legacy skills.md rule is cleaned up
+func TestCursorSkillRules(t *testing.T) {
+	env := NewTestEnv(t)
+	vaultDir := env.SetupPathVault()
+	env.MkdirAll(filepath.Join(env.HomeDir, ".cursor"))
+	workingDir := env.MkdirAll(filepath.Join(env.TempDir, "working"))
+	env.Chdir(workingDir)
+
+	rulesDir := filepath.Join(workingDir, ".cursor", "rules")
+	legacyRule := filepath.Join(rulesDir, "skills.md")
+	env.WriteFile(legacyRule, "---\nalwaysApply: true\n---\n\n<!-- AUTO-GENERATED by sx - Do not edit manually -->\n")
+	userRule := filepath.Join(rulesDir, "style.mdc")
+	env.WriteFile(userRule, "---\nalwaysApply: true\n---\nUse tabs.\n")
+
+	skillDir := filepath.Join(vaultDir, "assets", "go-testing", "1.0.0")
+	env.WriteFile(filepath.Join(skillDir, "metadata.toml"), `[asset]
+name = "go-testing"
+version = "1.0.0"
+type = "skill"
+description = "Writes Go tests."
+
+[skill]
+prompt-file = "SKILL.md"
+triggers = ["adding tests", "fixing a failing test"]
+globs = ["**/*_test.go"]
+`)
+	env.WriteFile(filepath.Join(skillDir, "SKILL.md"), "Follow @reference/table-tests.md for layout.\n")
+	env.WriteFile(filepath.Join(skillDir, "reference", "table-tests.md"), "Use table-driven tests.\n")
+
+	env.WriteLockFile(vaultDir, `lock-version = "1"
+version = "1.0.0"
+created-by = "test"
+
+[[assets]]
+name = "go-testing"
+version = "1.0.0"
+type = "skill"
+
+[assets.source-path]
+path = "assets/go-testing/1.0.0"
+`)
+
+	installCmd := NewInstallCommand()
+	installCmd.SetOut(&bytes.Buffer{})
+	installCmd.SetErr(&bytes.Buffer{})
+	installCmd.SetArgs([]string{})
+	if err := installCmd.Execute(); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+
+	rule, err := os.ReadFile(filepath.Join(rulesDir, "skill-go-testing.mdc"))
+	if err != nil {
+		t.Fatalf("skill rule not generated: %v", err)
+	}
+	wantHeader := "---\ndescription: 'Writes Go tests. Use when: adding tests; fixing a failing test.'\nglobs: '**/*_test.go'\nalwaysApply: false\n---\n"
+	if !strings.HasPrefix(string(rule), wantHeader) {
+		t.Errorf("rule frontmatter =\n%s\nwant prefix:\n%s", rule, wantHeader)
+	}
+	// Skills with file references are loaded through the MCP tool so they resolve
+	if !strings.Contains(string(rule), `read_skill(name: "go-testing")`) {
+		t.Errorf("rule should point to read_skill, got:\n%s", rule)
+	}
+
+	env.AssertFileNotExists(legacyRule)
+	env.AssertFileExists(userRule)
+}
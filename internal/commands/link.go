@@ -0,0 +1,417 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/assets"
+	"github.com/sleuth-io/sx/internal/clients"
+	"github.com/sleuth-io/sx/internal/config"
+	"github.com/sleuth-io/sx/internal/lockfile"
+	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/metadata"
+	"github.com/sleuth-io/sx/internal/ui"
+	"github.com/sleuth-io/sx/internal/utils"
+)
+
+// NewLinkCommand creates the link command
+func NewLinkCommand() *cobra.Command {
+	var noWatch bool
+	var interval time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "link <dir>",
+		Short: "Install a local asset directory for live development",
+		Long: `Install an asset's working directory into the detected clients without publishing
+a version. Where a client reads the asset from its own directory or file, sx
+installs a symlink so edits show up immediately. Clients that can't follow a
+symlink, or that install a rendered prompt (e.g. with frontmatter from
+metadata.toml), get a copy, and sx keeps running to re-copy it whenever the
+directory changes (disable with --no-watch).
+
+Linked assets are skipped by 'sx install' until 'sx unlink <name>' restores the
+vault version.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLink(cmd, args[0], !noWatch, interval)
+		},
+	}
+
+	cmd.Flags().BoolVar(&noWatch, "no-watch", false, "Don't watch for changes to re-copy the asset")
+	cmd.Flags().DurationVar(&interval, "interval", time.Second, "How often to check the directory for changes")
+
+	return cmd
+}
+
+// NewUnlinkCommand creates the unlink command
+func NewUnlinkCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unlink <name>",
+		Short: "Remove a linked asset and restore the vault version",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUnlink(cmd, args[0])
+		},
+	}
+
+	return cmd
+}
+
+// linkMode is how an asset was linked into a client
+type linkMode string
+
+const (
+	linkSymlink linkMode = "symlink"
+	linkCopy    linkMode = "copy"
+)
+
+func runLink(cmd *cobra.Command, dir string, watch bool, interval time.Duration) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	out := newOutputHelper(cmd)
+	styledOut := ui.NewOutput(cmd.OutOrStdout(), cmd.ErrOrStderr())
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve directory: %w", err)
+	}
+	meta, err := readLinkMetadata(dir)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = &config.Config{}
+	}
+	var targetClients []clients.Client
+	for _, client := range filterClientsByConfig(cfg, clients.Global().DetectInstalled()) {
+		if client.SupportsAssetType(meta.Asset.Type) {
+			targetClients = append(targetClients, client)
+		}
+	}
+	if len(targetClients) == 0 {
+		return fmt.Errorf("no detected client supports %s assets", meta.Asset.Type.Label)
+	}
+
+	installScope := &clients.InstallScope{Type: clients.ScopeGlobal}
+	modes, err := linkAsset(ctx, dir, meta, installScope, targetClients)
+	if err != nil {
+		return err
+	}
+	ensureAssetSupport(ctx, targetClients, installScope, out)
+
+	var linkedClientIDs []string
+	var copyClients []clients.Client
+	for _, client := range targetClients {
+		mode, ok := modes[client.ID()]
+		if !ok {
+			continue
+		}
+		linkedClientIDs = append(linkedClientIDs, client.ID())
+		if mode == linkCopy {
+			copyClients = append(copyClients, client)
+		}
+		styledOut.SuccessItem(fmt.Sprintf("%s → %s (%s)", meta.Asset.Name, client.DisplayName(), mode))
+	}
+	if len(linkedClientIDs) == 0 {
+		return fmt.Errorf("failed to link %s into any client", meta.Asset.Name)
+	}
+
+	if err := trackLinkedAsset(meta, dir, linkedClientIDs); err != nil {
+		return err
+	}
+
+	styledOut.Newline()
+	styledOut.Success(fmt.Sprintf("Linked %s from %s", meta.Asset.Name, dir))
+	styledOut.Muted(fmt.Sprintf("Run 'sx unlink %s' to restore the vault version.", meta.Asset.Name))
+
+	if len(copyClients) == 0 || !watch {
+		return nil
+	}
+
+	styledOut.Newline()
+	styledOut.Info(fmt.Sprintf("Watching %s for changes (Ctrl+C to stop)...", dir))
+	return watchDir(ctx, dir, interval, func() {
+		meta, err := readLinkMetadata(dir)
+		if err != nil {
+			styledOut.Warning(err.Error())
+			return
+		}
+		modes, err := linkAsset(ctx, dir, meta, installScope, copyClients)
+		if err != nil || len(modes) < len(copyClients) {
+			styledOut.Warning(fmt.Sprintf("Failed to update %s; will retry on the next change", meta.Asset.Name))
+			return
+		}
+		ensureAssetSupport(ctx, copyClients, installScope, out)
+		if err := trackLinkedAsset(meta, dir, linkedClientIDs); err != nil {
+			styledOut.Warning(err.Error())
+		}
+		out.printf("  ↻ %s updated at %s\n", meta.Asset.Name, time.Now().Format("15:04:05"))
+	})
+}
+
+func runUnlink(cmd *cobra.Command, name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	out := newOutputHelper(cmd)
+	styledOut := ui.NewOutput(cmd.OutOrStdout(), cmd.ErrOrStderr())
+
+	tracker, err := assets.LoadTracker()
+	if err != nil {
+		return err
+	}
+
+	var linked []assets.InstalledAsset
+	for _, installed := range tracker.Assets {
+		if installed.Name == name && installed.IsLinked() {
+			linked = append(linked, installed)
+		}
+	}
+	if len(linked) == 0 {
+		return fmt.Errorf("%s is not linked", name)
+	}
+
+	installScope := &clients.InstallScope{Type: clients.ScopeGlobal}
+	var touchedClients []clients.Client
+	for _, installed := range linked {
+		req := clients.UninstallRequest{
+			Assets: []asset.Asset{{Name: installed.Name, Version: installed.Version, Type: asset.FromString(installed.Type)}},
+			Scope:  installScope,
+		}
+		for _, clientID := range installed.Clients {
+			client, err := clients.Global().Get(clientID)
+			if err != nil {
+				continue
+			}
+			if _, err := client.UninstallAssets(ctx, req); err != nil {
+				styledOut.Warning(fmt.Sprintf("Failed to remove %s from %s: %v", name, client.DisplayName(), err))
+			}
+			touchedClients = append(touchedClients, client)
+		}
+		tracker.RemoveAsset(installed.Key())
+	}
+
+	if err := assets.SaveTracker(tracker); err != nil {
+		return err
+	}
+	ensureAssetSupport(ctx, touchedClients, installScope, out)
+
+	styledOut.Success("Unlinked " + name)
+
+	// Reinstall the vault version, if there is one
+	styledOut.Newline()
+	if err := runInstall(cmd, nil, false, "", false, false); err != nil {
+		logger.Get().Error("failed to restore vault version after unlink", "name", name, "error", err)
+		styledOut.Warning(fmt.Sprintf("Couldn't reinstall from the vault (%v); run 'sx install' to restore %s", err, name))
+	}
+
+	return nil
+}
+
+// readLinkMetadata reads and validates the metadata.toml of an asset working directory
+func readLinkMetadata(dir string) (*metadata.Metadata, error) {
+	metaPath := filepath.Join(dir, "metadata.toml")
+	if !utils.FileExists(metaPath) {
+		return nil, fmt.Errorf("no metadata.toml in %s (create an asset with 'sx new')", dir)
+	}
+	meta, err := metadata.ParseFile(metaPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := meta.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid metadata.toml: %w", err)
+	}
+	return meta, nil
+}
+
+// linkAsset installs dir into each client, then swaps in a symlink where the client
+// reads the asset from a single directory or file. Returns the mode per linked client.
+func linkAsset(ctx context.Context, dir string, meta *metadata.Metadata, installScope *clients.InstallScope, targetClients []clients.Client) (map[string]linkMode, error) {
+	zipData, err := utils.CreateZip(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &clients.AssetBundle{
+		Asset: &lockfile.Asset{
+			Name:       meta.Asset.Name,
+			Version:    meta.Asset.Version,
+			Type:       meta.Asset.Type,
+			SourcePath: &lockfile.SourcePath{Path: dir},
+		},
+		Metadata: meta,
+		ZipData:  zipData,
+	}
+
+	orchestrator := clients.NewOrchestrator(clients.Global())
+	results := orchestrator.InstallToClients(ctx, []*clients.AssetBundle{bundle}, installScope, clients.InstallOptions{}, targetClients)
+
+	log := logger.Get()
+	modes := make(map[string]linkMode)
+	for _, client := range targetClients {
+		installed := false
+		for _, result := range results[client.ID()].Results {
+			if result.AssetName == meta.Asset.Name && result.Status == clients.StatusSuccess {
+				installed = true
+			} else if result.Status == clients.StatusFailed {
+				log.Error("failed to link asset", "name", meta.Asset.Name, "client", client.ID(), "error", result.Error)
+			}
+		}
+		if !installed {
+			continue
+		}
+
+		modes[client.ID()] = linkCopy
+		if symlinkInstalled(ctx, client, meta, dir, installScope) {
+			modes[client.ID()] = linkSymlink
+		}
+	}
+
+	return modes, nil
+}
+
+// symlinkInstalled replaces the client's installed copy with a symlink into dir
+// Returns false when the client has no single path for the asset or symlinks fail.
+func symlinkInstalled(ctx context.Context, client clients.Client, meta *metadata.Metadata, dir string, installScope *clients.InstallScope) bool {
+	installPath, err := client.GetAssetPath(ctx, meta.Asset.Name, meta.Asset.Type, installScope)
+	if err != nil {
+		return false
+	}
+	info, err := os.Lstat(installPath)
+	if err != nil {
+		return false
+	}
+
+	// Directory assets link to the whole directory, single-file assets to their prompt
+	target := dir
+	if !info.IsDir() {
+		promptFile := linkPromptFile(meta)
+		if promptFile == "" {
+			return false
+		}
+		target = filepath.Join(dir, promptFile)
+
+		// Handlers may render the prompt (frontmatter from metadata, the dangerous
+		// command preamble), so only a verbatim copy can become a symlink
+		installed, err := os.ReadFile(installPath)
+		if err != nil {
+			return false
+		}
+		prompt, err := os.ReadFile(target)
+		if err != nil || !bytes.Equal(installed, prompt) {
+			logger.Get().Debug("installed prompt is rendered, keeping a copy", "path", installPath)
+			return false
+		}
+	}
+
+	tmpPath := installPath + ".sx-link"
+	_ = os.Remove(tmpPath)
+	if err := os.Symlink(target, tmpPath); err != nil {
+		logger.Get().Debug("symlinks unavailable, keeping a copy", "path", installPath, "error", err)
+		return false
+	}
+	if err := os.RemoveAll(installPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return false
+	}
+	if err := os.Rename(tmpPath, installPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return false
+	}
+	return true
+}
+
+func linkPromptFile(meta *metadata.Metadata) string {
+	switch {
+	case meta.Skill != nil:
+		return meta.Skill.PromptFile
+	case meta.Command != nil:
+		return meta.Command.PromptFile
+	case meta.Agent != nil:
+		return meta.Agent.PromptFile
+	}
+	return ""
+}
+
+// trackLinkedAsset records the link so install leaves the asset alone
+func trackLinkedAsset(meta *metadata.Metadata, dir string, clientIDs []string) error {
+	tracker, err := assets.LoadTracker()
+	if err != nil {
+		return err
+	}
+	tracker.UpsertAsset(assets.InstalledAsset{
+		Name:       meta.Asset.Name,
+		Version:    meta.Asset.Version,
+		Type:       meta.Asset.Type.Key,
+		Clients:    clientIDs,
+		LinkedFrom: dir,
+	})
+	return assets.SaveTracker(tracker)
+}
+
+// watchDir calls onChange whenever the files in dir change, until ctx is done
+func watchDir(ctx context.Context, dir string, interval time.Duration, onChange func()) error {
+	last, err := dirFingerprint(dir)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			current, err := dirFingerprint(dir)
+			if err != nil || current == last {
+				continue
+			}
+			last = current
+			onChange()
+		}
+	}
+}
+
+// dirFingerprint hashes the names, sizes, and modification times of the files in dir
+func dirFingerprint(dir string) (string, error) {
+	hash := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, _ := filepath.Rel(dir, path)
+		if utils.ShouldSkipPath(relPath) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00%d\n", relPath, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to scan %s: %w", dir, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
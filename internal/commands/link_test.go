@@ -0,0 +1,240 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/assets"
+)
+
+func TestLinkSymlinksWorkingDirectory(t *testing.T) {
+	env := NewTestEnv(t)
+	workingDir := env.MkdirAll(filepath.Join(env.TempDir, "working"))
+	env.Chdir(workingDir)
+
+	vaultDir := env.SetupPathVault()
+	env.AddSkillToVault(vaultDir, "code-review", "1.0.0")
+	env.WriteLockFile(vaultDir, `lock-version = "1"
+version = "1.0.0"
+created-by = "test"
+
+[[assets]]
+name = "code-review"
+version = "1.0.0"
+type = "skill"
+
+[assets.source-path]
+path = "assets/code-review/1.0.0"
+`)
+
+	devDir := filepath.Join(env.TempDir, "dev", "code-review")
+	newCmd := NewNewCommand()
+	newCmd.SetArgs([]string{"skill", "code-review", "--dir", devDir, "-d", "Review code"})
+	newCmd.SetOut(&bytes.Buffer{})
+	if err := newCmd.Execute(); err != nil {
+		t.Fatalf("new failed: %v", err)
+	}
+
+	linkCmd := NewLinkCommand()
+	linkCmd.SetArgs([]string{devDir, "--no-watch"})
+	linkCmd.SetOut(&bytes.Buffer{})
+	if err := linkCmd.Execute(); err != nil {
+		t.Fatalf("link failed: %v", err)
+	}
+
+	skillPath := filepath.Join(env.GlobalClaudeDir(), "skills", "code-review")
+	assertSymlinkTo(t, skillPath, devDir)
+
+	// Edits show up without reinstalling
+	env.WriteFile(filepath.Join(devDir, "SKILL.md"), "edited")
+	if data, _ := os.ReadFile(filepath.Join(skillPath, "SKILL.md")); string(data) != "edited" {
+		t.Errorf("installed SKILL.md = %q, want the working copy", data)
+	}
+
+	tracker, err := assets.LoadTracker()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tracker.Assets) != 1 || tracker.Assets[0].LinkedFrom != devDir {
+		t.Fatalf("tracker = %+v, want one asset linked from %s", tracker.Assets, devDir)
+	}
+
+	// Install leaves the link alone
+	installCmd := NewInstallCommand()
+	installCmd.SetOut(&bytes.Buffer{})
+	installCmd.SetArgs([]string{})
+	if err := installCmd.Execute(); err != nil {
+		t.Fatalf("install failed: %v", err)
+	}
+	assertSymlinkTo(t, skillPath, devDir)
+
+	// Unlink restores the vault version
+	unlinkCmd := NewUnlinkCommand()
+	unlinkCmd.SetArgs([]string{"code-review"})
+	unlinkCmd.SetOut(&bytes.Buffer{})
+	if err := unlinkCmd.Execute(); err != nil {
+		t.Fatalf("unlink failed: %v", err)
+	}
+
+	info, err := os.Lstat(skillPath)
+	if err != nil {
+		t.Fatalf("vault version not restored: %v", err)
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		t.Errorf("%s is still a symlink", skillPath)
+	}
+	if data, _ := os.ReadFile(filepath.Join(skillPath, "SKILL.md")); string(data) != "You are code-review" {
+		t.Errorf("installed SKILL.md = %q, want the vault version", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(devDir, "SKILL.md")); string(data) != "edited" {
+		t.Errorf("working copy was modified: %q", data)
+	}
+
+	tracker, _ = assets.LoadTracker()
+	for _, a := range tracker.Assets {
+		if a.IsLinked() {
+			t.Errorf("tracker still has a linked asset: %+v", a)
+		}
+	}
+}
+
+func TestLinkSingleFileAsset(t *testing.T) {
+	env := NewTestEnv(t)
+
+	devDir := filepath.Join(env.TempDir, "deploy")
+	newCmd := NewNewCommand()
+	newCmd.SetArgs([]string{"command", "deploy", "--dir", devDir})
+	newCmd.SetOut(&bytes.Buffer{})
+	if err := newCmd.Execute(); err != nil {
+		t.Fatalf("new failed: %v", err)
+	}
+
+	linkCmd := NewLinkCommand()
+	linkCmd.SetArgs([]string{devDir, "--no-watch"})
+	linkCmd.SetOut(&bytes.Buffer{})
+	if err := linkCmd.Execute(); err != nil {
+		t.Fatalf("link failed: %v", err)
+	}
+
+	assertSymlinkTo(t, filepath.Join(env.GlobalClaudeDir(), "commands", "deploy.md"), filepath.Join(devDir, "COMMAND.md"))
+}
+
+func TestLinkCopiesRenderedPrompt(t *testing.T) {
+	env := NewTestEnv(t)
+
+	devDir := filepath.Join(env.TempDir, "deploy")
+	newCmd := NewNewCommand()
+	newCmd.SetArgs([]string{"command", "deploy", "--dir", devDir})
+	newCmd.SetOut(&bytes.Buffer{})
+	if err := newCmd.Execute(); err != nil {
+		t.Fatalf("new failed: %v", err)
+	}
+	metaPath := filepath.Join(devDir, "metadata.toml")
+	metaData, err := os.ReadFile(metaPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.WriteFile(metaPath, strings.Replace(string(metaData), "[command]\n", "[command]\ndangerous = true\n", 1))
+
+	linkCmd := NewLinkCommand()
+	linkCmd.SetArgs([]string{devDir, "--no-watch"})
+	var out bytes.Buffer
+	linkCmd.SetOut(&out)
+	if err := linkCmd.Execute(); err != nil {
+		t.Fatalf("link failed: %v", err)
+	}
+
+	// A symlink to the raw prompt would drop the preamble install adds
+	commandPath := filepath.Join(env.GlobalClaudeDir(), "commands", "deploy.md")
+	if _, err := os.Readlink(commandPath); err == nil {
+		t.Fatalf("%s is a symlink, want a rendered copy", commandPath)
+	}
+	content, err := os.ReadFile(commandPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "sx:dangerous") {
+		t.Errorf("linked command is missing the dangerous preamble:\n%s", content)
+	}
+	if !strings.Contains(out.String(), "Claude Code (copy)") {
+		t.Errorf("output missing copy mode:\n%s", out.String())
+	}
+}
+
+func TestUnlinkNotLinked(t *testing.T) {
+	NewTestEnv(t)
+
+	cmd := NewUnlinkCommand()
+	cmd.SetArgs([]string{"nothing"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error for an asset that isn't linked")
+	}
+}
+
+func TestDirFingerprintChangesOnEdit(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	before, err := dirFingerprint(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("ab"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := dirFingerprint(dir)
+	if before == after {
+		t.Error("fingerprint didn't change after an edit")
+	}
+}
+
+func assertSymlinkTo(t *testing.T, path, target string) {
+	t.Helper()
+	got, err := os.Readlink(path)
+	if err != nil {
+		t.Fatalf("%s is not a symlink: %v", path, err)
+	}
+	if got != target {
+		t.Errorf("%s links to %s, want %s", path, got, target)
+	}
+}
+
+func TestLinkCopiesForClientsWithoutPaths(t *testing.T) {
+	env := NewTestEnv(t)
+	env.MkdirAll(filepath.Join(env.HomeDir, ".cursor"))
+	// Cursor writes its rules to the working directory
+	env.Chdir(env.MkdirAll(filepath.Join(env.TempDir, "working")))
+
+	devDir := filepath.Join(env.TempDir, "code-review")
+	newCmd := NewNewCommand()
+	newCmd.SetArgs([]string{"skill", "code-review", "--dir", devDir, "-d", "Review code"})
+	newCmd.SetOut(&bytes.Buffer{})
+	if err := newCmd.Execute(); err != nil {
+		t.Fatalf("new failed: %v", err)
+	}
+
+	linkCmd := NewLinkCommand()
+	linkCmd.SetArgs([]string{devDir, "--no-watch"})
+	var out bytes.Buffer
+	linkCmd.SetOut(&out)
+	if err := linkCmd.Execute(); err != nil {
+		t.Fatalf("link failed: %v", err)
+	}
+
+	for _, want := range []string{"Claude Code (symlink)", "Cursor (copy)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	tracker, _ := assets.LoadTracker()
+	if len(tracker.Assets) != 1 || len(tracker.Assets[0].Clients) != 2 {
+		t.Errorf("tracker = %+v, want both clients", tracker.Assets)
+	}
+}
@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sleuth-io/sx/internal/constants"
+	"github.com/sleuth-io/sx/internal/lockfile"
+	"github.com/sleuth-io/sx/internal/ui"
+)
+
+// lockMergeDriverName is the merge driver name registered in git config and .gitattributes
+const lockMergeDriverName = "sx-lock"
+
+// NewLockCommand creates the lock command
+func NewLockCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lock",
+		Short: "Lock file utilities for vault repositories",
+	}
+
+	cmd.AddCommand(newLockMergeDriverCommand())
+	cmd.AddCommand(newLockInstallMergeDriverCommand())
+
+	return cmd
+}
+
+func newLockMergeDriverCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "merge-driver <base> <ours> <theirs>",
+		Short: "Three-way merge sx.lock files (invoked by git)",
+		Long: `Merge sx.lock at the asset level instead of line by line. Git invokes this
+as "sx lock merge-driver %O %A %B" and expects the result in the ours file.
+
+Assets changed on one side take that side's entry. When both sides change the
+same asset, each field takes the side that changed it, scopes added by either side
+are kept, and scopes removed by either side are dropped. A field both sides changed
+differently keeps ours, and an asset modified on one side and removed on the other
+keeps the modified entry; both are reported as conflicts for review.
+
+Run 'sx lock install-merge-driver' in a vault repository to set it up.`,
+		Args:         cobra.ExactArgs(3),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLockMergeDriver(cmd, args[0], args[1], args[2])
+		},
+	}
+}
+
+func newLockInstallMergeDriverCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "install-merge-driver [repo-path]",
+		Short: "Register the sx.lock merge driver in a git repository",
+		Long: `Add "sx.lock merge=sx-lock" to .gitattributes and register the driver in the
+repository's git config. Commit .gitattributes so teammates get it too; each
+clone still needs this command run once, since git never shares driver config.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repoPath := "."
+			if len(args) > 0 {
+				repoPath = args[0]
+			}
+			return runLockInstallMergeDriver(cmd, repoPath)
+		},
+	}
+}
+
+func runLockMergeDriver(cmd *cobra.Command, basePath, oursPath, theirsPath string) error {
+	parse := func(path string) (*lockfile.LockFile, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		// Git passes an empty base when the file was added on both sides
+		if len(bytes.TrimSpace(data)) == 0 {
+			return &lockfile.LockFile{}, nil
+		}
+		return lockfile.Parse(data)
+	}
+
+	base, err := parse(basePath)
+	if err != nil {
+		return err
+	}
+	ours, err := parse(oursPath)
+	if err != nil {
+		return err
+	}
+	theirs, err := parse(theirsPath)
+	if err != nil {
+		return err
+	}
+
+	merged, conflicts := lockfile.Merge(base, ours, theirs)
+	if err := lockfile.Write(merged, oursPath); err != nil {
+		return err
+	}
+
+	// A non-zero exit leaves the file marked conflicted so someone reviews it
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%d asset(s) changed on both sides in conflicting ways: %s\nKept the modified entries and our value of conflicting fields; review %s and git add it",
+			len(conflicts), strings.Join(conflicts, ", "), constants.SkillLockFile)
+	}
+
+	return nil
+}
+
+func runLockInstallMergeDriver(cmd *cobra.Command, repoPath string) error {
+	out := ui.NewOutput(cmd.OutOrStdout(), cmd.ErrOrStderr())
+
+	// Register the driver in the repository's git config
+	for _, kv := range [][2]string{
+		{"merge." + lockMergeDriverName + ".name", "sx lock file merge"},
+		{"merge." + lockMergeDriverName + ".driver", "sx lock merge-driver %O %A %B"},
+	} {
+		gitCmd := exec.Command("git", "config", kv[0], kv[1])
+		gitCmd.Dir = repoPath
+		if output, err := gitCmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to configure merge driver: %w\nOutput: %s", err, string(output))
+		}
+	}
+
+	// Map sx.lock to the driver in .gitattributes
+	attributesPath := filepath.Join(repoPath, ".gitattributes")
+	existing, err := os.ReadFile(attributesPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read .gitattributes: %w", err)
+	}
+
+	rule := constants.SkillLockFile + " merge=" + lockMergeDriverName
+	for _, line := range strings.Split(string(existing), "\n") {
+		if strings.TrimSpace(line) == rule {
+			out.Success("Merge driver registered (.gitattributes already up to date)")
+			return nil
+		}
+	}
+
+	content := string(existing)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += rule + "\n"
+	if err := os.WriteFile(attributesPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write .gitattributes: %w", err)
+	}
+
+	out.Success("Merge driver registered")
+	out.Muted("Commit .gitattributes so merges of " + constants.SkillLockFile + " use it for everyone.")
+	return nil
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/lockfile"
+)
+
+func TestLockMergeDriver(t *testing.T) {
+	env := NewTestEnv(t)
+
+	header := "lock-version = \"1.0\"\nversion = \"1\"\ncreated-by = \"test\"\n"
+	entry := func(name string) string {
+		return "\n[[assets]]\nname = \"" + name + "\"\nversion = \"1.0.0\"\ntype = \"skill\"\n\n[assets.source-path]\npath = \"./assets/" + name + "/1.0.0\"\n"
+	}
+
+	base := filepath.Join(env.TempDir, "base.lock")
+	ours := filepath.Join(env.TempDir, "ours.lock")
+	theirs := filepath.Join(env.TempDir, "theirs.lock")
+	env.WriteFile(base, header+entry("shared"))
+	env.WriteFile(ours, header+entry("shared")+entry("ours"))
+	env.WriteFile(theirs, header+entry("shared")+entry("theirs"))
+
+	cmd := NewLockCommand()
+	cmd.SetArgs([]string{"merge-driver", base, ours, theirs})
+	cmd.SetOut(&bytes.Buffer{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("merge-driver failed: %v", err)
+	}
+
+	merged, err := lockfile.ParseFile(ours)
+	if err != nil {
+		t.Fatalf("merged lock file is not valid TOML: %v", err)
+	}
+	if len(merged.Assets) != 3 {
+		t.Errorf("expected 3 assets after merge, got %+v", merged.Assets)
+	}
+}
+
+func TestLockInstallMergeDriver(t *testing.T) {
+	env := NewTestEnv(t)
+	repoDir := env.SetupGitRepo("vault", "https://github.com/acme/vault.git")
+
+	for range 2 {
+		cmd := NewLockCommand()
+		cmd.SetArgs([]string{"install-merge-driver", repoDir})
+		cmd.SetOut(&bytes.Buffer{})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("install-merge-driver failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(repoDir, ".gitattributes"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(data), "sx.lock merge=sx-lock") != 1 {
+		t.Errorf("expected a single attributes rule, got:\n%s", data)
+	}
+
+	config, err := os.ReadFile(filepath.Join(repoDir, ".git", "config"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(config), "sx lock merge-driver %O %A %B") {
+		t.Errorf("merge driver not registered in git config:\n%s", config)
+	}
+}
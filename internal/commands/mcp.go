@@ -0,0 +1,266 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sleuth-io/sx/internal/config"
+	"github.com/sleuth-io/sx/internal/lockfile"
+	"github.com/sleuth-io/sx/internal/mcpcheck"
+	"github.com/sleuth-io/sx/internal/metadata"
+	"github.com/sleuth-io/sx/internal/ui"
+	"github.com/sleuth-io/sx/internal/ui/components"
+	"github.com/sleuth-io/sx/internal/utils"
+	vaultpkg "github.com/sleuth-io/sx/internal/vault"
+)
+
+// defaultMCPCheckTimeout bounds starting a server and listing what it offers
+const defaultMCPCheckTimeout = 30 * time.Second
+
+// NewMCPCommand creates the mcp command
+func NewMCPCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Work with MCP server assets",
+	}
+
+	cmd.AddCommand(newMCPCheckCommand())
+
+	return cmd
+}
+
+func newMCPCheckCommand() *cobra.Command {
+	var timeout time.Duration
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "check <name|path>",
+		Short: "Start an MCP server and list its tools",
+		Long: `Smoke-test an mcp or mcp-remote asset: start the server from its metadata.toml,
+perform the MCP initialize handshake, and list the tools, prompts and resources it
+offers. Catches a wrong command, missing args, or a crash on startup before a
+client runs into them.
+
+The argument is an asset directory or zip file, or the name of an asset in the
+vault. Servers with a url are checked over their http or sse transport.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMCPCheck(cmd, args[0], timeout, jsonOutput)
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", defaultMCPCheckTimeout, "How long to wait for the server")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func runMCPCheck(cmd *cobra.Command, target string, timeout time.Duration, jsonOutput bool) error {
+	if jsonOutput {
+		cmd.SilenceUsage = true
+	}
+
+	meta, dir, cleanup, err := loadMCPCheckTarget(context.Background(), target)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	var status *components.Status
+	if !jsonOutput {
+		status = components.NewStatus(cmd.OutOrStdout())
+		status.Start(fmt.Sprintf("Starting %s", meta.Asset.Name))
+	}
+
+	// The timeout covers the server alone, not fetching the asset from the vault
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	result, err := mcpcheck.Run(ctx, meta.MCP, dir)
+	if status != nil {
+		if err != nil {
+			status.Fail(fmt.Sprintf("%s failed to start", meta.Asset.Name))
+		} else {
+			status.Done("")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("MCP check failed for %s: %w", meta.Asset.Name, err)
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		newOutputHelper(cmd).printlnAlways(string(data))
+		return nil
+	}
+
+	printMCPCheckResult(cmd, meta, result)
+	return nil
+}
+
+// loadMCPCheckTarget reads an MCP asset from a directory, zip file or the vault
+// Returns the metadata and the directory holding the asset's files, plus a
+// cleanup function for any temporary extraction.
+func loadMCPCheckTarget(ctx context.Context, target string) (*metadata.Metadata, string, func(), error) {
+	noop := func() {}
+
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		dir, err := filepath.Abs(target)
+		if err != nil {
+			return nil, "", noop, fmt.Errorf("failed to resolve directory: %w", err)
+		}
+		meta, err := metadata.ParseFile(filepath.Join(dir, "metadata.toml"))
+		if err != nil {
+			return nil, "", noop, fmt.Errorf("failed to read metadata: %w", err)
+		}
+		if err := validateMCPCheckMetadata(meta); err != nil {
+			return nil, "", noop, err
+		}
+		return meta, dir, noop, nil
+	}
+
+	var zipData []byte
+	if _, err := os.Stat(target); err == nil {
+		zipData, err = os.ReadFile(target)
+		if err != nil {
+			return nil, "", noop, fmt.Errorf("failed to read %s: %w", target, err)
+		}
+	} else {
+		zipData, err = fetchVaultAsset(ctx, target)
+		if err != nil {
+			return nil, "", noop, err
+		}
+	}
+
+	metadataBytes, err := utils.ReadZipFile(zipData, "metadata.toml")
+	if err != nil {
+		return nil, "", noop, fmt.Errorf("failed to read metadata.toml: %w", err)
+	}
+	meta, err := metadata.Parse(metadataBytes)
+	if err != nil {
+		return nil, "", noop, fmt.Errorf("failed to parse metadata: %w", err)
+	}
+	if err := validateMCPCheckMetadata(meta); err != nil {
+		return nil, "", noop, err
+	}
+
+	dir, cleanup, err := extractForMCPCheck(zipData)
+	if err != nil {
+		return nil, "", noop, err
+	}
+	return meta, dir, cleanup, nil
+}
+
+// validateMCPCheckMetadata makes sure the asset describes an MCP server
+func validateMCPCheckMetadata(meta *metadata.Metadata) error {
+	if meta.MCP == nil {
+		return fmt.Errorf("%s is a %s, not an MCP server", meta.Asset.Name, meta.Asset.Type.Label)
+	}
+	if err := meta.Validate(); err != nil {
+		return fmt.Errorf("invalid metadata: %w", err)
+	}
+	return nil
+}
+
+// fetchVaultAsset downloads the locked version of an asset from the vault
+func fetchVaultAsset(ctx context.Context, name string) ([]byte, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w\nRun 'sx init' to configure", err)
+	}
+	vault, err := vaultpkg.NewFromConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vault: %w", err)
+	}
+
+	data, _, _, err := vault.GetLockFile(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch lock file: %w", err)
+	}
+	lockFile, err := lockfile.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse lock file: %w", err)
+	}
+
+	for i := range lockFile.Assets {
+		if lockFile.Assets[i].Name == name {
+			zipData, err := vault.GetAsset(ctx, &lockFile.Assets[i])
+			if err != nil {
+				return nil, fmt.Errorf("failed to download %s: %w", name, err)
+			}
+			return zipData, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s is not a file, directory, or asset in the vault", name)
+}
+
+// extractForMCPCheck extracts an asset zip into a temporary directory
+func extractForMCPCheck(zipData []byte) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "sx-mcp-check-")
+	if err != nil {
+		return "", func() {}, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	if err := utils.ExtractZip(zipData, dir); err != nil {
+		cleanup()
+		return "", func() {}, fmt.Errorf("failed to extract asset: %w", err)
+	}
+	return dir, cleanup, nil
+}
+
+// checkMCPAsset runs the MCP check against a packaged asset
+func checkMCPAsset(ctx context.Context, meta *metadata.Metadata, zipData []byte) (*mcpcheck.Result, error) {
+	dir, cleanup, err := extractForMCPCheck(zipData)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(ctx, defaultMCPCheckTimeout)
+	defer cancel()
+
+	return mcpcheck.Run(ctx, meta.MCP, dir)
+}
+
+func printMCPCheckResult(cmd *cobra.Command, meta *metadata.Metadata, result *mcpcheck.Result) {
+	styledOut := ui.NewOutput(cmd.OutOrStdout(), cmd.ErrOrStderr())
+
+	server := result.ServerName
+	if result.ServerVersion != "" {
+		server += " " + result.ServerVersion
+	}
+	styledOut.Success(fmt.Sprintf("%s started (%s)", meta.Asset.Name, server))
+
+	sections := []struct {
+		title string
+		items []mcpcheck.Item
+	}{
+		{"Tools", result.Tools},
+		{"Prompts", result.Prompts},
+		{"Resources", result.Resources},
+	}
+	for _, section := range sections {
+		if len(section.items) == 0 {
+			continue
+		}
+		styledOut.Newline()
+		styledOut.Bold(fmt.Sprintf("%s (%d)", section.title, len(section.items)))
+		for _, item := range section.items {
+			if item.Description != "" {
+				styledOut.ListItem("•", fmt.Sprintf("%s %s", item.Name, styledOut.MutedText("- "+item.Description)))
+			} else {
+				styledOut.ListItem("•", item.Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/sleuth-io/sx/internal/mcpcheck"
+	"github.com/sleuth-io/sx/internal/metadata"
+)
+
+func TestMCPCheckRemoteServer(t *testing.T) {
+	env := NewTestEnv(t)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "linear", Version: "2.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "list_issues", Description: "List issues"},
+		func(ctx context.Context, req *mcp.CallToolRequest, input struct{}) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{}, nil, nil
+		})
+	httpServer := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+	defer httpServer.Close()
+
+	dir := filepath.Join(env.TempDir, "linear")
+	env.WriteFile(filepath.Join(dir, "metadata.toml"), `[asset]
+name = "linear"
+version = "1.0.0"
+type = "mcp-remote"
+
+[mcp]
+url = "`+httpServer.URL+`"
+`)
+
+	cmd := NewMCPCommand()
+	cmd.SetArgs([]string{"check", dir, "--json"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("mcp check failed: %v", err)
+	}
+
+	var result mcpcheck.Result
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out.String())
+	}
+	if result.ServerName != "linear" || len(result.Tools) != 1 || result.Tools[0].Name != "list_issues" {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestMCPCheckFailsForBadCommand(t *testing.T) {
+	env := NewTestEnv(t)
+
+	dir := filepath.Join(env.TempDir, "broken")
+	env.WriteFile(filepath.Join(dir, "metadata.toml"), `[asset]
+name = "broken"
+version = "1.0.0"
+type = "mcp-remote"
+
+[mcp]
+command = "sx-no-such-command"
+args = ["serve"]
+`)
+
+	cmd := NewMCPCommand()
+	cmd.SetArgs([]string{"check", dir, "--timeout", "5s"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error for a server that can't start")
+	}
+}
+
+func TestMCPCheckRejectsNonMCPAsset(t *testing.T) {
+	env := NewTestEnv(t)
+	env.SetupPathVault()
+
+	dir := filepath.Join(env.TempDir, "code-review")
+	env.WriteFile(filepath.Join(dir, "metadata.toml"), `[asset]
+name = "code-review"
+version = "1.0.0"
+type = "skill"
+
+[skill]
+prompt-file = "SKILL.md"
+`)
+
+	cmd := NewMCPCommand()
+	cmd.SetArgs([]string{"check", dir})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error for a skill")
+	}
+}
+
+func TestAddCheckRecordsMCPTools(t *testing.T) {
+	env := NewTestEnv(t)
+	vaultDir := env.SetupPathVault()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "linear", Version: "2.0.0"}, nil)
+	for _, name := range []string{"list_issues", "create_issue"} {
+		mcp.AddTool(server, &mcp.Tool{Name: name},
+			func(ctx context.Context, req *mcp.CallToolRequest, input struct{}) (*mcp.CallToolResult, any, error) {
+				return &mcp.CallToolResult{}, nil, nil
+			})
+	}
+	httpServer := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+	defer httpServer.Close()
+
+	dir := filepath.Join(env.TempDir, "linear")
+	env.WriteFile(filepath.Join(dir, "metadata.toml"), `[asset]
+name = "linear"
+version = "1.0.0"
+type = "mcp-remote"
+
+[mcp]
+url = "`+httpServer.URL+`"
+`)
+
+	mockPrompter := NewMockPrompter().
+		ExpectConfirm("correct", true).
+		ExpectPrompt("Version", "1.0.0").
+		ExpectPrompt("choice", "1").
+		ExpectPrompt("Who is this asset for", "1").
+		ExpectConfirm("Run install now", false)
+
+	addCmd := NewAddCommand()
+	addCmd.SetArgs([]string{dir, "--check"})
+	if err := ExecuteWithPrompter(addCmd, mockPrompter); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+
+	meta, err := metadata.ParseFile(filepath.Join(vaultDir, "assets", "linear", "1.0.0", "metadata.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(meta.MCP.Capabilities, ","); got != "create_issue,list_issues" {
+		t.Errorf("capabilities = %q, want the advertised tools", got)
+	}
+}
@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/config"
+	"github.com/sleuth-io/sx/internal/metadata"
+	"github.com/sleuth-io/sx/internal/scaffold"
+	"github.com/sleuth-io/sx/internal/ui"
+	"github.com/sleuth-io/sx/internal/ui/components"
+)
+
+// NewNewCommand creates the new command
+func NewNewCommand() *cobra.Command {
+	var opts newOptions
+
+	cmd := &cobra.Command{
+		Use:   "new <type> <name>",
+		Short: "Scaffold a new asset directory",
+		Long: `Generate a new asset directory with metadata.toml, a templated prompt or script
+file, and a README, ready to edit and publish with 'sx add'.
+
+Types: skill, command, agent, hook, mcp, mcp-remote
+
+Templates can be customized per organization by committing them to the vault as
+templates/<type>/<file>.tmpl (e.g. templates/skill/SKILL.md.tmpl). They are Go
+templates with .Name, .Title, .Description, .Triggers, .Event, .Command, .Args,
+.URL and .Transport.
+
+Remote MCP servers are registered by URL. The transport defaults to http, or
+sse for URLs ending in /sse.
+
+Examples:
+  sx new skill code-review -d "Review code against our standards"
+  sx new hook lint-staged --event pre-commit
+  sx new mcp github --command "npx -y @modelcontextprotocol/server-github"
+  sx new mcp-remote linear --url https://mcp.linear.app/sse
+  sx new agent reviewer -i   # Prompt for description and triggers`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runNew(cmd, args[0], args[1], opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.dir, "dir", "", "Directory to create (default: ./<name>)")
+	cmd.Flags().StringVarP(&opts.description, "description", "d", "", "Asset description")
+	cmd.Flags().StringSliceVar(&opts.triggers, "trigger", nil, "When to use the skill or agent (repeatable)")
+	cmd.Flags().StringVar(&opts.event, "event", "", "Hook event (pre-commit, post-commit, pre-push, post-push, pre-merge, post-merge)")
+	cmd.Flags().StringVar(&opts.command, "command", "", "Command line that starts the MCP server")
+	cmd.Flags().StringVar(&opts.url, "url", "", "Remote MCP server URL (mcp-remote)")
+	cmd.Flags().StringVar(&opts.transport, "transport", "", "Remote MCP transport: http or sse (mcp-remote)")
+	cmd.Flags().BoolVarP(&opts.interactive, "interactive", "i", false, "Prompt for the description and type-specific settings")
+
+	return cmd
+}
+
+type newOptions struct {
+	dir         string
+	description string
+	triggers    []string
+	event       string
+	command     string
+	url         string
+	transport   string
+	interactive bool
+}
+
+func runNew(cmd *cobra.Command, typeKey, name string, opts newOptions) error {
+	styledOut := ui.NewOutput(cmd.OutOrStdout(), cmd.ErrOrStderr())
+
+	assetType := asset.FromString(typeKey)
+	if !assetType.IsValid() {
+		return fmt.Errorf("unknown asset type %q (must be one of: skill, command, agent, hook, mcp, mcp-remote)", typeKey)
+	}
+
+	if opts.interactive {
+		if err := promptNewOptions(cmd, assetType, name, &opts); err != nil {
+			return err
+		}
+	}
+
+	scaffoldOpts := scaffold.Options{
+		Type:        assetType,
+		Name:        name,
+		Description: opts.description,
+		Triggers:    opts.triggers,
+		Event:       opts.event,
+	}
+	if scaffoldOpts.Description == "" {
+		scaffoldOpts.Description = fmt.Sprintf("Describe what %s does", name)
+	}
+
+	switch assetType {
+	case asset.TypeMCP:
+		fields := strings.Fields(opts.command)
+		if len(fields) == 0 {
+			fields = []string{"npx", "-y", name}
+		}
+		scaffoldOpts.Command, scaffoldOpts.Args = fields[0], fields[1:]
+	case asset.TypeMCPRemote:
+		if opts.url == "" {
+			return fmt.Errorf("--url is required for mcp-remote assets (or use -i)")
+		}
+		scaffoldOpts.URL = opts.url
+		scaffoldOpts.Transport = opts.transport
+		if scaffoldOpts.Transport == "" && strings.HasSuffix(strings.TrimRight(opts.url, "/"), "/sse") {
+			scaffoldOpts.Transport = metadata.MCPTransportSSE
+		}
+	}
+
+	dir := opts.dir
+	if dir == "" {
+		dir = name
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	files, err := scaffold.Generate(dir, scaffoldOpts, vaultTemplateReader(ctx, cmd))
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", assetType.Label, err)
+	}
+
+	styledOut.Success(fmt.Sprintf("Created %s %s in %s", assetType.Label, name, dir))
+	for _, file := range files {
+		styledOut.ListItem("•", filepath.Join(dir, file))
+	}
+	styledOut.Newline()
+	styledOut.Muted(fmt.Sprintf("Edit the files, then run 'sx add %s' to publish.", dir))
+
+	return nil
+}
+
+// promptNewOptions asks for the values the flags didn't provide
+func promptNewOptions(cmd *cobra.Command, assetType asset.Type, name string, opts *newOptions) error {
+	in, out := cmd.InOrStdin(), cmd.OutOrStdout()
+
+	if opts.description == "" {
+		description, err := components.InputWithIO("Description", "What does it do?", "", in, out)
+		if err != nil {
+			return err
+		}
+		opts.description = description
+	}
+
+	switch assetType {
+	case asset.TypeSkill, asset.TypeAgent:
+		if len(opts.triggers) == 0 {
+			triggers, err := components.InputWithIO("When should it be used? (comma-separated, optional)", "", "", in, out)
+			if err != nil {
+				return err
+			}
+			for _, trigger := range strings.Split(triggers, ",") {
+				if trigger = strings.TrimSpace(trigger); trigger != "" {
+					opts.triggers = append(opts.triggers, trigger)
+				}
+			}
+		}
+
+	case asset.TypeHook:
+		if opts.event == "" {
+			events := metadata.HookEvents()
+			options := make([]components.Option, len(events))
+			for i, event := range events {
+				options[i] = components.Option{Label: event, Value: event}
+			}
+			selected, err := components.SelectWithIO("Hook event", options, in, out)
+			if err != nil {
+				return err
+			}
+			opts.event = selected.Value
+		}
+
+	case asset.TypeMCP:
+		if opts.command == "" {
+			command, err := components.InputWithIO("Command to start the server", "", "npx -y "+name, in, out)
+			if err != nil {
+				return err
+			}
+			opts.command = command
+		}
+
+	case asset.TypeMCPRemote:
+		if opts.url == "" {
+			url, err := components.InputWithIO("Server URL", "https://example.com/mcp", "", in, out)
+			if err != nil {
+				return err
+			}
+			opts.url = strings.TrimSpace(url)
+		}
+	}
+
+	return nil
+}
+
+// vaultTemplateReader reads org templates from the configured vault, if any
+func vaultTemplateReader(ctx context.Context, cmd *cobra.Command) scaffold.TemplateReader {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil
+	}
+	readVault := vaultFileReader(cfg)
+	if readVault == nil {
+		return nil
+	}
+	// An unreachable vault shouldn't block scaffolding; fall back to the built-ins
+	warned := false
+	return func(path string) ([]byte, error) {
+		data, err := readVault(ctx, path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			if !warned {
+				ui.NewOutput(cmd.OutOrStdout(), cmd.ErrOrStderr()).Warning("Using built-in templates: " + err.Error())
+				warned = true
+			}
+			return nil, fs.ErrNotExist
+		}
+		return data, err
+	}
+}
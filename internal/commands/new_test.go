@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/metadata"
+)
+
+func TestNewUsesVaultTemplates(t *testing.T) {
+	env := NewTestEnv(t)
+	vaultDir := env.SetupPathVault()
+	env.WriteFile(filepath.Join(vaultDir, "templates", "skill", "SKILL.md.tmpl"), "ACME: {{.Title}}\n")
+
+	dir := filepath.Join(env.TempDir, "code-review")
+	cmd := NewNewCommand()
+	cmd.SetArgs([]string{"skill", "code-review", "--dir", dir, "-d", "Review code", "--trigger", "Reviewing a PR"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("new failed: %v", err)
+	}
+
+	skill, err := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(skill) != "ACME: Code Review\n" {
+		t.Errorf("SKILL.md = %q, want the vault template", skill)
+	}
+	env.AssertFileExists(filepath.Join(dir, "README.md"))
+
+	meta, err := metadata.ParseFile(filepath.Join(dir, "metadata.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Asset.Description != "Review code" || len(meta.Skill.Triggers) != 1 {
+		t.Errorf("unexpected metadata: %+v %+v", meta.Asset, meta.Skill)
+	}
+}
+
+func TestNewMCPCommand(t *testing.T) {
+	env := NewTestEnv(t)
+	env.SetupPathVault()
+
+	dir := filepath.Join(env.TempDir, "github")
+	cmd := NewNewCommand()
+	cmd.SetArgs([]string{"mcp", "github", "--dir", dir, "--command", "npx -y @modelcontextprotocol/server-github"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("new failed: %v", err)
+	}
+
+	meta, err := metadata.ParseFile(filepath.Join(dir, "metadata.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.MCP.Command != "npx" || strings.Join(meta.MCP.Args, " ") != "-y @modelcontextprotocol/server-github" {
+		t.Errorf("MCP = %s %v", meta.MCP.Command, meta.MCP.Args)
+	}
+}
+
+func TestNewRemoteMCPCommand(t *testing.T) {
+	env := NewTestEnv(t)
+	env.SetupPathVault()
+
+	dir := filepath.Join(env.TempDir, "linear")
+	cmd := NewNewCommand()
+	cmd.SetArgs([]string{"mcp-remote", "linear", "--dir", dir, "--url", "https://mcp.linear.app/sse"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("new failed: %v", err)
+	}
+
+	meta, err := metadata.ParseFile(filepath.Join(dir, "metadata.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.MCP.URL != "https://mcp.linear.app/sse" || meta.MCP.Transport != "sse" || meta.MCP.Command != "" {
+		t.Errorf("MCP = %+v, want a remote sse server", meta.MCP)
+	}
+}
+
+func TestNewRejectsUnknownType(t *testing.T) {
+	NewTestEnv(t)
+
+	cmd := NewNewCommand()
+	cmd.SetArgs([]string{"widget", "foo", "--dir", t.TempDir()})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error for an unknown asset type")
+	}
+}
@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sleuth-io/sx/internal/config"
+	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/registry"
+	"github.com/sleuth-io/sx/internal/ui"
+	vaultpkg "github.com/sleuth-io/sx/internal/vault"
+)
+
+// NewRegistryCommand creates the registry command
+func NewRegistryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "registry",
+		Short: "Search and add skills from curated registries",
+		Long: `Browse curated skill registries. Registries are YAML indexes listing skills by
+name, description, category, tags, and GitHub URL, optionally pinned to a commit.
+
+Configure them with "registries" in the sx config file: http(s) URLs, local
+paths, or "vault:<path>" for a file in your vault. Without configuration, the
+vault's registry.yaml is used if present, otherwise the built-in featured list.`,
+	}
+
+	cmd.AddCommand(newRegistrySearchCommand())
+	cmd.AddCommand(newRegistryAddCommand())
+
+	return cmd
+}
+
+func newRegistrySearchCommand() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "search [term]",
+		Short: "Search registry skills by name, description, category, or tag",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var term string
+			if len(args) > 0 {
+				term = args[0]
+			}
+			return runRegistrySearch(cmd, term, jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
+func newRegistryAddCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "Add a registry skill to your vault",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRegistryAdd(cmd, args[0])
+		},
+	}
+
+	cmd.Flags().Bool("propose", false, "Push to a proposal branch for review instead of publishing (git vaults only)")
+
+	return cmd
+}
+
+func runRegistrySearch(cmd *cobra.Command, term string, jsonOutput bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	out := newOutputHelper(cmd)
+	styledOut := ui.NewOutput(cmd.OutOrStdout(), cmd.ErrOrStderr())
+
+	skills, err := loadRegistrySkills(ctx)
+	if err != nil {
+		if len(skills) == 0 {
+			return err
+		}
+		styledOut.Warning(err.Error())
+	}
+
+	var matches []registry.Skill
+	for _, skill := range skills {
+		if skill.Matches(term) {
+			matches = append(matches, skill)
+		}
+	}
+
+	if jsonOutput {
+		if matches == nil {
+			matches = []registry.Skill{}
+		}
+		data, err := json.MarshalIndent(matches, "", "  ")
+		if err != nil {
+			return err
+		}
+		out.printlnAlways(string(data))
+		return nil
+	}
+
+	if len(matches) == 0 {
+		out.printf("No registry skills match %q.\n", term)
+		return nil
+	}
+
+	// Group by category, uncategorized last
+	byCategory := make(map[string][]registry.Skill)
+	var categories []string
+	for _, skill := range matches {
+		if _, ok := byCategory[skill.Category]; !ok {
+			categories = append(categories, skill.Category)
+		}
+		byCategory[skill.Category] = append(byCategory[skill.Category], skill)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i] == "" || categories[j] == "" {
+			return categories[j] == ""
+		}
+		return categories[i] < categories[j]
+	})
+
+	styledOut.Newline()
+	for _, category := range categories {
+		label := category
+		if label == "" {
+			label = "Other"
+		}
+		styledOut.Bold(label)
+		for _, skill := range byCategory[category] {
+			styledOut.Println("  " + styledOut.EmphasisText(skill.Name))
+			if skill.Description != "" {
+				styledOut.Muted("    " + skill.Description)
+			}
+			var details []string
+			if len(skill.Tags) > 0 {
+				details = append(details, "tags: "+strings.Join(skill.Tags, ", "))
+			}
+			if skill.Commit != "" {
+				details = append(details, "pinned: "+shortSHA(skill.Commit))
+			}
+			if len(details) > 0 {
+				styledOut.Muted("    " + strings.Join(details, " · "))
+			}
+		}
+		styledOut.Newline()
+	}
+	styledOut.Muted("Run 'sx registry add <name>' to add a skill to your vault.")
+
+	return nil
+}
+
+func runRegistryAdd(cmd *cobra.Command, name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	skills, err := loadRegistrySkills(ctx)
+	skill := registry.Find(skills, name)
+	if skill == nil {
+		if err != nil {
+			return fmt.Errorf("skill %q not found: %w", name, err)
+		}
+		return fmt.Errorf("skill %q not found in the registry; run 'sx registry search' to browse", name)
+	}
+
+	out := newOutputHelper(cmd)
+	out.printf("Adding %s from %s\n", skill.Name, skill.InstallURL())
+
+	return runAdd(cmd, skill.InstallURL())
+}
+
+// loadRegistrySkills returns the skills from the configured registries, falling back
+// to the vault's registry.yaml and then the built-in featured list when none are set
+func loadRegistrySkills(ctx context.Context) ([]registry.Skill, error) {
+	cfg, _ := config.Load()
+
+	var sources []string
+	if cfg != nil {
+		sources = cfg.Registries
+	}
+	if len(sources) > 0 {
+		return registry.Load(ctx, sources, vaultFileReader(cfg))
+	}
+
+	// The vault's own index is optional, so a missing file isn't worth a warning
+	skills, err := registry.Load(ctx, []string{registry.DefaultVaultIndex}, vaultFileReader(cfg))
+	if err != nil {
+		log := logger.Get()
+		log.Debug("no vault registry index", "error", err)
+	}
+	if len(skills) > 0 {
+		return skills, nil
+	}
+	return registry.FeaturedSkills()
+}
+
+// vaultFileReader returns a reader for files in the configured vault, or nil when
+// the vault can't serve files
+func vaultFileReader(cfg *config.Config) registry.VaultReader {
+	if cfg == nil {
+		return nil
+	}
+	vault, err := vaultpkg.NewFromConfig(cfg)
+	if err != nil {
+		return nil
+	}
+	reader, ok := vault.(interface {
+		ReadFile(ctx context.Context, relPath string) ([]byte, error)
+	})
+	if !ok {
+		return nil
+	}
+	return reader.ReadFile
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
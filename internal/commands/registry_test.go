@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/registry"
+)
+
+func TestRegistrySearchUsesVaultIndex(t *testing.T) {
+	env := NewTestEnv(t)
+	vaultDir := env.SetupPathVault()
+	env.WriteFile(filepath.Join(vaultDir, "registry.yaml"), `- name: deploy-checklist
+  description: Pre-deploy checks
+  url: https://github.com/acme/skills/tree/main/deploy-checklist
+  category: Operations
+  tags: [release]
+- name: code-review
+  description: Review standards
+  url: https://github.com/acme/skills/tree/main/code-review
+`)
+
+	cmd := NewRegistryCommand()
+	cmd.SetArgs([]string{"search", "release", "--json"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("registry search failed: %v", err)
+	}
+
+	var skills []registry.Skill
+	if err := json.Unmarshal(out.Bytes(), &skills); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out.String())
+	}
+	if len(skills) != 1 || skills[0].Name != "deploy-checklist" {
+		t.Errorf("expected only the company's deploy-checklist, got %+v", skills)
+	}
+}
+
+func TestRegistryAddUnknownSkill(t *testing.T) {
+	env := NewTestEnv(t)
+	env.SetupPathVault()
+
+	cmd := NewRegistryCommand()
+	cmd.SetArgs([]string{"add", "no-such-skill"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err == nil {
+		t.Error("expected an error for a skill missing from the registry")
+	}
+}
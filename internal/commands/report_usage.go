@@ -15,9 +15,13 @@ import (
 	"github.com/sleuth-io/sx/internal/config"
 	"github.com/sleuth-io/sx/internal/logger"
 	"github.com/sleuth-io/sx/internal/stats"
+	"github.com/sleuth-io/sx/internal/telemetry"
 	vaultpkg "github.com/sleuth-io/sx/internal/vault"
 )
 
+// otlpExportTimeout bounds the OTLP export in the usage hook
+const otlpExportTimeout = 2 * time.Second
+
 // NewReportUsageCommand creates the report-usage command
 func NewReportUsageCommand() *cobra.Command {
 	var clientID string
@@ -148,13 +152,17 @@ func runReportUsage(cmd *cobra.Command, args []string) error {
 	vault, err := vaultpkg.NewFromConfig(cfg)
 	if err != nil {
 		log.Error("report-usage: failed to create vault", "error", err)
-		return nil
-	}
-
-	// Try to flush queue
-	if err := stats.FlushQueue(ctx, vault); err != nil {
+	} else if err := stats.FlushQueue(ctx, vault); err != nil {
 		log.Error("report-usage: failed to flush usage stats", "error", err)
 	}
 
+	// Export usage to the OTLP collector, if one is configured. It gets its own
+	// deadline so a slow collector can't hold up the vault flush.
+	exporter := telemetry.Init(cfg.GetOTLPEndpoint(), cfg.OTLPHeaders)
+	exporter.RecordUsage(usageEvent)
+	otlpCtx, otlpCancel := context.WithTimeout(context.Background(), otlpExportTimeout)
+	defer otlpCancel()
+	_ = exporter.Flush(otlpCtx) // Failures are logged by the exporter
+
 	return nil
 }
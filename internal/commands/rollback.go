@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sleuth-io/sx/internal/assets"
+	"github.com/sleuth-io/sx/internal/journal"
+	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/ui"
+	"github.com/sleuth-io/sx/internal/ui/components"
+)
+
+// NewRollbackCommand creates the rollback command
+func NewRollbackCommand() *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Restore client files to their state before the last install",
+		Long: `Rollback undoes the last successful install by restoring every file it changed
+(skills, settings.json, .mcp.json, hooks.json, etc.) and the installation tracker.
+
+Note: the next 'sx install' re-applies the lock file, so remove or pin the
+offending asset in the vault to keep it from coming back.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRollback(cmd, yes)
+		},
+	}
+
+	cmd.Flags().BoolVar(&yes, "yes", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+// runRollback executes the rollback command
+func runRollback(cmd *cobra.Command, yes bool) error {
+	styledOut := ui.NewOutput(cmd.OutOrStdout(), cmd.ErrOrStderr())
+
+	// Hold the install lock so an install can't change files while they're restored
+	unlock, err := journal.Lock(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to start rollback: %w", err)
+	}
+	defer unlock()
+
+	j, err := journal.LoadLast()
+	if err != nil {
+		return fmt.Errorf("failed to load install journal: %w", err)
+	}
+	if j == nil {
+		styledOut.Info("Nothing to roll back")
+		return nil
+	}
+
+	trackerPath, _ := assets.GetTrackerPath()
+
+	styledOut.Header(fmt.Sprintf("Last install: %s", j.StartedAt.Local().Format("Jan 2, 2006 15:04:05")))
+	for _, e := range j.Entries {
+		if e.Path == trackerPath {
+			continue
+		}
+		action := "restore"
+		if !e.Existed {
+			action = "remove"
+		}
+		styledOut.ListItem("•", fmt.Sprintf("%s %s", action, e.Path))
+	}
+	styledOut.Newline()
+
+	if !yes {
+		confirmed, err := components.ConfirmWithIO("Roll back these changes?", true, cmd.InOrStdin(), cmd.OutOrStdout())
+		if err != nil {
+			return fmt.Errorf("failed to read confirmation: %w", err)
+		}
+		if !confirmed {
+			styledOut.Info("Rollback cancelled")
+			return nil
+		}
+	}
+
+	if err := j.Rollback(); err != nil {
+		logger.Get().Error("rollback failed", "error", err)
+		return fmt.Errorf("rollback failed: %w", err)
+	}
+
+	logger.Get().Info("rollback completed", "paths", len(j.Entries))
+	styledOut.Success("Rolled back last install")
+	return nil
+}
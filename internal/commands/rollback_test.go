@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func TestRollbackSurvivesNoOpInstall(t *testing.T) {
+	env := NewTestEnv(t)
+	vaultDir := env.SetupPathVault()
+	env.AddSkillToVault(vaultDir, "go-testing", "1.0.0")
+	env.WriteLockFile(vaultDir, `lock-version = "1"
+version = "1.0.0"
+created-by = "test"
+
+[[assets]]
+name = "go-testing"
+version = "1.0.0"
+type = "skill"
+
+[assets.source-path]
+path = "assets/go-testing/1.0.0"
+`)
+
+	// Cursor regenerates its skill rules in the working directory on every install
+	env.MkdirAll(filepath.Join(env.HomeDir, ".cursor"))
+	env.Chdir(env.MkdirAll(filepath.Join(env.TempDir, "project")))
+
+	install := func(args ...string) {
+		t.Helper()
+		installCmd := NewInstallCommand()
+		installCmd.SetOut(&bytes.Buffer{})
+		installCmd.SetErr(&bytes.Buffer{})
+		installCmd.SetArgs(args)
+		if err := installCmd.Execute(); err != nil {
+			t.Fatalf("install failed: %v", err)
+		}
+	}
+
+	skill := filepath.Join(env.GlobalClaudeDir(), "skills", "go-testing")
+	install()
+	env.AssertFileExists(skill)
+
+	// Nothing changed, so the first install stays the one rollback undoes
+	install()
+	install("--hook-mode", "--client", "claude-code")
+
+	rollbackCmd := NewRollbackCommand()
+	rollbackCmd.SetOut(&bytes.Buffer{})
+	rollbackCmd.SetErr(&bytes.Buffer{})
+	rollbackCmd.SetArgs([]string{"--yes"})
+	if err := rollbackCmd.Execute(); err != nil {
+		t.Fatalf("rollback failed: %v", err)
+	}
+	env.AssertFileNotExists(skill)
+}
@@ -0,0 +1,198 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sleuth-io/sx/internal/config"
+	"github.com/sleuth-io/sx/internal/lint"
+	"github.com/sleuth-io/sx/internal/lockfile"
+	"github.com/sleuth-io/sx/internal/ui"
+	vaultpkg "github.com/sleuth-io/sx/internal/vault"
+)
+
+// NewValidateCommand creates the validate command
+func NewValidateCommand() *cobra.Command {
+	var format string
+	var maxPromptSize int
+
+	cmd := &cobra.Command{
+		Use:   "validate [path]",
+		Short: "Lint asset directories, zips, or a vault",
+		Long: `Check assets for problems beyond metadata structure: SKILL.md frontmatter that
+disagrees with metadata.toml, @file references to files that aren't packaged,
+hook scripts without a shebang or executable bit, MCP commands not declared in
+[mcp] requires, malformed tool requirements, oversized prompts, naming, and
+dependency constraints that don't resolve.
+
+path can be an asset directory, an asset zip, a directory of assets, or a vault
+directory (with assets/<name>/<version>/). Without a path, every asset in the
+configured vault's lock file is checked.
+
+Exits non-zero when any error is found, so it can gate CI.
+
+Examples:
+  sx validate ./my-skill
+  sx validate ./vault --format sarif > sx.sarif
+  sx validate --format json`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var path string
+			if len(args) > 0 {
+				path = args[0]
+			}
+			return runValidate(cmd, path, format, maxPromptSize)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "human", "Output format: human, json, or sarif")
+	cmd.Flags().IntVar(&maxPromptSize, "max-prompt-size", lint.DefaultMaxPromptBytes, "Warn when a prompt file is larger than this many bytes")
+
+	return cmd
+}
+
+func runValidate(cmd *cobra.Command, path, format string, maxPromptSize int) error {
+	switch format {
+	case "human", "json", "sarif":
+	default:
+		return fmt.Errorf("unknown format %q (must be human, json, or sarif)", format)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	var targets []lint.Target
+	var versions lint.VersionLister
+	var err error
+	if path != "" {
+		targets, versions, err = lint.Discover(path)
+	} else {
+		targets, versions, err = vaultLintTargets(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	opts := lint.Options{MaxPromptBytes: maxPromptSize, Versions: versions}
+	report := &lint.Report{Findings: []lint.Finding{}}
+	for _, target := range targets {
+		zipData, err := target.Load()
+		if err != nil {
+			report.Add([]lint.Finding{{
+				Rule:     lint.RuleMetadata,
+				Severity: lint.SeverityError,
+				Source:   target.Source,
+				Message:  err.Error(),
+			}})
+			continue
+		}
+		report.Add(lint.Check(zipData, target.Source, opts))
+	}
+
+	out := newOutputHelper(cmd)
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		out.printlnAlways(string(data))
+	case "sarif":
+		data, err := report.SARIF()
+		if err != nil {
+			return err
+		}
+		out.printlnAlways(string(data))
+	default:
+		printValidateReport(cmd, targets, report)
+	}
+
+	if report.HasErrors() {
+		return fmt.Errorf("validation failed: %d error(s)", report.Errors)
+	}
+	return nil
+}
+
+// vaultLintTargets lists the assets in the configured vault's lock file
+func vaultLintTargets(ctx context.Context) ([]lint.Target, lint.VersionLister, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	vault, err := vaultpkg.NewFromConfig(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create vault: %w", err)
+	}
+
+	data, _, _, err := vault.GetLockFile(ctx, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch lock file: %w", err)
+	}
+	lockFile, err := lockfile.Parse(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse lock file: %w", err)
+	}
+
+	lockedVersions := make(map[string][]string)
+	var targets []lint.Target
+	for i := range lockFile.Assets {
+		asset := &lockFile.Assets[i]
+		lockedVersions[asset.Name] = append(lockedVersions[asset.Name], asset.Version)
+		targets = append(targets, lint.Target{
+			Source: asset.Key(),
+			Load:   func() ([]byte, error) { return vault.GetAsset(ctx, asset) },
+		})
+	}
+
+	// Vaults without version lists can still resolve against what's locked
+	versions := func(name string) ([]string, error) {
+		if listed, err := vault.GetVersionList(ctx, name); err == nil && len(listed) > 0 {
+			return listed, nil
+		}
+		return lockedVersions[name], nil
+	}
+
+	return targets, versions, nil
+}
+
+func printValidateReport(cmd *cobra.Command, targets []lint.Target, report *lint.Report) {
+	styledOut := ui.NewOutput(cmd.OutOrStdout(), cmd.ErrOrStderr())
+
+	bySource := make(map[string][]lint.Finding)
+	for _, f := range report.Findings {
+		bySource[f.Source] = append(bySource[f.Source], f)
+	}
+
+	for _, target := range targets {
+		findings := bySource[target.Source]
+		if len(findings) == 0 {
+			styledOut.Success(target.Source)
+			continue
+		}
+
+		styledOut.Bold(target.Source)
+		for _, f := range findings {
+			location := f.File
+			if f.Line > 0 {
+				location = fmt.Sprintf("%s:%d", f.File, f.Line)
+			}
+			severity := styledOut.WarningText("warning")
+			if f.Severity == lint.SeverityError {
+				severity = styledOut.ErrorText("error  ")
+			}
+			line := fmt.Sprintf("  %s %s %s", severity, f.Message, styledOut.MutedText("["+f.Rule+"]"))
+			if location != "" {
+				line = fmt.Sprintf("  %s %s: %s %s", severity, location, f.Message, styledOut.MutedText("["+f.Rule+"]"))
+			}
+			styledOut.Println(line)
+		}
+	}
+
+	styledOut.Newline()
+	styledOut.Println(fmt.Sprintf("Checked %d asset(s): %d error(s), %d warning(s)", report.Assets, report.Errors, report.Warnings))
+}
@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/lint"
+)
+
+func TestValidateScaffoldedAssets(t *testing.T) {
+	env := NewTestEnv(t)
+	env.SetupPathVault()
+	assetsDir := env.MkdirAll(filepath.Join(env.TempDir, "assets"))
+
+	for _, args := range [][]string{
+		{"skill", "code-review", "-d", "Review code"},
+		{"hook", "lint", "--event", "pre-commit"},
+		{"mcp", "github"},
+	} {
+		newCmd := NewNewCommand()
+		newCmd.SetArgs(append(args, "--dir", filepath.Join(assetsDir, args[1])))
+		newCmd.SetOut(&bytes.Buffer{})
+		if err := newCmd.Execute(); err != nil {
+			t.Fatalf("new %v failed: %v", args, err)
+		}
+	}
+
+	cmd := NewValidateCommand()
+	cmd.SetArgs([]string{assetsDir, "--format", "json"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("validate failed: %v\n%s", err, out.String())
+	}
+
+	var report lint.Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out.String())
+	}
+	if report.Assets != 3 || len(report.Findings) != 0 {
+		t.Errorf("expected 3 clean assets, got %+v", report)
+	}
+}
+
+func TestValidateFailsOnErrors(t *testing.T) {
+	env := NewTestEnv(t)
+	dir := env.MkdirAll(filepath.Join(env.TempDir, "code-review"))
+	env.WriteFile(filepath.Join(dir, "metadata.toml"), `[asset]
+name = "code-review"
+version = "1.0.0"
+type = "skill"
+description = "Review code"
+
+[skill]
+prompt-file = "SKILL.md"
+`)
+	env.WriteFile(filepath.Join(dir, "SKILL.md"), "---\nname: reviewer\ndescription: Review code\n---\nSee @guide.md\n")
+
+	cmd := NewValidateCommand()
+	cmd.SetArgs([]string{dir, "--format", "sarif"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected validation to fail")
+	}
+
+	var log map[string]any
+	if err := json.Unmarshal(out.Bytes(), &log); err != nil {
+		t.Fatalf("invalid SARIF output: %v\n%s", err, out.String())
+	}
+	results := log["runs"].([]any)[0].(map[string]any)["results"].([]any)
+	if len(results) != 2 {
+		t.Errorf("expected frontmatter and file-reference results, got %d", len(results))
+	}
+}
+
+func TestValidateConfiguredVault(t *testing.T) {
+	env := NewTestEnv(t)
+	vaultDir := env.SetupPathVault()
+	env.AddSkillToVault(vaultDir, "code-review", "1.0.0")
+	env.WriteLockFile(vaultDir, `lock-version = "1.0"
+version = "1"
+created-by = "test"
+
+[[assets]]
+name = "code-review"
+version = "1.0.0"
+type = "skill"
+
+[assets.source-path]
+path = "assets/code-review/1.0.0"
+`)
+
+	cmd := NewValidateCommand()
+	cmd.SetArgs([]string{"--format", "json"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	_ = cmd.Execute()
+
+	var report lint.Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, out.String())
+	}
+	if report.Assets != 1 {
+		t.Errorf("expected the locked asset to be checked, got %+v", report)
+	}
+	for _, f := range report.Findings {
+		if f.Rule == lint.RuleMetadata {
+			t.Errorf("failed to load asset from vault: %s", f.Message)
+		}
+	}
+}
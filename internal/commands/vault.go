@@ -20,12 +20,13 @@ import (
 func NewVaultCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "vault",
-		Short: "Manage vault assets (list, show)",
+		Short: "Manage vault assets (list, show, pending)",
 		Long:  "Browse and inspect assets in the configured vault.",
 	}
 
 	cmd.AddCommand(newVaultListCommand())
 	cmd.AddCommand(newVaultShowCommand())
+	cmd.AddCommand(newVaultPendingCommand())
 
 	return cmd
 }
@@ -67,6 +68,25 @@ func newVaultShowCommand() *cobra.Command {
 	return cmd
 }
 
+func newVaultPendingCommand() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "pending",
+		Short: "List proposed assets awaiting review",
+		Long: `List proposal branches pushed by 'sx add --propose' (or by a vault with
+review-required = true) that haven't been merged yet. Git vaults only.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runVaultPending(cmd, jsonOutput)
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+
+	return cmd
+}
+
 func runVaultList(cmd *cobra.Command, typeFilter string, jsonOutput bool) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -258,6 +278,11 @@ func printVaultShowText(out *outputHelper, details *vaultpkg.AssetDetails, scope
 		ui.Newline()
 	}
 
+	if details.Metadata != nil && details.Metadata.IsDangerous() {
+		ui.Println(ui.WarningText("Dangerous command: asks for confirmation before making changes"))
+		ui.Newline()
+	}
+
 	// Show installation status
 	if scopesFound {
 		displayCurrentInstallation(currentScopes, ui)
@@ -295,6 +320,14 @@ func printVaultShowText(out *outputHelper, details *vaultpkg.AssetDetails, scope
 	}
 
 	if details.Metadata != nil {
+		if details.Metadata.Command != nil && len(details.Metadata.Command.Aliases) > 0 {
+			ui.Bold("Aliases")
+			for _, alias := range details.Metadata.Command.Aliases {
+				ui.ListItem("•", "/"+alias)
+			}
+			ui.Newline()
+		}
+
 		if len(details.Metadata.Asset.Dependencies) > 0 {
 			ui.Bold("Dependencies")
 			for _, dep := range details.Metadata.Asset.Dependencies {
@@ -326,6 +359,7 @@ func printVaultShowJSON(out *outputHelper, details *vaultpkg.AssetDetails, scope
 		"createdAt":   details.CreatedAt,
 		"updatedAt":   details.UpdatedAt,
 		"installed":   scopesFound,
+		"dangerous":   details.Metadata != nil && details.Metadata.IsDangerous(),
 	}
 
 	if scopesFound {
@@ -343,3 +377,84 @@ func printVaultShowJSON(out *outputHelper, details *vaultpkg.AssetDetails, scope
 	out.printlnAlways(string(data))
 	return nil
 }
+
+func runVaultPending(cmd *cobra.Command, jsonOutput bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	out := newOutputHelper(cmd)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w\nRun 'sx init' to configure", err)
+	}
+
+	vault, err := vaultpkg.NewFromConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create vault: %w", err)
+	}
+
+	gitVault, ok := vault.(*vaultpkg.GitVault)
+	if !ok {
+		return fmt.Errorf("proposals are only supported for git vaults")
+	}
+
+	var status *components.Status
+	if !jsonOutput {
+		status = components.NewStatus(cmd.OutOrStdout())
+		status.Start("Fetching proposal branches")
+	}
+
+	proposals, err := gitVault.ListProposals(ctx)
+
+	if status != nil {
+		status.Done("")
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to list proposals: %w", err)
+	}
+
+	if jsonOutput {
+		if proposals == nil {
+			proposals = []vaultpkg.Proposal{}
+		}
+		data, err := json.MarshalIndent(proposals, "", "  ")
+		if err != nil {
+			return err
+		}
+		out.printlnAlways(string(data))
+		return nil
+	}
+
+	if len(proposals) == 0 {
+		out.println("No pending proposals.")
+		return nil
+	}
+
+	ui := ui.NewOutput(out.cmd.OutOrStdout(), out.cmd.ErrOrStderr())
+	ui.Newline()
+	ui.Header("Pending Proposals")
+	ui.Newline()
+	for _, p := range proposals {
+		line := fmt.Sprintf("  %s %s", ui.EmphasisText(p.Asset), ui.MutedText("v"+p.Version))
+		if p.Version == "remove" {
+			line = fmt.Sprintf("  %s %s", ui.EmphasisText(p.Asset), ui.MutedText("(removal)"))
+		}
+		ui.Println(line)
+		details := "    " + p.Branch
+		if p.Author != "" {
+			details += " by " + p.Author
+		}
+		if !p.UpdatedAt.IsZero() {
+			details += ", " + p.UpdatedAt.Format("2006-01-02")
+		}
+		ui.Muted(details)
+		if p.CompareURL != "" {
+			ui.Muted("    " + p.CompareURL)
+		}
+	}
+	ui.Newline()
+
+	return nil
+}
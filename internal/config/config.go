@@ -37,6 +37,35 @@ type Config struct {
 	// EnabledClients is the list of client IDs that assets should be installed to.
 	// An empty/nil slice means "all detected clients" (backwards compatible default).
 	EnabledClients []string `json:"enabledClients,omitempty"`
+
+	// OTLPEndpoint is the base URL of an OTLP/HTTP collector (e.g. http://localhost:4318).
+	// When set, install phases are exported as spans and asset usage as log records.
+	OTLPEndpoint string `json:"otlpEndpoint,omitempty"`
+
+	// OTLPHeaders are extra HTTP headers sent with every OTLP request (e.g. auth)
+	OTLPHeaders map[string]string `json:"otlpHeaders,omitempty"`
+
+	// Registries are the skill registry indexes searched by 'sx registry' and offered
+	// during init, in priority order: http(s) URLs, local paths, or "vault:<path>"
+	// for a file in the vault. Empty means the vault's registry.yaml if present,
+	// otherwise the built-in featured list.
+	Registries []string `json:"registries,omitempty"`
+
+	// Groups are the audiences this user belongs to (e.g. "frontend", "sre"), for
+	// assets that set an audience in the lock file
+	Groups []string `json:"groups,omitempty"`
+
+	// GroupRules add groups based on git user.email, mapping a group to email
+	// globs (e.g. {"contractors": ["*@agency.example.com"]})
+	GroupRules map[string][]string `json:"groupRules,omitempty"`
+
+	// ApprovedSetup lists the MCP server setups the user has allowed to run, as
+	// "name@content-hash", so a changed asset asks again
+	ApprovedSetup []string `json:"approvedSetup,omitempty"`
+
+	// ClaudePlugin installs Claude Code assets as plugins in a local marketplace
+	// maintained by sx, one per scope, instead of into .claude directly
+	ClaudePlugin bool `json:"claudePlugin,omitempty"`
 }
 
 // getLegacyConfigFile returns the old config file path for backwards compatibility
@@ -180,6 +209,18 @@ func (c *Config) GetRepositoryURL() string {
 	return c.RepositoryURL
 }
 
+// GetOTLPEndpoint returns the OTLP collector endpoint, with environment override
+// SX_OTLP_ENDPOINT takes precedence, then the standard OTEL_EXPORTER_OTLP_ENDPOINT
+func (c *Config) GetOTLPEndpoint() string {
+	if envURL := os.Getenv("SX_OTLP_ENDPOINT"); envURL != "" {
+		return envURL
+	}
+	if envURL := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); envURL != "" {
+		return envURL
+	}
+	return c.OTLPEndpoint
+}
+
 // IsSilent checks if silent mode is enabled via environment variable
 func IsSilent() bool {
 	return os.Getenv("SX_SYNC_SILENT") == "true" || os.Getenv("SKILLS_SYNC_SILENT") == "true"
@@ -199,6 +240,16 @@ func (c *Config) IsClientEnabled(clientID string) bool {
 	return false
 }
 
+// IsSetupApproved checks if an MCP server setup was approved
+func (c *Config) IsSetupApproved(key string) bool {
+	for _, approved := range c.ApprovedSetup {
+		if approved == key {
+			return true
+		}
+	}
+	return false
+}
+
 // GetEnabledClients returns the list of enabled client IDs.
 // Returns nil if not explicitly configured (meaning use all detected).
 func (c *Config) GetEnabledClients() []string {
@@ -2,6 +2,7 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -127,6 +128,10 @@ func (c *Client) Pull(ctx context.Context, repoPath string) error {
 	return nil
 }
 
+// ErrPushRejected is returned by Push when the remote has commits the local branch
+// doesn't, typically because someone else pushed first
+var ErrPushRejected = errors.New("git push rejected: remote contains newer commits")
+
 // Push pushes changes to the remote repository
 func (c *Client) Push(ctx context.Context, repoPath string) error {
 	cmd := execGitCommand(ctx, c.sshKeyPath, "push", "--quiet")
@@ -134,7 +139,67 @@ func (c *Client) Push(ctx context.Context, repoPath string) error {
 
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("git push failed: %w\nOutput: %s", err, string(output))
+		return pushError(err, output)
+	}
+
+	return nil
+}
+
+// pushError wraps a failed push, distinguishing non-fast-forward rejects
+func pushError(err error, output []byte) error {
+	text := string(output)
+	for _, marker := range []string{"non-fast-forward", "fetch first", "stale info", "[rejected]"} {
+		if strings.Contains(text, marker) {
+			return fmt.Errorf("%w\nOutput: %s", ErrPushRejected, text)
+		}
+	}
+	return fmt.Errorf("git push failed: %w\nOutput: %s", err, text)
+}
+
+// ResetToUpstream discards local commits and changes, resetting to the branch's upstream
+func (c *Client) ResetToUpstream(ctx context.Context, repoPath string) error {
+	cmd := execGitCommand(ctx, c.sshKeyPath, "reset", "--quiet", "--hard", "@{upstream}")
+	cmd.Dir = repoPath
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git reset failed: %w\nOutput: %s", err, string(output))
+	}
+
+	// Drop files added by the discarded change
+	cmd = execGitCommand(ctx, c.sshKeyPath, "clean", "--quiet", "-fd")
+	cmd.Dir = repoPath
+
+	output, err = cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git clean failed: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// FetchPrune fetches the remote and drops remote-tracking branches deleted upstream
+func (c *Client) FetchPrune(ctx context.Context, repoPath string) error {
+	cmd := execGitCommand(ctx, c.sshKeyPath, "fetch", "--quiet", "--prune", "origin")
+	cmd.Dir = repoPath
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git fetch failed: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// PushBranch pushes a local branch to origin and sets it as upstream
+// The push is forced with lease so re-proposing replaces the branch only if nobody else moved it.
+func (c *Client) PushBranch(ctx context.Context, repoPath, branch string) error {
+	cmd := execGitCommand(ctx, c.sshKeyPath, "push", "--quiet", "--force-with-lease", "--set-upstream", "origin", branch)
+	cmd.Dir = repoPath
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return pushError(err, output)
 	}
 
 	return nil
@@ -153,6 +218,82 @@ func (c *Client) Checkout(ctx context.Context, repoPath, ref string) error {
 	return nil
 }
 
+// CheckoutNewBranch creates (or resets) a branch at HEAD and checks it out
+// Uncommitted changes in the working tree are carried over to the branch.
+func (c *Client) CheckoutNewBranch(ctx context.Context, repoPath, branch string) error {
+	cmd := execGitCommand(ctx, c.sshKeyPath, "checkout", "--quiet", "-B", branch)
+	cmd.Dir = repoPath
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git checkout failed: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// DefaultBranch returns the branch origin/HEAD points to (e.g. "main")
+func (c *Client) DefaultBranch(ctx context.Context, repoPath string) (string, error) {
+	cmd := execGitCommand(ctx, c.sshKeyPath, "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+	cmd.Dir = repoPath
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git symbolic-ref failed: %w\nOutput: %s", err, string(output))
+	}
+
+	return strings.TrimPrefix(strings.TrimSpace(string(output)), "origin/"), nil
+}
+
+// RemoteBranch is a remote-tracking branch on origin
+type RemoteBranch struct {
+	Name       string
+	Author     string
+	CommitDate time.Time
+}
+
+// ListRemoteBranches returns origin's branches whose names start with prefix
+func (c *Client) ListRemoteBranches(ctx context.Context, repoPath, prefix string) ([]RemoteBranch, error) {
+	cmd := execGitCommand(ctx, c.sshKeyPath, "for-each-ref",
+		"--format=%(refname:strip=3)%09%(authorname)%09%(committerdate:iso-strict)",
+		"refs/remotes/origin/"+prefix)
+	cmd.Dir = repoPath
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git for-each-ref failed: %w", err)
+	}
+
+	var branches []RemoteBranch
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) != 3 || !strings.HasPrefix(parts[0], prefix) {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, parts[2])
+		branches = append(branches, RemoteBranch{Name: parts[0], Author: parts[1], CommitDate: date})
+	}
+
+	return branches, nil
+}
+
+// IsAncestor reports whether ancestor is reachable from ref (i.e. already merged into it)
+func (c *Client) IsAncestor(ctx context.Context, repoPath, ancestor, ref string) (bool, error) {
+	cmd := execGitCommand(ctx, c.sshKeyPath, "merge-base", "--is-ancestor", ancestor, ref)
+	cmd.Dir = repoPath
+
+	err := cmd.Run()
+	if err != nil {
+		// Exit code 1 means not an ancestor
+		if exitError, ok := err.(*exec.ExitError); ok && exitError.ExitCode() == 1 {
+			return false, nil
+		}
+		return false, fmt.Errorf("git merge-base failed: %w", err)
+	}
+
+	return true, nil
+}
+
 // LsRemote queries a remote repository for a specific ref
 // Returns the commit hash for the ref
 func (c *Client) LsRemote(ctx context.Context, repoURL, ref string) (string, error) {
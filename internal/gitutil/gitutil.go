@@ -17,6 +17,7 @@ type GitContext struct {
 	RepoRoot     string // Absolute path to repository root
 	RepoURL      string // Remote repository URL
 	RelativePath string // Current path relative to repo root
+	Branch       string // Current branch (empty when HEAD is detached)
 }
 
 // DetectContext detects the Git context for the current working directory
@@ -60,11 +61,18 @@ func DetectContextForPath(ctx context.Context, path string) (*GitContext, error)
 		relativePath = "."
 	}
 
+	// A detached HEAD (or a repository without commits) has no branch
+	branch, err := GetCurrentBranch(ctx, repoRoot)
+	if err != nil || branch == "HEAD" {
+		branch = ""
+	}
+
 	return &GitContext{
 		IsRepo:       true,
 		RepoRoot:     repoRoot,
 		RepoURL:      repoURL,
 		RelativePath: relativePath,
+		Branch:       branch,
 	}, nil
 }
 
@@ -105,6 +113,16 @@ func GetCurrentBranch(ctx context.Context, repoPath string) (string, error) {
 	return gitClient.GetCurrentBranch(ctx, repoPath)
 }
 
+// GetUserEmail returns the configured git user.email for the current directory
+func GetUserEmail(ctx context.Context) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "config", "user.email")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git config user.email failed: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 // GetCurrentCommit returns the current commit SHA
 func GetCurrentCommit(ctx context.Context, repoPath string) (string, error) {
 	cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
@@ -118,6 +136,24 @@ func GetCurrentCommit(ctx context.Context, repoPath string) (string, error) {
 	return commit, nil
 }
 
+// ListFiles returns the paths of the files tracked in the repository
+func ListFiles(ctx context.Context, repoPath string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "ls-files", "-z")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files failed: %w", err)
+	}
+
+	var files []string
+	for _, file := range strings.Split(string(output), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
 // HasUncommittedChanges checks if there are uncommitted changes in the repository
 func HasUncommittedChanges(ctx context.Context, repoPath string) (bool, error) {
 	cmd := exec.CommandContext(ctx, "git", "status", "--porcelain")
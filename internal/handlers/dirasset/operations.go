@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/journal"
 	"github.com/sleuth-io/sx/internal/metadata"
 	"github.com/sleuth-io/sx/internal/utils"
 )
@@ -33,6 +34,11 @@ func NewOperations(subdir string, expectedType *asset.Type) *Operations {
 func (o *Operations) Install(ctx context.Context, zipData []byte, targetBase string, assetName string) error {
 	assetDir := filepath.Join(targetBase, o.subdir, assetName)
 
+	// Snapshot existing installation so the install can be rolled back
+	if err := journal.Track(assetDir); err != nil {
+		return err
+	}
+
 	// Remove existing installation if present
 	if utils.IsDirectory(assetDir) {
 		if err := os.RemoveAll(assetDir); err != nil {
@@ -62,6 +68,10 @@ func (o *Operations) Remove(ctx context.Context, targetBase string, assetName st
 		return nil
 	}
 
+	if err := journal.Track(assetDir); err != nil {
+		return err
+	}
+
 	if err := os.RemoveAll(assetDir); err != nil {
 		return fmt.Errorf("failed to remove asset: %w", err)
 	}
@@ -0,0 +1,105 @@
+package fileasset
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sleuth-io/sx/internal/clients"
+	"github.com/sleuth-io/sx/internal/journal"
+	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/utils"
+)
+
+// dangerousMarker keeps the preamble from being added twice
+const dangerousMarker = "<!-- sx:dangerous -->"
+
+// dangerousPreamble is prepended to the prompt of commands marked dangerous
+const dangerousPreamble = dangerousMarker + `
+> **This command makes changes that are hard to undo.** Before taking any
+> action, summarize exactly what you are about to do and ask the user to
+> confirm. Stop if they don't explicitly agree.
+
+`
+
+// WithDangerousPreamble adds the confirmation preamble to a command prompt,
+// after its frontmatter if it has any
+func WithDangerousPreamble(prompt []byte) []byte {
+	if bytes.Contains(prompt, []byte(dangerousMarker)) {
+		return prompt
+	}
+
+	_, body, _ := splitFrontmatter(prompt)
+	frontmatter := prompt[:len(prompt)-len(body)]
+
+	result := make([]byte, 0, len(prompt)+len(dangerousPreamble))
+	result = append(result, frontmatter...)
+	result = append(result, dangerousPreamble...)
+	return append(result, body...)
+}
+
+// aliasMarker identifies the alias files of a command, so they can be found
+// again on removal without the command's metadata
+func aliasMarker(name string) string {
+	return fmt.Sprintf("<!-- sx:alias-of %s -->", name)
+}
+
+// InstallAliases writes an entry point for each alias in commandsDir that
+// points to the command's prompt at promptPath rather than copying it
+// An alias whose file already exists and isn't one of this command's aliases is
+// skipped with a warning, so it never replaces another command.
+func InstallAliases(ctx context.Context, commandsDir, name, promptPath string, aliases []string) error {
+	for _, alias := range aliases {
+		if alias == name {
+			continue
+		}
+		aliasPath := filepath.Join(commandsDir, alias+".md")
+		if existing, err := os.ReadFile(aliasPath); err == nil && !bytes.Contains(existing, []byte(aliasMarker(name))) {
+			clients.Warn(ctx, fmt.Sprintf("Skipping alias /%s for /%s: %s already exists", alias, name, aliasPath))
+			logger.Get().Warn("alias conflicts with an existing command", "alias", alias, "command", name, "path", aliasPath)
+			continue
+		}
+		if err := journal.Track(aliasPath); err != nil {
+			return err
+		}
+		content := fmt.Sprintf("Alias for /%s: read `%s` and follow its instructions, using these arguments: $ARGUMENTS\n\n%s\n",
+			name, promptPath, aliasMarker(name))
+		if err := os.WriteFile(aliasPath, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write alias %s: %w", alias, err)
+		}
+	}
+	return nil
+}
+
+// RemoveAliases removes the alias files of a command from commandsDir
+func RemoveAliases(commandsDir, name string) error {
+	if !utils.IsDirectory(commandsDir) {
+		return nil
+	}
+	entries, err := os.ReadDir(commandsDir)
+	if err != nil {
+		return fmt.Errorf("failed to read commands directory: %w", err)
+	}
+
+	marker := []byte(aliasMarker(name))
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+		path := filepath.Join(commandsDir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil || !bytes.Contains(content, marker) {
+			continue
+		}
+		if err := journal.Track(path); err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove alias %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package fileasset
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/clients"
+)
+
+func TestWithDangerousPreamble(t *testing.T) {
+	prompt := []byte("---\ndescription: Drop the database\n---\nDrop it.\n")
+
+	got := string(WithDangerousPreamble(prompt))
+	if !strings.HasPrefix(got, "---\ndescription: Drop the database\n---\n"+dangerousMarker) {
+		t.Errorf("preamble should follow the frontmatter, got:\n%s", got)
+	}
+	if !strings.HasSuffix(got, "Drop it.\n") {
+		t.Errorf("prompt body should be kept, got:\n%s", got)
+	}
+
+	// Adding it again is a no-op
+	if again := string(WithDangerousPreamble([]byte(got))); again != got {
+		t.Errorf("preamble added twice:\n%s", again)
+	}
+
+	plain := string(WithDangerousPreamble([]byte("Drop it.\n")))
+	if !strings.HasPrefix(plain, dangerousMarker) {
+		t.Errorf("preamble should start a prompt without frontmatter, got:\n%s", plain)
+	}
+}
+
+func TestInstallAndRemoveAliases(t *testing.T) {
+	dir := t.TempDir()
+	promptPath := filepath.Join(dir, "deploy.md")
+	if err := os.WriteFile(promptPath, []byte("Deploy."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	other := filepath.Join(dir, "ship-it.md")
+	if err := os.WriteFile(other, []byte("Someone else's command"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, warnings := clients.WithWarnings(context.Background())
+	if err := InstallAliases(ctx, dir, "deploy", promptPath, []string{"ship", "deploy", "ship-it"}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(other); string(data) != "Someone else's command" {
+		t.Error("an alias must not overwrite an existing command")
+	}
+	if got := warnings(); len(got) != 1 || !strings.Contains(got[0], "ship-it") {
+		t.Errorf("expected a warning for the skipped alias, got %v", got)
+	}
+
+	// Reinstalling updates the command's own aliases
+	if err := InstallAliases(ctx, dir, "deploy", promptPath, []string{"ship"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := warnings(); len(got) != 1 {
+		t.Errorf("the command's own alias should be rewritten without a warning, got %v", got)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "ship.md"))
+	if err != nil {
+		t.Fatalf("alias not written: %v", err)
+	}
+	if !strings.Contains(string(content), promptPath) {
+		t.Errorf("alias should point to the prompt, got:\n%s", content)
+	}
+	if data, _ := os.ReadFile(promptPath); string(data) != "Deploy." {
+		t.Error("an alias matching the command name must not overwrite it")
+	}
+
+	if err := RemoveAliases(dir, "deploy"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ship.md")); !os.IsNotExist(err) {
+		t.Error("alias should be removed")
+	}
+	for _, keep := range []string{promptPath, other} {
+		if _, err := os.Stat(keep); err != nil {
+			t.Errorf("%s should be left alone", keep)
+		}
+	}
+}
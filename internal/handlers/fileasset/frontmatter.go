@@ -0,0 +1,126 @@
+package fileasset
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Field is a frontmatter key and value
+type Field struct {
+	Key   string
+	Value string
+	// IfMissing only sets the field when the prompt doesn't already have it
+	IfMissing bool
+}
+
+// ErrInvalidFrontmatter is returned by SetFrontmatter, along with the prompt
+// unchanged, when the prompt's frontmatter can't be parsed as a YAML mapping
+var ErrInvalidFrontmatter = errors.New("frontmatter is not valid YAML")
+
+// SetFrontmatter sets fields in a prompt's YAML frontmatter, creating it if the
+// prompt has none. Fields with empty values are skipped, and keys already in the
+// prompt keep their position. The frontmatter is only parsed when a field needs
+// setting, since hand-written headers aren't always strict YAML; if it doesn't
+// parse, the prompt is returned unchanged with ErrInvalidFrontmatter.
+func SetFrontmatter(prompt []byte, fields []Field) ([]byte, error) {
+	header, body, found := splitFrontmatter(prompt)
+	present := headerKeys(header)
+
+	var set []Field
+	for _, field := range fields {
+		if field.Value != "" && !(field.IfMissing && present[field.Key]) {
+			set = append(set, field)
+		}
+	}
+	if len(set) == 0 {
+		return prompt, nil
+	}
+
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	if found {
+		var doc yaml.Node
+		if err := yaml.Unmarshal(header, &doc); err != nil {
+			return prompt, fmt.Errorf("%w: %v", ErrInvalidFrontmatter, err)
+		}
+		if len(doc.Content) > 0 {
+			if doc.Content[0].Kind != yaml.MappingNode {
+				return prompt, fmt.Errorf("%w: not a mapping", ErrInvalidFrontmatter)
+			}
+			mapping = doc.Content[0]
+		}
+	}
+
+	for _, field := range set {
+		value := &yaml.Node{Kind: yaml.ScalarNode, Value: field.Value}
+		if i := keyIndex(mapping, field.Key); i >= 0 {
+			if !field.IfMissing {
+				mapping.Content[i+1] = value
+			}
+			continue
+		}
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: field.Key}, value)
+	}
+
+	rendered, err := yaml.Marshal(mapping)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render frontmatter: %w", err)
+	}
+
+	var result bytes.Buffer
+	result.WriteString("---\n")
+	result.Write(rendered)
+	result.WriteString("---\n")
+	result.Write(body)
+	return result.Bytes(), nil
+}
+
+// StripFrontmatter returns the prompt without its frontmatter, for clients that
+// render their own
+func StripFrontmatter(prompt []byte) []byte {
+	_, body, _ := splitFrontmatter(prompt)
+	return body
+}
+
+// splitFrontmatter separates a leading --- delimited block from the prompt body
+func splitFrontmatter(prompt []byte) (header, body []byte, found bool) {
+	if !bytes.HasPrefix(prompt, []byte("---\n")) {
+		return nil, prompt, false
+	}
+	rest := prompt[4:]
+	if bytes.HasPrefix(rest, []byte("---\n")) {
+		return nil, rest[4:], true
+	}
+	end := bytes.Index(rest, []byte("\n---\n"))
+	if end < 0 {
+		return nil, prompt, false
+	}
+	return rest[:end+1], rest[end+len("\n---\n"):], true
+}
+
+// headerKeys returns the top-level keys of a frontmatter block without parsing it
+func headerKeys(header []byte) map[string]bool {
+	keys := make(map[string]bool)
+	for _, line := range strings.Split(string(header), "\n") {
+		if line == "" || line[0] == ' ' || line[0] == '\t' || line[0] == '#' || line[0] == '-' {
+			continue
+		}
+		if key, _, ok := strings.Cut(line, ":"); ok {
+			keys[strings.TrimSpace(key)] = true
+		}
+	}
+	return keys
+}
+
+// keyIndex returns the index of key in a mapping node's content, or -1
+func keyIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
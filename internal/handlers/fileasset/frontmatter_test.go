@@ -0,0 +1,82 @@
+package fileasset
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetFrontmatter(t *testing.T) {
+	tests := []struct {
+		name   string
+		prompt string
+		fields []Field
+		want   string
+	}{
+		{
+			name:   "creates frontmatter",
+			prompt: "Review the PR.\n",
+			fields: []Field{
+				{Key: "description", Value: "Review a pull request"},
+				{Key: "argument-hint", Value: "[pr-number]"},
+				{Key: "model", Value: ""},
+			},
+			want: "---\ndescription: Review a pull request\nargument-hint: '[pr-number]'\n---\nReview the PR.\n",
+		},
+		{
+			name:   "keeps existing keys in place",
+			prompt: "---\nname: reviewer\ndescription: Mine\nmodel: opus\n---\nReview.\n",
+			fields: []Field{
+				{Key: "description", Value: "From metadata", IfMissing: true},
+				{Key: "model", Value: "sonnet"},
+				{Key: "tools", Value: "Read, Grep"},
+			},
+			want: "---\nname: reviewer\ndescription: Mine\nmodel: sonnet\ntools: Read, Grep\n---\nReview.\n",
+		},
+		{
+			name:   "no fields leaves the prompt alone",
+			prompt: "Review.\n",
+			fields: []Field{{Key: "model", Value: ""}},
+			want:   "Review.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SetFrontmatter([]byte(tt.prompt), tt.fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("SetFrontmatter() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetFrontmatterKeepsInvalidYAML(t *testing.T) {
+	// Claude Code reads descriptions like this one, but they aren't valid YAML
+	prompt := "---\nname: reviewer\ndescription: Use this agent when: reviewing code\n---\nReview.\n"
+
+	got, err := SetFrontmatter([]byte(prompt), []Field{
+		{Key: "name", Value: "reviewer", IfMissing: true},
+		{Key: "description", Value: "From metadata", IfMissing: true},
+	})
+	if err != nil {
+		t.Fatalf("fields already present shouldn't need parsing: %v", err)
+	}
+	if string(got) != prompt {
+		t.Errorf("prompt should be unchanged, got:\n%s", got)
+	}
+
+	got, err = SetFrontmatter([]byte(prompt), []Field{{Key: "model", Value: "opus"}})
+	if !errors.Is(err, ErrInvalidFrontmatter) {
+		t.Errorf("expected ErrInvalidFrontmatter, got %v", err)
+	}
+	if string(got) != prompt {
+		t.Errorf("original prompt should be returned, got:\n%s", got)
+	}
+
+	if _, err := SetFrontmatter([]byte("---\n- a list\n---\nBody\n"), []Field{{Key: "model", Value: "opus"}}); !errors.Is(err, ErrInvalidFrontmatter) {
+		t.Errorf("expected ErrInvalidFrontmatter for frontmatter that isn't a mapping, got %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/journal"
 	"github.com/sleuth-io/sx/internal/metadata"
 	"github.com/sleuth-io/sx/internal/utils"
 )
@@ -39,9 +40,24 @@ func (o *Operations) Install(ctx context.Context, zipData []byte, targetBase str
 		return fmt.Errorf("failed to read prompt file from zip: %w", err)
 	}
 
+	return o.InstallPrompt(ctx, zipData, targetBase, assetName, promptData)
+}
+
+// InstallPrompt installs promptData as {targetBase}/{subdir}/{name}.md, for
+// handlers that adjust the prompt before installing it. The companion metadata
+// file is still taken from the zip.
+func (o *Operations) InstallPrompt(ctx context.Context, zipData []byte, targetBase string, assetName string, promptData []byte) error {
 	// Determine installation path
 	installPath := o.GetAssetPath(targetBase, assetName)
 
+	// Snapshot existing files so the install can be rolled back
+	if err := journal.Track(installPath); err != nil {
+		return err
+	}
+	if err := journal.Track(o.getMetadataPath(installPath)); err != nil {
+		return err
+	}
+
 	// Ensure parent directory exists
 	if err := utils.EnsureDir(filepath.Dir(installPath)); err != nil {
 		return fmt.Errorf("failed to create %s directory: %w", o.subdir, err)
@@ -69,6 +85,13 @@ func (o *Operations) Remove(ctx context.Context, targetBase string, assetName st
 		return nil
 	}
 
+	if err := journal.Track(installPath); err != nil {
+		return err
+	}
+	if err := journal.Track(o.getMetadataPath(installPath)); err != nil {
+		return err
+	}
+
 	if err := os.Remove(installPath); err != nil {
 		return fmt.Errorf("failed to remove asset: %w", err)
 	}
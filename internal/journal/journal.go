@@ -0,0 +1,385 @@
+package journal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/sleuth-io/sx/internal/cache"
+	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/utils"
+)
+
+const (
+	// pendingDir holds the journal of the install currently in progress
+	pendingDir = "pending"
+	// lastDir holds the journal of the last committed install (used by sx rollback)
+	lastDir = "last"
+
+	journalFile = "journal.json"
+	filesDir    = "files"
+	lockFile    = "install.lock"
+
+	// lockWait is how long Begin waits for another install to finish
+	lockWait = 30 * time.Second
+)
+
+// ErrInstallInProgress is returned by Begin when another process holds the install lock
+var ErrInstallInProgress = errors.New("another install is in progress")
+
+// Entry records the state of a single path before an install touched it
+type Entry struct {
+	Path    string `json:"path"`
+	Existed bool   `json:"existed"`
+	Backup  string `json:"backup,omitempty"` // Relative to the journal's files directory
+}
+
+// Journal snapshots every path an install modifies so the install can be undone
+type Journal struct {
+	StartedAt time.Time `json:"startedAt"`
+	PID       int       `json:"pid,omitempty"` // Process that wrote the journal
+	Entries   []Entry   `json:"entries"`
+
+	dir  string
+	lock *flock.Flock
+	mu   sync.Mutex
+}
+
+var (
+	active   *Journal
+	activeMu sync.Mutex
+)
+
+// GetJournalDir returns the directory where install journals are stored
+func GetJournalDir() (string, error) {
+	cacheDir, err := cache.GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "journal"), nil
+}
+
+// Begin takes the install lock, starts a new journal and makes it the active one
+// The lock is held until the journal is committed or rolled back, so concurrent
+// installs (e.g. hooks firing in two sessions at once) run one after the other.
+// If a previous install died before it could commit or roll back, its changes
+// are rolled back first.
+func Begin(ctx context.Context) (*Journal, error) {
+	root, err := GetJournalDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get journal directory: %w", err)
+	}
+
+	fileLock, err := acquireLock(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(root, pendingDir)
+	if utils.FileExists(filepath.Join(dir, journalFile)) {
+		log := logger.Get()
+		stale, err := load(dir)
+		if err == nil && stale.PID != os.Getpid() && processAlive(stale.PID) {
+			// Holding the lock means the writer isn't an install using it, but a
+			// live process may still be an older sx mid-install; leave it alone
+			_ = fileLock.Unlock()
+			log.Warn("pending journal belongs to a running process", "path", dir, "pid", stale.PID)
+			return nil, ErrInstallInProgress
+		}
+		log.Warn("found journal from interrupted install, rolling back", "path", dir)
+		if err == nil {
+			if err := stale.restore(); err != nil {
+				log.Error("failed to roll back interrupted install", "error", err)
+			}
+		}
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		_ = fileLock.Unlock()
+		return nil, fmt.Errorf("failed to clear pending journal: %w", err)
+	}
+	if err := utils.EnsureDir(filepath.Join(dir, filesDir)); err != nil {
+		_ = fileLock.Unlock()
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	j := &Journal{
+		StartedAt: time.Now().UTC(),
+		PID:       os.Getpid(),
+		Entries:   []Entry{},
+		dir:       dir,
+		lock:      fileLock,
+	}
+	if err := j.save(); err != nil {
+		_ = fileLock.Unlock()
+		return nil, err
+	}
+
+	activeMu.Lock()
+	active = j
+	activeMu.Unlock()
+
+	return j, nil
+}
+
+// acquireLock takes the exclusive install lock, waiting up to lockWait for
+// another install to release it
+func acquireLock(ctx context.Context, root string) (*flock.Flock, error) {
+	if err := utils.EnsureDir(root); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lockWait)
+	defer cancel()
+
+	fileLock := flock.New(filepath.Join(root, lockFile))
+	locked, err := fileLock.TryLockContext(ctx, 100*time.Millisecond)
+	if err != nil && ctx.Err() == nil {
+		return nil, fmt.Errorf("failed to acquire install lock: %w", err)
+	}
+	if !locked {
+		return nil, ErrInstallInProgress
+	}
+	return fileLock, nil
+}
+
+// Lock takes the install lock for changes made outside an install, such as
+// sx rollback, and returns a function that releases it
+func Lock(ctx context.Context) (func(), error) {
+	root, err := GetJournalDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get journal directory: %w", err)
+	}
+	fileLock, err := acquireLock(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	return func() { _ = fileLock.Unlock() }, nil
+}
+
+// Track snapshots path in the active journal before it is modified
+// It is a no-op when no install is in progress, so handlers can call it unconditionally
+func Track(path string) error {
+	activeMu.Lock()
+	j := active
+	activeMu.Unlock()
+
+	if j == nil {
+		return nil
+	}
+	return j.Snapshot(path)
+}
+
+// Snapshot records the current state of path (a file or directory)
+// Paths already covered by an earlier snapshot are skipped
+func (j *Journal) Snapshot(path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, e := range j.Entries {
+		if absPath == e.Path || strings.HasPrefix(absPath, e.Path+string(filepath.Separator)) {
+			return nil
+		}
+	}
+
+	entry := Entry{Path: absPath}
+	if _, err := os.Lstat(absPath); err == nil {
+		entry.Existed = true
+		entry.Backup = strconv.Itoa(len(j.Entries))
+		if err := copyPath(absPath, filepath.Join(j.dir, filesDir, entry.Backup)); err != nil {
+			return fmt.Errorf("failed to snapshot %s: %w", absPath, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to stat %s: %w", absPath, err)
+	}
+
+	j.Entries = append(j.Entries, entry)
+	return j.save()
+}
+
+// Commit keeps the install's changes and records the journal as the last install
+// so it can later be undone with sx rollback
+func (j *Journal) Commit() error {
+	j.deactivate()
+
+	root := filepath.Dir(j.dir)
+
+	// An install that touched nothing should not replace the previous rollback point
+	if len(j.Entries) == 0 {
+		return os.RemoveAll(j.dir)
+	}
+
+	last := filepath.Join(root, lastDir)
+	if err := os.RemoveAll(last); err != nil {
+		return fmt.Errorf("failed to remove previous journal: %w", err)
+	}
+	if err := os.Rename(j.dir, last); err != nil {
+		return fmt.Errorf("failed to commit journal: %w", err)
+	}
+	j.dir = last
+	return nil
+}
+
+// Rollback restores every tracked path to its state before the install
+func (j *Journal) Rollback() error {
+	j.deactivate()
+
+	if err := j.restore(); err != nil {
+		return err
+	}
+	return os.RemoveAll(j.dir)
+}
+
+// Discard drops the journal without restoring anything, keeping the previous
+// rollback point. Used when an install made no changes worth undoing.
+func (j *Journal) Discard() error {
+	j.deactivate()
+	return os.RemoveAll(j.dir)
+}
+
+// LoadLast loads the journal of the last committed install
+// Returns nil if there is nothing to roll back
+func LoadLast() (*Journal, error) {
+	root, err := GetJournalDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get journal directory: %w", err)
+	}
+
+	dir := filepath.Join(root, lastDir)
+	if !utils.FileExists(filepath.Join(dir, journalFile)) {
+		return nil, nil
+	}
+	return load(dir)
+}
+
+// deactivate clears the active journal if it is this one and releases the install lock
+func (j *Journal) deactivate() {
+	activeMu.Lock()
+	if active == j {
+		active = nil
+	}
+	activeMu.Unlock()
+
+	if j.lock != nil {
+		_ = j.lock.Unlock()
+		j.lock = nil
+	}
+}
+
+// restore puts tracked paths back in reverse order so later, broader snapshots
+// are undone before the earlier ones they contain
+func (j *Journal) restore() error {
+	var errs []string
+	for i := len(j.Entries) - 1; i >= 0; i-- {
+		e := j.Entries[i]
+		if err := os.RemoveAll(e.Path); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", e.Path, err))
+			continue
+		}
+		if !e.Existed {
+			continue
+		}
+		if err := utils.EnsureDir(filepath.Dir(e.Path)); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", e.Path, err))
+			continue
+		}
+		if err := copyPath(filepath.Join(j.dir, filesDir, e.Backup), e.Path); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", e.Path, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to restore %d path(s):\n  %s", len(errs), strings.Join(errs, "\n  "))
+	}
+	return nil
+}
+
+// save writes the journal index so an interrupted install can be recovered
+func (j *Journal) save() error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(j.dir, journalFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+// load reads a journal from dir
+func load(dir string) (*Journal, error) {
+	data, err := os.ReadFile(filepath.Join(dir, journalFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	var j Journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("failed to parse journal: %w", err)
+	}
+	j.dir = dir
+	return &j, nil
+}
+
+// copyPath copies a file, symlink, or directory tree from src to dst, preserving modes
+func copyPath(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, dst)
+
+	case info.IsDir():
+		if err := os.MkdirAll(dst, info.Mode().Perm()); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := copyPath(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		in, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	}
+}
@@ -0,0 +1,233 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestRollbackRestoresTrackedPaths(t *testing.T) {
+	t.Setenv("SX_CACHE_DIR", t.TempDir())
+	target := t.TempDir()
+
+	settings := filepath.Join(target, "settings.json")
+	skillDir := filepath.Join(target, "skills", "my-skill")
+	newFile := filepath.Join(target, "commands", "new.md")
+
+	writeFile(t, settings, `{"hooks":{}}`)
+	writeFile(t, filepath.Join(skillDir, "SKILL.md"), "v1")
+
+	j, err := Begin(context.Background())
+	if err != nil {
+		t.Fatalf("Begin() error: %v", err)
+	}
+
+	// Simulate handlers modifying files
+	for _, p := range []string{settings, skillDir, newFile} {
+		if err := Track(p); err != nil {
+			t.Fatalf("Track(%s) error: %v", p, err)
+		}
+	}
+	writeFile(t, settings, `{"hooks":{"SessionStart":[]}}`)
+	if err := os.RemoveAll(skillDir); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(skillDir, "SKILL.md"), "v2")
+	writeFile(t, filepath.Join(skillDir, "extra.md"), "extra")
+	writeFile(t, newFile, "new")
+
+	// Paths inside an already tracked directory are not snapshotted again
+	if err := Track(filepath.Join(skillDir, "SKILL.md")); err != nil {
+		t.Fatal(err)
+	}
+	if len(j.Entries) != 3 {
+		t.Errorf("expected 3 entries, got %d", len(j.Entries))
+	}
+
+	if err := j.Rollback(); err != nil {
+		t.Fatalf("Rollback() error: %v", err)
+	}
+
+	if got := readFile(t, settings); got != `{"hooks":{}}` {
+		t.Errorf("settings.json = %q, want original", got)
+	}
+	if got := readFile(t, filepath.Join(skillDir, "SKILL.md")); got != "v1" {
+		t.Errorf("SKILL.md = %q, want v1", got)
+	}
+	if _, err := os.Stat(filepath.Join(skillDir, "extra.md")); !os.IsNotExist(err) {
+		t.Error("extra.md should have been removed")
+	}
+	if _, err := os.Stat(newFile); !os.IsNotExist(err) {
+		t.Error("new file should have been removed")
+	}
+
+	// Tracking is inactive after rollback
+	if err := Track(settings); err != nil {
+		t.Fatal(err)
+	}
+	if last, _ := LoadLast(); last != nil {
+		t.Error("rolled back journal should not become the last install")
+	}
+}
+
+func TestCommitKeepsLastInstall(t *testing.T) {
+	t.Setenv("SX_CACHE_DIR", t.TempDir())
+	target := t.TempDir()
+	settings := filepath.Join(target, "settings.json")
+	writeFile(t, settings, "before")
+
+	j, err := Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Track(settings); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, settings, "after")
+	if err := j.Commit(); err != nil {
+		t.Fatalf("Commit() error: %v", err)
+	}
+
+	// An install that changes nothing keeps the previous rollback point
+	empty, err := Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := empty.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	last, err := LoadLast()
+	if err != nil {
+		t.Fatalf("LoadLast() error: %v", err)
+	}
+	if last == nil || len(last.Entries) != 1 {
+		t.Fatalf("expected last journal with 1 entry, got %+v", last)
+	}
+
+	if err := last.Rollback(); err != nil {
+		t.Fatalf("Rollback() error: %v", err)
+	}
+	if got := readFile(t, settings); got != "before" {
+		t.Errorf("settings = %q, want before", got)
+	}
+}
+
+func TestBeginRecoversInterruptedInstall(t *testing.T) {
+	t.Setenv("SX_CACHE_DIR", t.TempDir())
+	target := t.TempDir()
+	settings := filepath.Join(target, "settings.json")
+	writeFile(t, settings, "before")
+
+	j, err := Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Snapshot(settings); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, settings, "half-written")
+	j.deactivate() // Simulate the process dying without commit or rollback
+
+	if _, err := Begin(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, settings); got != "before" {
+		t.Errorf("settings = %q, want before", got)
+	}
+}
+
+func TestBeginWaitsForConcurrentInstall(t *testing.T) {
+	t.Setenv("SX_CACHE_DIR", t.TempDir())
+
+	j, err := Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The install lock is held until the first journal finishes
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if _, err := Begin(ctx); !errors.Is(err, ErrInstallInProgress) {
+		t.Fatalf("Begin() while locked = %v, want ErrInstallInProgress", err)
+	}
+
+	if err := j.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	next, err := Begin(context.Background())
+	if err != nil {
+		t.Fatalf("Begin() after commit error: %v", err)
+	}
+	_ = next.Commit()
+}
+
+func TestBeginLeavesJournalOfRunningProcess(t *testing.T) {
+	t.Setenv("SX_CACHE_DIR", t.TempDir())
+	target := t.TempDir()
+	settings := filepath.Join(target, "settings.json")
+	writeFile(t, settings, "before")
+
+	// Start a process to stand in for an older sx that installs without the lock
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+	cmd.Env = append(os.Environ(), "SX_JOURNAL_HELPER=1")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	j, err := Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Snapshot(settings); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, settings, "in progress")
+	j.PID = cmd.Process.Pid
+	if err := j.save(); err != nil {
+		t.Fatal(err)
+	}
+	j.deactivate()
+
+	if _, err := Begin(context.Background()); !errors.Is(err, ErrInstallInProgress) {
+		t.Fatalf("Begin() = %v, want ErrInstallInProgress", err)
+	}
+	if got := readFile(t, settings); got != "in progress" {
+		t.Errorf("settings = %q, the running install should not be rolled back", got)
+	}
+}
+
+// TestHelperProcess is not a real test; it idles as a live process for other tests
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("SX_JOURNAL_HELPER") != "1" {
+		return
+	}
+	time.Sleep(time.Minute)
+}
@@ -0,0 +1,213 @@
+// Package languages detects the programming languages a repository uses, so
+// skills declaring supported-languages can be limited to repos that use them.
+package languages
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sleuth-io/sx/internal/cache"
+	"github.com/sleuth-io/sx/internal/gitutil"
+	"github.com/sleuth-io/sx/internal/logger"
+)
+
+// markers are project files that identify a language on their own
+var markers = map[string]string{
+	"go.mod":           "go",
+	"package.json":     "javascript",
+	"tsconfig.json":    "typescript",
+	"pyproject.toml":   "python",
+	"requirements.txt": "python",
+	"setup.py":         "python",
+	"Pipfile":          "python",
+	"Cargo.toml":       "rust",
+	"Gemfile":          "ruby",
+	"pom.xml":          "java",
+	"build.gradle":     "java",
+	"build.gradle.kts": "kotlin",
+	"composer.json":    "php",
+	"mix.exs":          "elixir",
+	"Package.swift":    "swift",
+	"pubspec.yaml":     "dart",
+}
+
+// extensions maps source file extensions to languages
+var extensions = map[string]string{
+	".go":     "go",
+	".js":     "javascript",
+	".jsx":    "javascript",
+	".mjs":    "javascript",
+	".cjs":    "javascript",
+	".ts":     "typescript",
+	".tsx":    "typescript",
+	".py":     "python",
+	".rs":     "rust",
+	".rb":     "ruby",
+	".java":   "java",
+	".kt":     "kotlin",
+	".kts":    "kotlin",
+	".scala":  "scala",
+	".php":    "php",
+	".cs":     "csharp",
+	".fs":     "fsharp",
+	".c":      "c",
+	".h":      "c",
+	".cc":     "cpp",
+	".cpp":    "cpp",
+	".cxx":    "cpp",
+	".hpp":    "cpp",
+	".swift":  "swift",
+	".m":      "objective-c",
+	".ex":     "elixir",
+	".exs":    "elixir",
+	".erl":    "erlang",
+	".hs":     "haskell",
+	".clj":    "clojure",
+	".dart":   "dart",
+	".lua":    "lua",
+	".sh":     "shell",
+	".sql":    "sql",
+	".vue":    "vue",
+	".svelte": "svelte",
+	".tf":     "terraform",
+}
+
+// aliases are other names skills use for a language
+var aliases = map[string]string{
+	"golang":  "go",
+	"js":      "javascript",
+	"node":    "javascript",
+	"nodejs":  "javascript",
+	"ts":      "typescript",
+	"py":      "python",
+	"python3": "python",
+	"rb":      "ruby",
+	"c++":     "cpp",
+	"c#":      "csharp",
+	"f#":      "fsharp",
+	"bash":    "shell",
+	"objc":    "objective-c",
+	"kt":      "kotlin",
+	"rs":      "rust",
+	"hcl":     "terraform",
+}
+
+// cacheEntry is the detected languages of a repository at a commit
+type cacheEntry struct {
+	Commit    string   `json:"commit"`
+	Languages []string `json:"languages"`
+}
+
+// Detect returns the languages used by the repository at repoRoot, from its
+// tracked files. Results are cached per commit.
+func Detect(ctx context.Context, repoRoot string) ([]string, error) {
+	log := logger.Get()
+
+	commit, err := gitutil.GetCurrentCommit(ctx, repoRoot)
+	if err != nil {
+		// No commits yet, nothing to key the cache on
+		commit = ""
+	}
+	cacheFile := cachePath(repoRoot)
+	if commit != "" && cacheFile != "" {
+		if entry, err := loadCache(cacheFile); err == nil && entry.Commit == commit {
+			return entry.Languages, nil
+		}
+	}
+
+	files, err := gitutil.ListFiles(ctx, repoRoot)
+	if err != nil {
+		return nil, err
+	}
+	languages := detect(files)
+
+	if commit != "" && cacheFile != "" {
+		if err := saveCache(cacheFile, &cacheEntry{Commit: commit, Languages: languages}); err != nil {
+			log.Debug("failed to cache detected languages", "repo", repoRoot, "error", err)
+		}
+	}
+	return languages, nil
+}
+
+// detect works out the languages from a list of file paths
+func detect(files []string) []string {
+	found := make(map[string]bool)
+	for _, file := range files {
+		base := path.Base(file)
+		if lang, ok := markers[base]; ok {
+			found[lang] = true
+		}
+		if lang, ok := extensions[strings.ToLower(path.Ext(base))]; ok {
+			found[lang] = true
+		}
+	}
+
+	languages := make([]string, 0, len(found))
+	for lang := range found {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Normalize returns the canonical name of a language, e.g. "golang" is "go"
+func Normalize(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if canonical, ok := aliases[language]; ok {
+		return canonical
+	}
+	return language
+}
+
+// Matches checks if any of the supported languages is among the detected ones
+func Matches(supported, detected []string) bool {
+	for _, s := range supported {
+		for _, d := range detected {
+			if Normalize(s) == Normalize(d) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cachePath returns where the languages of repoRoot are cached, or "" if the
+// cache directory is unavailable
+func cachePath(repoRoot string) string {
+	dir, err := cache.GetLanguageCacheDir()
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(repoRoot))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".json")
+}
+
+func loadCache(file string) (*cacheEntry, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func saveCache(file string, entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0644)
+}
@@ -0,0 +1,92 @@
+package languages
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	files := []string{
+		"pyproject.toml",
+		"src/app/main.py",
+		"web/package.json",
+		"web/src/index.tsx",
+		"docs/README.md",
+	}
+	want := []string{"javascript", "python", "typescript"}
+	if got := detect(files); !reflect.DeepEqual(got, want) {
+		t.Errorf("detect() = %v, want %v", got, want)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	detected := []string{"go", "python"}
+	tests := []struct {
+		supported []string
+		want      bool
+	}{
+		{[]string{"rust"}, false},
+		{[]string{"rust", "Python"}, true},
+		{[]string{"golang"}, true},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := Matches(tt.supported, detected); got != tt.want {
+			t.Errorf("Matches(%v) = %v, want %v", tt.supported, got, tt.want)
+		}
+	}
+}
+
+func TestDetectCachesPerCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.email=test@example.com", "-c", "user.name=test"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q")
+	write("main.go")
+	git("add", ".")
+	git("commit", "-q", "-m", "go")
+
+	ctx := context.Background()
+	got, err := Detect(ctx, repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []string{"go"}) {
+		t.Errorf("Detect() = %v, want [go]", got)
+	}
+
+	// A new commit invalidates the cached result
+	write("lib.rs")
+	git("add", ".")
+	git("commit", "-q", "-m", "rust")
+	got, err = Detect(ctx, repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []string{"go", "rust"}) {
+		t.Errorf("Detect() after commit = %v, want [go rust]", got)
+	}
+}
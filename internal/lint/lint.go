@@ -0,0 +1,443 @@
+// Package lint checks packaged assets for problems beyond metadata structure:
+// prompt frontmatter, @file references, hook scripts, MCP commands, tool
+// requirements, prompt size, naming, and dependency constraints.
+package lint
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"gopkg.in/yaml.v3"
+
+	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/metadata"
+	"github.com/sleuth-io/sx/internal/utils"
+)
+
+// Severity of a finding
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rule IDs
+const (
+	RuleMetadata      = "metadata"
+	RuleNaming        = "naming"
+	RuleMissingFile   = "missing-file"
+	RuleFrontmatter   = "frontmatter"
+	RuleFileReference = "file-reference"
+	RuleHookScript    = "hook-script"
+	RuleMCPCommand    = "mcp-command"
+	RuleRequires      = "requires"
+	RulePromptSize    = "prompt-size"
+	RuleDependency    = "dependency"
+)
+
+// Rule describes a check
+type Rule struct {
+	ID          string
+	Description string
+}
+
+// Rules lists every check in reporting order
+var Rules = []Rule{
+	{RuleMetadata, "metadata.toml is present, parses, and is structurally valid"},
+	{RuleNaming, "Asset names are lowercase and dash-separated"},
+	{RuleMissingFile, "Files referenced by metadata.toml are packaged"},
+	{RuleFrontmatter, "SKILL.md frontmatter name and description match metadata.toml"},
+	{RuleFileReference, "@file references in prompts point to packaged files"},
+	{RuleHookScript, "Hook scripts are executable and start with a shebang"},
+	{RuleMCPCommand, "MCP commands are packaged or declared in [mcp] requires"},
+	{RuleRequires, "Tool requirements are well formed"},
+	{RulePromptSize, "Prompt files stay within the size budget"},
+	{RuleDependency, "Dependency constraints are valid and resolve in the vault"},
+}
+
+// DefaultMaxPromptBytes is the prompt size above which a warning is raised (roughly 10k tokens)
+const DefaultMaxPromptBytes = 40 * 1024
+
+// kebabRegex matches the recommended asset name style
+var kebabRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Finding is a single problem found in an asset
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	// Source is where the asset was read from (a directory, zip, or name@version)
+	Source string `json:"source"`
+	// Asset is name@version, when metadata.toml could be read
+	Asset string `json:"asset,omitempty"`
+	// File is the path within the asset, if the finding is about a file
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+// Location returns the finding's file path for display, relative to where the asset was read from
+func (f Finding) Location() string {
+	if f.File == "" {
+		return f.Source
+	}
+	return path.Join(f.Source, f.File)
+}
+
+// VersionLister returns the versions of an asset available in the vault
+type VersionLister func(name string) ([]string, error)
+
+// Options configure a check
+type Options struct {
+	// MaxPromptBytes overrides DefaultMaxPromptBytes when positive
+	MaxPromptBytes int
+	// Versions resolves dependency constraints; dependencies are only syntax-checked when nil
+	Versions VersionLister
+}
+
+// Report collects the findings for a set of assets
+type Report struct {
+	Assets   int       `json:"assets"`
+	Errors   int       `json:"errors"`
+	Warnings int       `json:"warnings"`
+	Findings []Finding `json:"findings"`
+}
+
+// Add records the findings for one checked asset
+func (r *Report) Add(findings []Finding) {
+	r.Assets++
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			r.Errors++
+		} else {
+			r.Warnings++
+		}
+	}
+	r.Findings = append(r.Findings, findings...)
+}
+
+// HasErrors reports whether any finding is an error
+func (r *Report) HasErrors() bool {
+	return r.Errors > 0
+}
+
+// checker accumulates findings for one asset
+type checker struct {
+	source   string
+	asset    string
+	files    map[string]*zip.File
+	opts     Options
+	findings []Finding
+}
+
+func (c *checker) add(rule string, severity Severity, file string, line int, format string, args ...any) {
+	c.findings = append(c.findings, Finding{
+		Rule:     rule,
+		Severity: severity,
+		Source:   c.source,
+		Asset:    c.asset,
+		File:     file,
+		Line:     line,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *checker) read(name string) (string, bool) {
+	file, ok := c.files[name]
+	if !ok {
+		return "", false
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return "", false
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// Check lints a packaged asset. source labels the findings.
+func Check(zipData []byte, source string, opts Options) []Finding {
+	c := &checker{source: source, opts: opts, files: make(map[string]*zip.File)}
+
+	reader, err := zip.NewReader(bytes.NewReader(zipData), int64(len(zipData)))
+	if err != nil {
+		c.add(RuleMetadata, SeverityError, "", 0, "not a valid asset archive: %v", err)
+		return c.findings
+	}
+	for _, file := range reader.File {
+		if !file.FileInfo().IsDir() {
+			c.files[path.Clean(file.Name)] = file
+		}
+	}
+
+	data, ok := c.read("metadata.toml")
+	if !ok {
+		c.add(RuleMetadata, SeverityError, "metadata.toml", 0, "metadata.toml is missing")
+		return c.findings
+	}
+	meta, err := metadata.Parse([]byte(data))
+	if err != nil {
+		c.add(RuleMetadata, SeverityError, "metadata.toml", 0, "%v", err)
+		return c.findings
+	}
+	c.asset = meta.Asset.Name + "@" + meta.Asset.Version
+	if err := meta.Validate(); err != nil {
+		c.add(RuleMetadata, SeverityError, "metadata.toml", 0, "%v", err)
+		return c.findings
+	}
+
+	c.checkNaming(meta)
+	c.checkDependencies(meta)
+
+	switch meta.Asset.Type {
+	case asset.TypeSkill:
+		c.checkPrompt(meta.Skill.PromptFile)
+		c.checkSkillFrontmatter(meta)
+		c.checkRequires(meta.Skill.Requires)
+	case asset.TypeCommand:
+		c.checkPrompt(meta.Command.PromptFile)
+	case asset.TypeAgent:
+		c.checkPrompt(meta.Agent.PromptFile)
+		c.checkRequires(meta.Agent.Requires)
+	case asset.TypeHook:
+		c.checkHookScript(meta.Hook.ScriptFile)
+	case asset.TypeMCP, asset.TypeMCPRemote:
+		c.checkRequires(meta.MCP.Requires)
+		if !meta.MCP.IsRemote() {
+			c.checkMCPCommand(meta.MCP)
+		}
+	}
+
+	c.checkFileReferences()
+
+	return c.findings
+}
+
+func (c *checker) checkNaming(meta *metadata.Metadata) {
+	if !kebabRegex.MatchString(meta.Asset.Name) {
+		c.add(RuleNaming, SeverityWarning, "metadata.toml", 0,
+			"name %q should be lowercase words separated by dashes", meta.Asset.Name)
+	}
+}
+
+func (c *checker) checkPrompt(promptFile string) {
+	file, ok := c.files[path.Clean(promptFile)]
+	if !ok {
+		c.add(RuleMissingFile, SeverityError, "metadata.toml", 0, "prompt file %s is not packaged", promptFile)
+		return
+	}
+
+	maxBytes := c.opts.MaxPromptBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxPromptBytes
+	}
+	if size := file.UncompressedSize64; size > uint64(maxBytes) {
+		c.add(RulePromptSize, SeverityWarning, promptFile, 0,
+			"prompt is %d KB (limit %d KB); move detail into referenced files", size/1024, maxBytes/1024)
+	}
+}
+
+func (c *checker) checkSkillFrontmatter(meta *metadata.Metadata) {
+	promptFile := path.Clean(meta.Skill.PromptFile)
+	content, ok := c.read(promptFile)
+	if !ok {
+		return
+	}
+
+	fields, found, err := parseFrontmatter(content)
+	if err != nil {
+		c.add(RuleFrontmatter, SeverityError, promptFile, 1, "invalid frontmatter: %v", err)
+		return
+	}
+	if !found {
+		c.add(RuleFrontmatter, SeverityWarning, promptFile, 1, "no frontmatter; add name and description so clients can discover the skill")
+		return
+	}
+
+	check := func(key, want string) {
+		got, _ := fields[key].(string)
+		line := frontmatterLine(content, key)
+		switch {
+		case got == "":
+			c.add(RuleFrontmatter, SeverityWarning, promptFile, line, "frontmatter has no %s", key)
+		case want != "" && strings.TrimSpace(got) != strings.TrimSpace(want):
+			c.add(RuleFrontmatter, SeverityError, promptFile, line,
+				"frontmatter %s %q doesn't match metadata.toml (%q)", key, got, want)
+		}
+	}
+	check("name", meta.Asset.Name)
+	check("description", meta.Asset.Description)
+}
+
+// checkFileReferences verifies @file references in markdown files resolve to packaged files
+func (c *checker) checkFileReferences() {
+	for _, name := range sortedKeys(c.files) {
+		if !strings.EqualFold(path.Ext(name), ".md") {
+			continue
+		}
+		content, ok := c.read(name)
+		if !ok {
+			continue
+		}
+
+		for _, match := range utils.FileRefPattern.FindAllStringSubmatchIndex(content, -1) {
+			// Skip emails and handles like user@example.com
+			if start := match[0]; start > 0 && !isRefBoundary(content[start-1]) {
+				continue
+			}
+			ref := content[match[2]:match[3]]
+			if c.hasFile(path.Join(path.Dir(name), ref)) || c.hasFile(ref) {
+				continue
+			}
+			c.add(RuleFileReference, SeverityError, name, lineAt(content, match[0]), "@%s doesn't match a packaged file", ref)
+		}
+	}
+}
+
+func (c *checker) checkHookScript(scriptFile string) {
+	name := path.Clean(scriptFile)
+	file, ok := c.files[name]
+	if !ok {
+		c.add(RuleMissingFile, SeverityError, "metadata.toml", 0, "script file %s is not packaged", scriptFile)
+		return
+	}
+
+	if content, ok := c.read(name); ok && !strings.HasPrefix(content, "#!") {
+		c.add(RuleHookScript, SeverityError, name, 1, "script has no shebang line (e.g. #!/usr/bin/env bash)")
+	}
+	if file.Mode().Perm()&0111 == 0 {
+		c.add(RuleHookScript, SeverityError, name, 0, "script is not executable (chmod +x %s)", name)
+	}
+}
+
+func (c *checker) checkMCPCommand(mcp *metadata.MCPConfig) {
+	command := mcp.Command
+	switch {
+	case path.IsAbs(command):
+		c.add(RuleMCPCommand, SeverityWarning, "metadata.toml", 0, "command %s is an absolute path and won't be portable", command)
+	case strings.Contains(command, "/"):
+		if !c.hasFile(command) {
+			c.add(RuleMissingFile, SeverityError, "metadata.toml", 0, "command %s is not packaged", command)
+		}
+	default:
+		for _, req := range mcp.Requires {
+			if tool, _, err := metadata.ParseRequirement(req); err == nil && tool == command {
+				return
+			}
+		}
+		c.add(RuleMCPCommand, SeverityWarning, "metadata.toml", 0,
+			"command %q isn't packaged; declare it in [mcp] requires so missing tools are reported", command)
+	}
+}
+
+func (c *checker) checkRequires(requires []string) {
+	for _, req := range requires {
+		if _, _, err := metadata.ParseRequirement(req); err != nil {
+			c.add(RuleRequires, SeverityError, "metadata.toml", 0, "%v", err)
+		}
+	}
+}
+
+func (c *checker) checkDependencies(meta *metadata.Metadata) {
+	for _, dep := range meta.Asset.Dependencies {
+		name, constraint, err := metadata.ParseDependency(dep)
+		if err != nil {
+			c.add(RuleDependency, SeverityError, "metadata.toml", 0, "%v", err)
+			continue
+		}
+		if err := metadata.ValidateDependencyConstraint(strings.TrimSpace(constraint)); err != nil {
+			c.add(RuleDependency, SeverityError, "metadata.toml", 0, "%s: %v", name, err)
+			continue
+		}
+		if c.opts.Versions == nil {
+			continue
+		}
+
+		versions, err := c.opts.Versions(name)
+		if err != nil || len(versions) == 0 {
+			c.add(RuleDependency, SeverityError, "metadata.toml", 0, "dependency %s is not in the vault", name)
+			continue
+		}
+		if !satisfiable(strings.TrimSpace(constraint), versions) {
+			c.add(RuleDependency, SeverityError, "metadata.toml", 0,
+				"no version of %s satisfies %s (available: %s)", name, constraint, strings.Join(versions, ", "))
+		}
+	}
+}
+
+func (c *checker) hasFile(name string) bool {
+	_, ok := c.files[path.Clean(strings.TrimPrefix(name, "./"))]
+	return ok
+}
+
+func satisfiable(constraint string, versions []string) bool {
+	if constraint == "" {
+		return true
+	}
+	constraints, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false
+	}
+	for _, v := range versions {
+		if version, err := semver.NewVersion(v); err == nil && constraints.Check(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFrontmatter parses a leading YAML block delimited by --- lines
+func parseFrontmatter(content string) (map[string]any, bool, error) {
+	content = strings.ReplaceAll(strings.TrimPrefix(content, "\ufeff"), "\r\n", "\n")
+	if !strings.HasPrefix(content, "---\n") {
+		return nil, false, nil
+	}
+	end := strings.Index(content[4:], "\n---")
+	if end < 0 {
+		return nil, true, fmt.Errorf("missing closing ---")
+	}
+
+	fields := make(map[string]any)
+	if err := yaml.Unmarshal([]byte(content[4:4+end]), &fields); err != nil {
+		return nil, true, err
+	}
+	return fields, true, nil
+}
+
+// frontmatterLine returns the line of key in the frontmatter, or 1
+func frontmatterLine(content, key string) int {
+	for i, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, key+":") {
+			return i + 1
+		}
+	}
+	return 1
+}
+
+func lineAt(content string, offset int) int {
+	return strings.Count(content[:offset], "\n") + 1
+}
+
+func isRefBoundary(b byte) bool {
+	return strings.IndexByte(" \t\n([`\"'", b) >= 0
+}
+
+func sortedKeys(files map[string]*zip.File) []string {
+	keys := make([]string, 0, len(files))
+	for name := range files {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,311 @@
+package lint
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type testFile struct {
+	content string
+	mode    os.FileMode
+}
+
+func buildZip(t *testing.T, files map[string]testFile) []byte {
+	t.Helper()
+
+	buf := new(bytes.Buffer)
+	writer := zip.NewWriter(buf)
+	for name, file := range files {
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate}
+		mode := file.mode
+		if mode == 0 {
+			mode = 0644
+		}
+		header.SetMode(mode)
+		w, err := writer.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(file.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+const skillMetadata = `[asset]
+name = "code-review"
+version = "1.0.0"
+type = "skill"
+description = "Review code"
+
+[skill]
+prompt-file = "SKILL.md"
+`
+
+func rules(findings []Finding) []string {
+	var ids []string
+	for _, f := range findings {
+		ids = append(ids, string(f.Severity)+":"+f.Rule)
+	}
+	return ids
+}
+
+func TestCheckCleanSkill(t *testing.T) {
+	zipData := buildZip(t, map[string]testFile{
+		"metadata.toml":       {content: skillMetadata},
+		"SKILL.md":            {content: "---\nname: code-review\ndescription: Review code\n---\n\nSee @docs/checklist.md and mail review@example.com.\n"},
+		"docs/checklist.md":   {content: "- tests\n"},
+		"docs/unreferenced.a": {content: ""},
+	})
+
+	if findings := Check(zipData, "code-review", Options{}); len(findings) != 0 {
+		t.Errorf("expected no findings, got %+v", findings)
+	}
+}
+
+func TestCheckSkillFrontmatterAndReferences(t *testing.T) {
+	zipData := buildZip(t, map[string]testFile{
+		"metadata.toml": {content: skillMetadata},
+		"SKILL.md":      {content: "---\nname: code-reviewer\ndescription: Review code\n---\n\nUse @missing.md\n"},
+	})
+
+	findings := Check(zipData, "code-review", Options{})
+	got := strings.Join(rules(findings), ",")
+	if got != "error:frontmatter,error:file-reference" {
+		t.Fatalf("findings = %s (%+v)", got, findings)
+	}
+	if findings[0].Line != 2 || findings[1].Line != 6 {
+		t.Errorf("lines = %d, %d; want 2, 6", findings[0].Line, findings[1].Line)
+	}
+	if findings[0].Asset != "code-review@1.0.0" {
+		t.Errorf("Asset = %q", findings[0].Asset)
+	}
+}
+
+func TestCheckSkillWithoutFrontmatter(t *testing.T) {
+	zipData := buildZip(t, map[string]testFile{
+		"metadata.toml": {content: skillMetadata},
+		"SKILL.md":      {content: "# Code review\n"},
+	})
+
+	if got := strings.Join(rules(Check(zipData, "x", Options{})), ","); got != "warning:frontmatter" {
+		t.Errorf("findings = %s", got)
+	}
+}
+
+func TestCheckMissingPromptAndSize(t *testing.T) {
+	missing := buildZip(t, map[string]testFile{"metadata.toml": {content: skillMetadata}})
+	if got := strings.Join(rules(Check(missing, "x", Options{})), ","); got != "error:missing-file" {
+		t.Errorf("missing prompt findings = %s", got)
+	}
+
+	large := buildZip(t, map[string]testFile{
+		"metadata.toml": {content: skillMetadata},
+		"SKILL.md":      {content: "---\nname: code-review\ndescription: Review code\n---\n" + strings.Repeat("x", 2048)},
+	})
+	if got := strings.Join(rules(Check(large, "x", Options{MaxPromptBytes: 1024})), ","); got != "warning:prompt-size" {
+		t.Errorf("large prompt findings = %s", got)
+	}
+}
+
+func TestCheckHookScript(t *testing.T) {
+	meta := `[asset]
+name = "lint"
+version = "1.0.0"
+type = "hook"
+
+[hook]
+event = "pre-commit"
+script-file = "hook.sh"
+`
+	good := buildZip(t, map[string]testFile{
+		"metadata.toml": {content: meta},
+		"hook.sh":       {content: "#!/bin/sh\nexit 0\n", mode: 0755},
+	})
+	if findings := Check(good, "lint", Options{}); len(findings) != 0 {
+		t.Errorf("expected no findings, got %+v", findings)
+	}
+
+	bad := buildZip(t, map[string]testFile{
+		"metadata.toml": {content: meta},
+		"hook.sh":       {content: "exit 0\n"},
+	})
+	if got := strings.Join(rules(Check(bad, "lint", Options{})), ","); got != "error:hook-script,error:hook-script" {
+		t.Errorf("findings = %s", got)
+	}
+}
+
+func TestCheckMCPCommand(t *testing.T) {
+	meta := func(command, requires string) string {
+		return `[asset]
+name = "github"
+version = "1.0.0"
+type = "mcp"
+
+[mcp]
+command = "` + command + `"
+args = ["server.js"]
+requires = [` + requires + `]
+`
+	}
+
+	tests := []struct {
+		name    string
+		meta    string
+		files   map[string]testFile
+		want    string
+		wantMsg string
+	}{
+		{"declared", meta("node", `"node>=18"`), nil, "", ""},
+		{"undeclared", meta("node", ""), nil, "warning:mcp-command", "node"},
+		{"packaged", meta("./bin/server", ""), map[string]testFile{"bin/server": {content: "x", mode: 0755}}, "", ""},
+		{"not packaged", meta("./bin/server", ""), nil, "error:missing-file", "bin/server"},
+		{"bad requirement", meta("node", `"node>=abc"`), nil, "error:requires,warning:mcp-command", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]testFile{"metadata.toml": {content: tt.meta}}
+			for name, file := range tt.files {
+				files[name] = file
+			}
+
+			findings := Check(buildZip(t, files), "github", Options{})
+			if got := strings.Join(rules(findings), ","); got != tt.want {
+				t.Fatalf("findings = %s (%+v)", got, findings)
+			}
+			if tt.wantMsg != "" && !strings.Contains(findings[0].Message, tt.wantMsg) {
+				t.Errorf("message %q doesn't mention %q", findings[0].Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestCheckNamingAndMetadata(t *testing.T) {
+	invalid := buildZip(t, map[string]testFile{"metadata.toml": {content: "[asset]\nname = \"bad name\"\nversion = \"1.0.0\"\ntype = \"skill\"\n"}})
+	if got := strings.Join(rules(Check(invalid, "x", Options{})), ","); got != "error:metadata" {
+		t.Errorf("invalid metadata findings = %s", got)
+	}
+
+	meta := strings.Replace(skillMetadata, `name = "code-review"`, `name = "Code_Review"`, 1)
+	styled := buildZip(t, map[string]testFile{
+		"metadata.toml": {content: meta},
+		"SKILL.md":      {content: "---\nname: Code_Review\ndescription: Review code\n---\n"},
+	})
+	if got := strings.Join(rules(Check(styled, "x", Options{})), ","); got != "warning:naming" {
+		t.Errorf("naming findings = %s", got)
+	}
+}
+
+func TestCheckDependencies(t *testing.T) {
+	meta := strings.Replace(skillMetadata, `description = "Review code"`,
+		"description = \"Review code\"\ndependencies = [\"style-guide>=2.0.0\", \"linter\", \"missing\"]", 1)
+	zipData := buildZip(t, map[string]testFile{
+		"metadata.toml": {content: meta},
+		"SKILL.md":      {content: "---\nname: code-review\ndescription: Review code\n---\n"},
+	})
+
+	if findings := Check(zipData, "x", Options{}); len(findings) != 0 {
+		t.Errorf("without a vault only syntax is checked, got %+v", findings)
+	}
+
+	versions := map[string][]string{"style-guide": {"1.0.0", "1.5.0"}, "linter": {"0.1.0"}}
+	findings := Check(zipData, "x", Options{Versions: func(name string) ([]string, error) {
+		return versions[name], nil
+	}})
+	if got := strings.Join(rules(findings), ","); got != "error:dependency,error:dependency" {
+		t.Fatalf("findings = %s (%+v)", got, findings)
+	}
+	if !strings.Contains(findings[0].Message, "style-guide") || !strings.Contains(findings[1].Message, "missing") {
+		t.Errorf("unexpected messages: %+v", findings)
+	}
+}
+
+func TestDiscoverVaultDirectory(t *testing.T) {
+	root := t.TempDir()
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		dir := filepath.Join(root, "assets", "code-review", version)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		meta := strings.Replace(skillMetadata, "1.0.0", version, 1)
+		if err := os.WriteFile(filepath.Join(dir, "metadata.toml"), []byte(meta), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "assets", "code-review", "list.txt"), []byte("1.0.0\n1.1.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	targets, versions, err := Discover(root)
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("targets = %d, want 2", len(targets))
+	}
+	if listed, _ := versions("code-review"); strings.Join(listed, ",") != "1.0.0,1.1.0" {
+		t.Errorf("versions = %v", listed)
+	}
+
+	zipData, err := targets[0].Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	// The prompt file was never written
+	if got := strings.Join(rules(Check(zipData, targets[0].Source, Options{})), ","); got != "error:missing-file" {
+		t.Errorf("findings = %s", got)
+	}
+}
+
+func TestReportSARIF(t *testing.T) {
+	report := &Report{}
+	report.Add([]Finding{{Rule: RuleFrontmatter, Severity: SeverityError, Source: "skills/code-review", Asset: "code-review@1.0.0", File: "SKILL.md", Line: 2, Message: "mismatch"}})
+	report.Add(nil)
+
+	if report.Assets != 2 || report.Errors != 1 || !report.HasErrors() {
+		t.Errorf("report = %+v", report)
+	}
+
+	data, err := report.SARIF()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &log); err != nil {
+		t.Fatalf("invalid SARIF: %v", err)
+	}
+	result := log.Runs[0].Results[0]
+	location := result.Locations[0].PhysicalLocation
+	if log.Version != "2.1.0" || result.RuleID != "frontmatter" || result.Level != "error" ||
+		location.ArtifactLocation.URI != "skills/code-review/SKILL.md" || location.Region.StartLine != 2 {
+		t.Errorf("unexpected SARIF:\n%s", data)
+	}
+}
@@ -0,0 +1,129 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sleuth-io/sx/internal/utils"
+)
+
+// Target is an asset to check
+type Target struct {
+	// Source labels the target in findings
+	Source string
+	// Load returns the packaged asset
+	Load func() ([]byte, error)
+}
+
+// Discover finds the assets at path: an asset zip, an asset directory (containing
+// metadata.toml), a vault directory (containing assets/<name>/<version>/), or a
+// directory of asset directories. For vault directories it also returns a
+// VersionLister backed by the vault's list.txt files.
+func Discover(root string) ([]Target, VersionLister, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !info.IsDir() {
+		return []Target{zipTarget(root)}, nil, nil
+	}
+
+	if utils.FileExists(filepath.Join(root, "metadata.toml")) {
+		return []Target{dirTarget(root)}, nil, nil
+	}
+
+	if assetsDir := filepath.Join(root, "assets"); utils.IsDirectory(assetsDir) {
+		targets, err := vaultTargets(assetsDir)
+		if err != nil {
+			return nil, nil, err
+		}
+		return targets, dirVersions(assetsDir), nil
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read directory: %w", err)
+	}
+	var targets []Target
+	for _, entry := range entries {
+		dir := filepath.Join(root, entry.Name())
+		if entry.IsDir() && utils.FileExists(filepath.Join(dir, "metadata.toml")) {
+			targets = append(targets, dirTarget(dir))
+		}
+	}
+	if len(targets) == 0 {
+		return nil, nil, fmt.Errorf("no assets found in %s", root)
+	}
+	return targets, nil, nil
+}
+
+// vaultTargets lists every version of every asset in a vault directory
+func vaultTargets(assetsDir string) ([]Target, error) {
+	names, err := os.ReadDir(assetsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read assets directory: %w", err)
+	}
+
+	var targets []Target
+	for _, name := range names {
+		if !name.IsDir() {
+			continue
+		}
+		versions, err := os.ReadDir(filepath.Join(assetsDir, name.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name.Name(), err)
+		}
+		for _, version := range versions {
+			dir := filepath.Join(assetsDir, name.Name(), version.Name())
+			if version.IsDir() && utils.FileExists(filepath.Join(dir, "metadata.toml")) {
+				targets = append(targets, dirTarget(dir))
+			}
+		}
+	}
+	return targets, nil
+}
+
+// dirVersions reads versions from assets/<name>/list.txt
+func dirVersions(assetsDir string) VersionLister {
+	return func(name string) ([]string, error) {
+		data, err := os.ReadFile(filepath.Join(assetsDir, name, "list.txt"))
+		if err != nil {
+			return nil, err
+		}
+		var versions []string
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				versions = append(versions, line)
+			}
+		}
+		sort.Strings(versions)
+		return versions, nil
+	}
+}
+
+func dirTarget(dir string) Target {
+	return Target{
+		Source: filepath.ToSlash(dir),
+		Load:   func() ([]byte, error) { return utils.CreateZip(dir) },
+	}
+}
+
+func zipTarget(file string) Target {
+	return Target{
+		Source: filepath.ToSlash(file),
+		Load: func() ([]byte, error) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			if !utils.IsZipFile(data) {
+				return nil, fmt.Errorf("%s is not an asset directory or zip file", file)
+			}
+			return data, nil
+		},
+	}
+}
@@ -32,13 +32,50 @@ type Asset struct {
 	// Installation configurations - array of scope installations
 	// If empty, asset is installed globally
 	Scopes []Scope `toml:"scopes,omitempty"`
+
+	// Audience lists the groups of people the asset is for (e.g. "frontend", "sre").
+	// Entries starting with ! exclude a group. If empty, the asset is for everyone.
+	Audience []string `toml:"audience,omitempty"`
+
+	// MissingRequires is what install does when a tool the asset requires (its
+	// metadata requires) isn't on the machine: "warn" (the default) installs it
+	// anyway, "skip" leaves it out until the tool is available
+	MissingRequires string `toml:"missing-requires,omitempty"`
+
+	// AutoScope "languages" limits the asset to repositories using one of its
+	// metadata supported-languages; a global asset is then installed per repo
+	AutoScope string `toml:"auto-scope,omitempty"`
+}
+
+// AutoScopeLanguages scopes an asset by the languages a repository uses
+const AutoScopeLanguages = "languages"
+
+// AutoScopesByLanguage checks if the asset is scoped by repository languages
+func (a *Asset) AutoScopesByLanguage() bool {
+	return a.AutoScope == AutoScopeLanguages
+}
+
+// Policies for assets whose required tools are missing
+const (
+	MissingRequiresWarn = "warn"
+	MissingRequiresSkip = "skip"
+)
+
+// SkipsOnMissingRequires checks if the asset is left out when required tools are missing
+func (a *Asset) SkipsOnMissingRequires() bool {
+	return a.MissingRequires == MissingRequiresSkip
 }
 
 // Scope represents where an asset is installed within a repository
 // (formerly Repository)
+//
+// Repo may be a glob over the normalized URL (github.com/my-org/*, or my-org/*
+// for any host). Paths and Branches are globs where * matches one path segment;
+// entries starting with ! exclude instead of include.
 type Scope struct {
-	Repo  string   `toml:"repo"`            // Repository URL
-	Paths []string `toml:"paths,omitempty"` // Specific paths within repo (if empty, entire repo)
+	Repo     string   `toml:"repo"`               // Repository URL or pattern
+	Paths    []string `toml:"paths,omitempty"`    // Specific paths within repo (if empty, entire repo)
+	Branches []string `toml:"branches,omitempty"` // Branches the asset applies on (if empty, all branches)
 }
 
 // ScopeType represents the scope of an installation
@@ -115,6 +115,48 @@ func TestValidateLockFile(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid missing-requires",
+			lockFile: &LockFile{
+				LockVersion: "1.0",
+				Version:     "abc",
+				CreatedBy:   "test",
+				Assets: []Asset{
+					{
+						Name:            "test",
+						Version:         "1.0.0",
+						Type:            asset.TypeSkill,
+						MissingRequires: "ignore",
+						SourceHTTP: &SourceHTTP{
+							URL:    "https://example.com/test.zip",
+							Hashes: map[string]string{"sha256": "abc"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid auto-scope",
+			lockFile: &LockFile{
+				LockVersion: "1.0",
+				Version:     "abc",
+				CreatedBy:   "test",
+				Assets: []Asset{
+					{
+						Name:      "test",
+						Version:   "1.0.0",
+						Type:      asset.TypeSkill,
+						AutoScope: "frameworks",
+						SourceHTTP: &SourceHTTP{
+							URL:    "https://example.com/test.zip",
+							Hashes: map[string]string{"sha256": "abc"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -238,3 +280,65 @@ func TestAssetScopes(t *testing.T) {
 		})
 	}
 }
+
+func TestScopePatternsRoundTrip(t *testing.T) {
+	data := []byte(`lock-version = "1"
+version = "1"
+created-by = "test"
+
+[[assets]]
+name = "release-checklist"
+version = "1.0.0"
+type = "skill"
+
+[assets.source-path]
+path = "assets/release-checklist/1.0.0"
+
+[[assets.scopes]]
+repo = "github.com/my-org/*"
+paths = ["services/*/api", "!services/legacy"]
+branches = ["release/*"]
+`)
+
+	lf, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if err := lf.Validate(); err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+
+	out, err := Marshal(lf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reparsed, err := Parse(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scope := reparsed.Assets[0].Scopes[0]
+	if scope.Repo != "github.com/my-org/*" || len(scope.Paths) != 2 || len(scope.Branches) != 1 || scope.Branches[0] != "release/*" {
+		t.Errorf("scope didn't round-trip: %+v", scope)
+	}
+}
+
+func TestScopeValidatePatterns(t *testing.T) {
+	tests := []struct {
+		name    string
+		scope   Scope
+		wantErr bool
+	}{
+		{"valid", Scope{Repo: "my-org/*", Paths: []string{"a/*", "!a/b"}, Branches: []string{"!main"}}, false},
+		{"bad path glob", Scope{Repo: "my-org/*", Paths: []string{"a/["}}, true},
+		{"empty exclusion", Scope{Repo: "my-org/*", Branches: []string{"!"}}, true},
+		{"bad repo glob", Scope{Repo: "my-org/["}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.scope.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
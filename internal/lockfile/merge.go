@@ -0,0 +1,180 @@
+package lockfile
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Merge performs a three-way merge of lock files
+// Assets are matched by name@version. An asset changed on only one side takes that
+// side's entry. An asset changed on both sides is merged field by field: each field
+// takes the side that changed it, and scopes are combined so repositories added by
+// either side are kept and ones removed by either side are dropped. Conflicts lists
+// assets modified on one side and deleted on the other (the modified entry wins) and
+// assets with a field both sides changed differently (ours wins for that field).
+// base may be nil when the file was added on both sides.
+func Merge(base, ours, theirs *LockFile) (merged *LockFile, conflicts []string) {
+	if base == nil {
+		base = &LockFile{}
+	}
+
+	baseAssets := indexAssets(base)
+	ourAssets := indexAssets(ours)
+	theirAssets := indexAssets(theirs)
+
+	merged = &LockFile{
+		LockVersion: ours.LockVersion,
+		Version:     ours.Version,
+		CreatedBy:   ours.CreatedBy,
+	}
+
+	// Keep their order, then append assets only we have
+	var keys []string
+	seen := make(map[string]bool)
+	for _, list := range [][]Asset{theirs.Assets, ours.Assets} {
+		for i := range list {
+			if key := list[i].Key(); !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	for _, key := range keys {
+		b, o, t := baseAssets[key], ourAssets[key], theirAssets[key]
+
+		var result *Asset
+		switch {
+		case reflect.DeepEqual(o, b):
+			result = t
+		case reflect.DeepEqual(t, b):
+			result = o
+		case o == nil || t == nil:
+			// Modified on one side, deleted on the other
+			conflicts = append(conflicts, key)
+			result = o
+			if result == nil {
+				result = t
+			}
+		default:
+			combined, ok := mergeAsset(b, o, t)
+			if !ok {
+				conflicts = append(conflicts, key)
+			}
+			result = &combined
+		}
+
+		if result != nil {
+			merged.Assets = append(merged.Assets, *result)
+		}
+	}
+
+	return merged, conflicts
+}
+
+// assetFieldGroups lists the Asset fields merged as a unit. The source fields
+// are one group since an asset has exactly one source; scopes are merged separately.
+var assetFieldGroups = func() [][]string {
+	var groups [][]string
+	var source []string
+	fields := reflect.TypeOf(Asset{})
+	for i := 0; i < fields.NumField(); i++ {
+		name := fields.Field(i).Name
+		switch {
+		case name == "Name" || name == "Version" || name == "Scopes":
+		case strings.HasPrefix(name, "Source"):
+			source = append(source, name)
+		default:
+			groups = append(groups, []string{name})
+		}
+	}
+	return append(groups, source)
+}()
+
+// mergeAsset merges an asset changed on both sides, field by field
+// ok is false if both sides changed a field to different values; ours is kept for it.
+func mergeAsset(base, ours, theirs *Asset) (merged Asset, ok bool) {
+	if base == nil {
+		base = &Asset{}
+	}
+	merged = *ours
+	ok = true
+
+	bv, ov, tv := reflect.ValueOf(base).Elem(), reflect.ValueOf(ours).Elem(), reflect.ValueOf(theirs).Elem()
+	mv := reflect.ValueOf(&merged).Elem()
+	for _, group := range assetFieldGroups {
+		oursChanged, theirsChanged := false, false
+		for _, name := range group {
+			b := bv.FieldByName(name).Interface()
+			oursChanged = oursChanged || !reflect.DeepEqual(ov.FieldByName(name).Interface(), b)
+			theirsChanged = theirsChanged || !reflect.DeepEqual(tv.FieldByName(name).Interface(), b)
+		}
+		if !theirsChanged {
+			continue
+		}
+		if !oursChanged {
+			for _, name := range group {
+				mv.FieldByName(name).Set(tv.FieldByName(name))
+			}
+			continue
+		}
+		for _, name := range group {
+			if !reflect.DeepEqual(ov.FieldByName(name).Interface(), tv.FieldByName(name).Interface()) {
+				ok = false
+				break
+			}
+		}
+	}
+
+	merged.Scopes = mergeScopes(base, ours, theirs)
+	return merged, ok
+}
+
+// mergeScopes combines scope changes from both sides relative to base
+func mergeScopes(base, ours, theirs *Asset) []Scope {
+	var baseScopes []Scope
+	if base != nil {
+		baseScopes = base.Scopes
+	}
+	inBase := scopeSet(baseScopes)
+	inOurs := scopeSet(ours.Scopes)
+	inTheirs := scopeSet(theirs.Scopes)
+
+	var result []Scope
+	added := make(map[string]bool)
+	for _, list := range [][]Scope{ours.Scopes, theirs.Scopes} {
+		for _, s := range list {
+			key := scopeKey(s)
+			if added[key] {
+				continue
+			}
+			// Removed by the other side
+			if inBase[key] && (!inOurs[key] || !inTheirs[key]) {
+				continue
+			}
+			added[key] = true
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func indexAssets(lf *LockFile) map[string]*Asset {
+	assets := make(map[string]*Asset, len(lf.Assets))
+	for i := range lf.Assets {
+		assets[lf.Assets[i].Key()] = &lf.Assets[i]
+	}
+	return assets
+}
+
+func scopeSet(scopes []Scope) map[string]bool {
+	set := make(map[string]bool, len(scopes))
+	for _, s := range scopes {
+		set[scopeKey(s)] = true
+	}
+	return set
+}
+
+func scopeKey(s Scope) string {
+	return s.Repo + "\x00" + strings.Join(s.Paths, "\x00") + "\x01" + strings.Join(s.Branches, "\x00")
+}
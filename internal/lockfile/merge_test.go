@@ -0,0 +1,132 @@
+package lockfile
+
+import (
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/asset"
+)
+
+func mergeTestAsset(name string, repos ...string) Asset {
+	a := Asset{Name: name, Version: "1.0.0", Type: asset.TypeSkill, SourcePath: &SourcePath{Path: "./assets/" + name}}
+	for _, repo := range repos {
+		a.Scopes = append(a.Scopes, Scope{Repo: repo})
+	}
+	return a
+}
+
+func TestMergeCombinesIndependentChanges(t *testing.T) {
+	base := &LockFile{Version: "1", Assets: []Asset{
+		mergeTestAsset("shared", "github.com/acme/a", "github.com/acme/b"),
+		mergeTestAsset("dropped-by-them"),
+	}}
+	ours := &LockFile{Version: "1", Assets: []Asset{
+		// We add repo c and remove repo b
+		mergeTestAsset("shared", "github.com/acme/a", "github.com/acme/c"),
+		mergeTestAsset("dropped-by-them"),
+		mergeTestAsset("ours-new"),
+	}}
+	theirs := &LockFile{Version: "1", Assets: []Asset{
+		// They add repo d
+		mergeTestAsset("shared", "github.com/acme/a", "github.com/acme/b", "github.com/acme/d"),
+		mergeTestAsset("theirs-new"),
+	}}
+
+	merged, conflicts := Merge(base, ours, theirs)
+	if len(conflicts) != 0 {
+		t.Errorf("unexpected conflicts: %v", conflicts)
+	}
+
+	got := make(map[string]Asset)
+	for _, a := range merged.Assets {
+		got[a.Name] = a
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected shared, theirs-new and ours-new, got %+v", merged.Assets)
+	}
+	for _, name := range []string{"theirs-new", "ours-new"} {
+		if _, ok := got[name]; !ok {
+			t.Errorf("expected %s to be kept", name)
+		}
+	}
+
+	var repos []string
+	for _, s := range got["shared"].Scopes {
+		repos = append(repos, s.Repo)
+	}
+	want := []string{"github.com/acme/a", "github.com/acme/c", "github.com/acme/d"}
+	if len(repos) != len(want) {
+		t.Fatalf("merged scopes = %v, want %v", repos, want)
+	}
+	for i := range want {
+		if repos[i] != want[i] {
+			t.Errorf("merged scopes = %v, want %v", repos, want)
+			break
+		}
+	}
+}
+
+func TestMergeReportsModifyDeleteConflicts(t *testing.T) {
+	base := &LockFile{Assets: []Asset{mergeTestAsset("skill")}}
+	ours := &LockFile{Assets: []Asset{mergeTestAsset("skill", "github.com/acme/a")}}
+	theirs := &LockFile{}
+
+	merged, conflicts := Merge(base, ours, theirs)
+	if len(conflicts) != 1 || conflicts[0] != "skill@1.0.0" {
+		t.Errorf("expected a conflict for skill@1.0.0, got %v", conflicts)
+	}
+	if len(merged.Assets) != 1 || len(merged.Assets[0].Scopes) != 1 {
+		t.Errorf("expected the modified entry to win, got %+v", merged.Assets)
+	}
+}
+
+func TestMergeTakesFieldsFromTheSideThatChangedThem(t *testing.T) {
+	base := &LockFile{Assets: []Asset{mergeTestAsset("skill", "github.com/acme/a")}}
+
+	ourAsset := mergeTestAsset("skill", "github.com/acme/a", "github.com/acme/b")
+	ourAsset.Audience = []string{"frontend"}
+	ours := &LockFile{Assets: []Asset{ourAsset}}
+
+	theirAsset := mergeTestAsset("skill", "github.com/acme/a")
+	theirAsset.MissingRequires = MissingRequiresSkip
+	theirAsset.SourcePath = nil
+	theirAsset.SourceGit = &SourceGit{URL: "https://github.com/acme/skills.git", Ref: "main"}
+	theirs := &LockFile{Assets: []Asset{theirAsset}}
+
+	merged, conflicts := Merge(base, ours, theirs)
+	if len(conflicts) != 0 {
+		t.Errorf("unexpected conflicts: %v", conflicts)
+	}
+	if len(merged.Assets) != 1 {
+		t.Fatalf("expected one asset, got %+v", merged.Assets)
+	}
+	got := merged.Assets[0]
+	if len(got.Audience) != 1 || got.Audience[0] != "frontend" {
+		t.Errorf("Audience = %v, want our change", got.Audience)
+	}
+	if got.MissingRequires != MissingRequiresSkip {
+		t.Errorf("MissingRequires = %q, want their change", got.MissingRequires)
+	}
+	if got.SourcePath != nil || got.SourceGit == nil {
+		t.Errorf("expected their new source, got path=%+v git=%+v", got.SourcePath, got.SourceGit)
+	}
+	if len(got.Scopes) != 2 {
+		t.Errorf("expected our added scope, got %+v", got.Scopes)
+	}
+}
+
+func TestMergeReportsConflictingFieldChanges(t *testing.T) {
+	base := &LockFile{Assets: []Asset{mergeTestAsset("skill")}}
+
+	ourAsset := mergeTestAsset("skill")
+	ourAsset.Audience = []string{"frontend"}
+	theirAsset := mergeTestAsset("skill")
+	theirAsset.Audience = []string{"sre"}
+
+	merged, conflicts := Merge(base, &LockFile{Assets: []Asset{ourAsset}}, &LockFile{Assets: []Asset{theirAsset}})
+	if len(conflicts) != 1 || conflicts[0] != "skill@1.0.0" {
+		t.Errorf("expected a conflict for skill@1.0.0, got %v", conflicts)
+	}
+	if len(merged.Assets) != 1 || merged.Assets[0].Audience[0] != "frontend" {
+		t.Errorf("expected our value to be kept, got %+v", merged.Assets)
+	}
+}
@@ -2,7 +2,9 @@ package lockfile
 
 import (
 	"fmt"
+	"path"
 	"regexp"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
 )
@@ -129,6 +131,23 @@ func (a *Asset) Validate() error {
 		}
 	}
 
+	// Validate audience groups
+	for _, group := range a.Audience {
+		if err := ValidateAudienceGroup(group); err != nil {
+			return fmt.Errorf("audience: %w", err)
+		}
+	}
+
+	switch a.MissingRequires {
+	case "", MissingRequiresWarn, MissingRequiresSkip:
+	default:
+		return fmt.Errorf("invalid missing-requires %q (must be warn or skip)", a.MissingRequires)
+	}
+
+	if a.AutoScope != "" && a.AutoScope != AutoScopeLanguages {
+		return fmt.Errorf("invalid auto-scope %q (must be languages)", a.AutoScope)
+	}
+
 	return nil
 }
 
@@ -137,7 +156,41 @@ func (s *Scope) Validate() error {
 	if s.Repo == "" {
 		return fmt.Errorf("repo is required")
 	}
+	if _, err := path.Match(s.Repo, ""); err != nil {
+		return fmt.Errorf("invalid repo pattern %q: %w", s.Repo, err)
+	}
+
+	for _, p := range s.Paths {
+		if err := validateScopePattern(p); err != nil {
+			return fmt.Errorf("paths: %w", err)
+		}
+	}
+	for _, b := range s.Branches {
+		if err := validateScopePattern(b); err != nil {
+			return fmt.Errorf("branches: %w", err)
+		}
+	}
+
+	return nil
+}
 
+// ValidateAudienceGroup checks an audience group name, optionally negated with !
+func ValidateAudienceGroup(group string) error {
+	if !nameRegex.MatchString(strings.TrimPrefix(group, "!")) {
+		return fmt.Errorf("invalid group %q (must be alphanumeric with dashes or underscores)", group)
+	}
+	return nil
+}
+
+// validateScopePattern checks a path or branch glob, optionally negated with !
+func validateScopePattern(pattern string) error {
+	trimmed := strings.TrimPrefix(pattern, "!")
+	if trimmed == "" {
+		return fmt.Errorf("empty pattern %q", pattern)
+	}
+	if _, err := path.Match(trimmed, ""); err != nil {
+		return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
 	return nil
 }
 
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -14,6 +13,7 @@ import (
 	"github.com/sleuth-io/sx/internal/gitutil"
 	"github.com/sleuth-io/sx/internal/logger"
 	"github.com/sleuth-io/sx/internal/stats"
+	"github.com/sleuth-io/sx/internal/utils"
 	vaultpkg "github.com/sleuth-io/sx/internal/vault"
 )
 
@@ -47,9 +47,6 @@ type ReadSkillInput struct {
 	Name string `json:"name" jsonschema:"name of the skill to read"`
 }
 
-// fileRefPattern matches @filename or @path/to/file patterns in skill content
-var fileRefPattern = regexp.MustCompile(`@([a-zA-Z0-9_\-./]+\.[a-zA-Z0-9]+)`)
-
 // Run starts the MCP server over stdio
 func (s *Server) Run(ctx context.Context) error {
 	impl := &mcp.Implementation{
@@ -108,7 +105,7 @@ func (s *Server) handleReadSkill(ctx context.Context, req *mcp.CallToolRequest,
 // resolveFileReferences replaces @file references with absolute paths
 // Only replaces if the file actually exists at the resolved path
 func resolveFileReferences(content string, baseDir string) string {
-	return fileRefPattern.ReplaceAllStringFunc(content, func(match string) string {
+	return utils.FileRefPattern.ReplaceAllStringFunc(content, func(match string) string {
 		// Extract the relative path (everything after @)
 		relativePath := match[1:] // Remove the @ prefix
 
@@ -0,0 +1,213 @@
+// Package mcpcheck smoke-tests MCP servers by starting them from their metadata
+// and listing what they advertise.
+package mcpcheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/sleuth-io/sx/internal/buildinfo"
+	"github.com/sleuth-io/sx/internal/metadata"
+)
+
+// Item is a tool, prompt or resource advertised by a server
+type Item struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Result is what a server reported during the check
+type Result struct {
+	ServerName    string `json:"serverName"`
+	ServerVersion string `json:"serverVersion,omitempty"`
+	Tools         []Item `json:"tools"`
+	Prompts       []Item `json:"prompts"`
+	Resources     []Item `json:"resources"`
+}
+
+// ToolNames returns the sorted names of the advertised tools
+func (r *Result) ToolNames() []string {
+	names := make([]string, 0, len(r.Tools))
+	for _, tool := range r.Tools {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run connects to the server described by cfg, performs the initialize
+// handshake and lists its tools, prompts and resources. dir is where a packaged
+// server's files are extracted; relative command and argument paths resolve
+// against it. The check is bounded by ctx.
+func Run(ctx context.Context, cfg *metadata.MCPConfig, dir string) (*Result, error) {
+	transport, stderr, err := newTransport(ctx, cfg, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "sx", Version: buildinfo.Version}, nil)
+	session, err := client.Connect(ctx, transport, nil)
+	if err != nil {
+		return nil, withStderr(fmt.Errorf("failed to initialize server: %w", err), stderr)
+	}
+	defer session.Close()
+
+	initResult := session.InitializeResult()
+	result := &Result{Tools: []Item{}, Prompts: []Item{}, Resources: []Item{}}
+	if initResult.ServerInfo != nil {
+		result.ServerName = initResult.ServerInfo.Name
+		result.ServerVersion = initResult.ServerInfo.Version
+	}
+
+	caps := initResult.Capabilities
+	if caps == nil {
+		return result, nil
+	}
+	if caps.Tools != nil {
+		for tool, err := range session.Tools(ctx, nil) {
+			if err != nil {
+				return nil, withStderr(fmt.Errorf("failed to list tools: %w", err), stderr)
+			}
+			result.Tools = append(result.Tools, Item{Name: tool.Name, Description: tool.Description})
+		}
+	}
+	if caps.Prompts != nil {
+		for prompt, err := range session.Prompts(ctx, nil) {
+			if err != nil {
+				return nil, withStderr(fmt.Errorf("failed to list prompts: %w", err), stderr)
+			}
+			result.Prompts = append(result.Prompts, Item{Name: prompt.Name, Description: prompt.Description})
+		}
+	}
+	if caps.Resources != nil {
+		for resource, err := range session.Resources(ctx, nil) {
+			if err != nil {
+				return nil, withStderr(fmt.Errorf("failed to list resources: %w", err), stderr)
+			}
+			result.Resources = append(result.Resources, Item{Name: resource.URI, Description: resource.Description})
+		}
+	}
+
+	return result, nil
+}
+
+// newTransport builds the client transport for cfg. For stdio servers it also
+// returns a buffer collecting the server's stderr.
+func newTransport(ctx context.Context, cfg *metadata.MCPConfig, dir string) (mcp.Transport, *output, error) {
+	switch cfg.GetTransport() {
+	case metadata.MCPTransportHTTP:
+		return &mcp.StreamableClientTransport{
+			Endpoint:   os.ExpandEnv(cfg.URL),
+			HTTPClient: headerClient(cfg.Headers),
+			MaxRetries: -1,
+		}, nil, nil
+	case metadata.MCPTransportSSE:
+		return &mcp.SSEClientTransport{
+			Endpoint:   os.ExpandEnv(cfg.URL),
+			HTTPClient: headerClient(cfg.Headers),
+		}, nil, nil
+	}
+
+	if cfg.Command == "" {
+		return nil, nil, fmt.Errorf("no command or url to start the server")
+	}
+
+	command := cfg.Command
+	args := make([]string, len(cfg.Args))
+	copy(args, cfg.Args)
+	if dir != "" {
+		// Packaged servers reference their own files relative to the asset
+		// directory, as the client handlers do when installing them
+		if strings.Contains(command, "/") && !filepath.IsAbs(command) {
+			command = filepath.Join(dir, command)
+		}
+		for i, arg := range args {
+			if !filepath.IsAbs(arg) && filepath.Base(arg) != arg {
+				if _, err := os.Stat(filepath.Join(dir, arg)); err == nil {
+					args[i] = filepath.Join(dir, arg)
+				}
+			}
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Dir = dir
+	cmd.Env = os.Environ()
+	for key, value := range cfg.Env {
+		cmd.Env = append(cmd.Env, key+"="+os.ExpandEnv(value))
+	}
+	stderr := &output{}
+	cmd.Stderr = stderr
+
+	return &mcp.CommandTransport{Command: cmd}, stderr, nil
+}
+
+// headerClient returns an HTTP client that sends headers with each request
+func headerClient(headers map[string]string) *http.Client {
+	if len(headers) == 0 {
+		return nil
+	}
+	expanded := make(map[string]string, len(headers))
+	for name, value := range headers {
+		expanded[name] = os.ExpandEnv(value)
+	}
+	return &http.Client{Transport: &headerTransport{headers: expanded, base: http.DefaultTransport}}
+}
+
+type headerTransport struct {
+	headers map[string]string
+	base    http.RoundTripper
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// output collects a server's stderr, which is written while the server runs
+type output struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.buf.Write(p)
+}
+
+func (o *output) String() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.buf.String()
+}
+
+// withStderr adds the tail of the server's stderr to err, since a server that
+// crashes on startup usually says why there
+func withStderr(err error, stderr *output) error {
+	if stderr == nil {
+		return err
+	}
+	text := strings.TrimSpace(stderr.String())
+	if text == "" {
+		return err
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) > 10 {
+		lines = lines[len(lines)-10:]
+	}
+	return fmt.Errorf("%w\nserver stderr:\n  %s", err, strings.Join(lines, "\n  "))
+}
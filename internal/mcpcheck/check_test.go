@@ -0,0 +1,127 @@
+package mcpcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/sleuth-io/sx/internal/metadata"
+)
+
+// TestMain doubles as a stdio MCP server, so tests can check a real process
+func TestMain(m *testing.M) {
+	switch os.Getenv("SX_TEST_MCP_SERVER") {
+	case "serve":
+		if err := testServer().Run(context.Background(), &mcp.StdioTransport{}); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	case "crash":
+		os.Stderr.WriteString("missing API_TOKEN\n")
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+type echoInput struct {
+	Text string `json:"text"`
+}
+
+func testServer() *mcp.Server {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.2.3"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "echo", Description: "Echo text"},
+		func(ctx context.Context, req *mcp.CallToolRequest, input echoInput) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{}, nil, nil
+		})
+	mcp.AddTool(server, &mcp.Tool{Name: "add", Description: "Add numbers"},
+		func(ctx context.Context, req *mcp.CallToolRequest, input echoInput) (*mcp.CallToolResult, any, error) {
+			return &mcp.CallToolResult{}, nil, nil
+		})
+	server.AddPrompt(&mcp.Prompt{Name: "summarize"}, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{}, nil
+	})
+	return server
+}
+
+func testContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestRunStdioServer(t *testing.T) {
+	cfg := &metadata.MCPConfig{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^$"},
+		Env:     map[string]string{"SX_TEST_MCP_SERVER": "serve"},
+	}
+
+	result, err := Run(testContext(t), cfg, t.TempDir())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if result.ServerName != "test-server" || result.ServerVersion != "1.2.3" {
+		t.Errorf("server = %s %s", result.ServerName, result.ServerVersion)
+	}
+	if got := strings.Join(result.ToolNames(), ","); got != "add,echo" {
+		t.Errorf("tools = %s, want add,echo", got)
+	}
+	if len(result.Prompts) != 1 || result.Prompts[0].Name != "summarize" {
+		t.Errorf("prompts = %+v", result.Prompts)
+	}
+}
+
+func TestRunReportsStderrOnCrash(t *testing.T) {
+	cfg := &metadata.MCPConfig{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=^$"},
+		Env:     map[string]string{"SX_TEST_MCP_SERVER": "crash"},
+	}
+
+	_, err := Run(testContext(t), cfg, t.TempDir())
+	if err == nil {
+		t.Fatal("expected an error for a server that exits on startup")
+	}
+	if !strings.Contains(err.Error(), "missing API_TOKEN") {
+		t.Errorf("error = %v, want the server's stderr", err)
+	}
+}
+
+func TestRunHTTPServerWithHeaders(t *testing.T) {
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return testServer() }, nil)
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer httpServer.Close()
+
+	t.Setenv("TEST_API_KEY", "secret")
+	cfg := &metadata.MCPConfig{
+		URL:     httpServer.URL,
+		Headers: map[string]string{"X-Api-Key": "${TEST_API_KEY}"},
+	}
+
+	result, err := Run(testContext(t), cfg, "")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(result.Tools) != 2 {
+		t.Errorf("tools = %+v, want 2", result.Tools)
+	}
+
+	cfg.Headers = nil
+	if _, err := Run(testContext(t), cfg, ""); err == nil {
+		t.Error("expected an error without the API key header")
+	}
+}
@@ -0,0 +1,134 @@
+// Package mcpruntime sets up the dependencies of packaged MCP servers, running
+// their [mcp] setup commands in a per-version directory that the installed
+// server then runs from.
+package mcpruntime
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/sleuth-io/sx/internal/cache"
+	"github.com/sleuth-io/sx/internal/metadata"
+	"github.com/sleuth-io/sx/internal/utils"
+)
+
+// markerFile is written once setup succeeds, so an interrupted or failed setup
+// is never mistaken for a ready one
+const markerFile = ".sx-setup"
+
+// NeedsSetup checks if an asset declares setup commands
+func NeedsSetup(meta *metadata.Metadata) bool {
+	return meta.MCP != nil && len(meta.MCP.SetupCommands()) > 0
+}
+
+// Hash identifies an asset's contents, keying both the setup cache and approvals
+func Hash(zipData []byte) string {
+	sum := sha256.Sum256(zipData)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// ApprovalKey is how an approved setup is recorded in the config
+func ApprovalKey(name, hash string) string {
+	return name + "@" + hash
+}
+
+// Dir returns the directory an asset version is set up in
+func Dir(name, version, hash string) (string, error) {
+	base, err := cache.GetMCPRuntimeCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, name, version+"-"+hash), nil
+}
+
+// Ready checks if setup completed in dir
+func Ready(dir string) bool {
+	return utils.FileExists(filepath.Join(dir, markerFile))
+}
+
+// Prepare extracts the asset and runs its setup commands, returning the
+// directory the server runs from. A completed setup for the same contents is
+// reused unless force is set.
+func Prepare(ctx context.Context, meta *metadata.Metadata, zipData []byte, force bool) (string, error) {
+	dir, err := Dir(meta.Asset.Name, meta.Asset.Version, Hash(zipData))
+	if err != nil {
+		return "", fmt.Errorf("failed to get runtime directory: %w", err)
+	}
+	if !force && Ready(dir) {
+		return dir, nil
+	}
+
+	// Set up in place: virtualenvs and some node packages record absolute
+	// paths, so the directory can't be moved afterwards
+	if err := os.RemoveAll(dir); err != nil {
+		return "", fmt.Errorf("failed to clear runtime directory: %w", err)
+	}
+	if err := utils.ExtractZip(zipData, dir); err != nil {
+		return "", fmt.Errorf("failed to extract asset: %w", err)
+	}
+
+	commands := meta.MCP.SetupCommands()
+	for _, command := range commands {
+		if err := run(ctx, dir, command); err != nil {
+			return "", err
+		}
+	}
+
+	marker := strings.Join(commands, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(dir, markerFile), []byte(marker), 0644); err != nil {
+		return "", fmt.Errorf("failed to record setup: %w", err)
+	}
+	return dir, nil
+}
+
+// run runs a setup command in dir. Commands are split on whitespace and run
+// without a shell.
+func run(ctx context.Context, dir, command string) error {
+	fields := strings.Fields(command)
+	cmd := exec.CommandContext(ctx, fields[0], fields[1:]...)
+	cmd.Dir = dir
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+		if len(lines) > 10 {
+			lines = lines[len(lines)-10:]
+		}
+		return fmt.Errorf("setup command %q failed: %w\n  %s", command, err, strings.Join(lines, "\n  "))
+	}
+	return nil
+}
+
+// Resolve returns a copy of cfg that runs the server from dir: packaged
+// command and argument paths become absolute paths in dir, and commands
+// that aren't packaged (node, uv, ...) are resolved from PATH.
+func Resolve(cfg *metadata.MCPConfig, dir string) *metadata.MCPConfig {
+	resolved := *cfg
+
+	if !filepath.IsAbs(cfg.Command) {
+		if utils.FileExists(filepath.Join(dir, cfg.Command)) {
+			resolved.Command = filepath.Join(dir, cfg.Command)
+		} else if path, err := exec.LookPath(cfg.Command); err == nil {
+			resolved.Command = path
+		}
+	}
+
+	resolved.Args = make([]string, len(cfg.Args))
+	for i, arg := range cfg.Args {
+		resolved.Args[i] = arg
+		if !filepath.IsAbs(arg) && utils.FileExists(filepath.Join(dir, arg)) {
+			resolved.Args[i] = filepath.Join(dir, arg)
+		}
+	}
+
+	return &resolved
+}
@@ -0,0 +1,144 @@
+package mcpruntime
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/metadata"
+	"github.com/sleuth-io/sx/internal/utils"
+)
+
+// TestMain doubles as a setup command: it records each run in a log file and
+// creates the dependency directory a real installer would
+func TestMain(m *testing.M) {
+	switch os.Getenv("SX_TEST_SETUP") {
+	case "ok":
+		f, err := os.OpenFile(os.Getenv("SX_TEST_SETUP_LOG"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			os.Exit(2)
+		}
+		_, _ = f.WriteString("run\n")
+		_ = f.Close()
+		if err := os.MkdirAll(filepath.Join("node_modules", "dep"), 0755); err != nil {
+			os.Exit(2)
+		}
+		os.Exit(0)
+	case "fail":
+		os.Stderr.WriteString("npm ERR! missing package-lock.json\n")
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func testAsset(t *testing.T) (*metadata.Metadata, []byte) {
+	t.Helper()
+	t.Setenv("SX_CACHE_DIR", t.TempDir())
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "index.js"), []byte("console.log('hi')"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	zipData, err := utils.CreateZip(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta := &metadata.Metadata{
+		Asset: metadata.Asset{Name: "github", Version: "1.0.0", Type: asset.TypeMCP},
+		MCP: &metadata.MCPConfig{
+			Command: "node",
+			Args:    []string{"index.js"},
+			Setup:   []string{os.Args[0] + " -test.run=^$"},
+		},
+	}
+	return meta, zipData
+}
+
+func setupLog(t *testing.T) string {
+	t.Helper()
+	log := filepath.Join(t.TempDir(), "setup.log")
+	t.Setenv("SX_TEST_SETUP_LOG", log)
+	return log
+}
+
+func runs(t *testing.T, log string) int {
+	t.Helper()
+	data, err := os.ReadFile(log)
+	if err != nil {
+		return 0
+	}
+	return strings.Count(string(data), "run\n")
+}
+
+func TestPrepareRunsSetupOnce(t *testing.T) {
+	meta, zipData := testAsset(t)
+	log := setupLog(t)
+	t.Setenv("SX_TEST_SETUP", "ok")
+
+	dir, err := Prepare(context.Background(), meta, zipData, false)
+	if err != nil {
+		t.Fatalf("Prepare failed: %v", err)
+	}
+	if !Ready(dir) || !utils.IsDirectory(filepath.Join(dir, "node_modules", "dep")) {
+		t.Errorf("setup didn't complete in %s", dir)
+	}
+	if !strings.Contains(dir, "1.0.0-"+Hash(zipData)) {
+		t.Errorf("dir = %s, want it keyed by version and content hash", dir)
+	}
+
+	// The same contents reuse the prepared directory, unless forced
+	if _, err := Prepare(context.Background(), meta, zipData, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := runs(t, log); got != 1 {
+		t.Errorf("setup ran %d times, want 1", got)
+	}
+	if _, err := Prepare(context.Background(), meta, zipData, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := runs(t, log); got != 2 {
+		t.Errorf("setup ran %d times after forcing, want 2", got)
+	}
+}
+
+func TestPrepareReportsFailure(t *testing.T) {
+	meta, zipData := testAsset(t)
+	t.Setenv("SX_TEST_SETUP", "fail")
+
+	_, err := Prepare(context.Background(), meta, zipData, false)
+	if err == nil {
+		t.Fatal("expected an error for a failing setup command")
+	}
+	if !strings.Contains(err.Error(), "missing package-lock.json") {
+		t.Errorf("error = %v, want the command's output", err)
+	}
+
+	dir, _ := Dir(meta.Asset.Name, meta.Asset.Version, Hash(zipData))
+	if Ready(dir) {
+		t.Error("a failed setup must not be marked ready")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "index.js"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &metadata.MCPConfig{Command: "sx-no-such-tool", Args: []string{"index.js", "--stdio"}}
+	resolved := Resolve(cfg, dir)
+
+	if resolved.Command != "sx-no-such-tool" {
+		t.Errorf("command = %s, want it left alone when not packaged or on PATH", resolved.Command)
+	}
+	if resolved.Args[0] != filepath.Join(dir, "index.js") || resolved.Args[1] != "--stdio" {
+		t.Errorf("args = %v", resolved.Args)
+	}
+	if cfg.Args[0] != "index.js" {
+		t.Error("Resolve modified the original config")
+	}
+}
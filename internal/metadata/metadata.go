@@ -45,6 +45,9 @@ type SkillConfig struct {
 	Triggers           []string `toml:"triggers,omitempty"`
 	Requires           []string `toml:"requires,omitempty"`
 	SupportedLanguages []string `toml:"supported-languages,omitempty"`
+
+	// Files that make clients with file-based rules (Cursor) load the skill
+	Globs []string `toml:"globs,omitempty"`
 }
 
 // CommandConfig represents the [command] section
@@ -53,6 +56,12 @@ type CommandConfig struct {
 	Aliases      []string `toml:"aliases,omitempty"`
 	RequiresAuth bool     `toml:"requires-auth,omitempty"`
 	Dangerous    bool     `toml:"dangerous,omitempty"`
+
+	// Rendered into the installed command's frontmatter
+	Description  string   `toml:"description,omitempty"`
+	ArgumentHint string   `toml:"argument-hint,omitempty"`
+	AllowedTools []string `toml:"allowed-tools,omitempty"`
+	Model        string   `toml:"model,omitempty"`
 }
 
 // AgentConfig represents the [agent] section
@@ -60,8 +69,19 @@ type AgentConfig struct {
 	PromptFile string   `toml:"prompt-file"`
 	Triggers   []string `toml:"triggers,omitempty"`
 	Requires   []string `toml:"requires,omitempty"`
+
+	// Rendered into the installed agent's frontmatter
+	Tools []string `toml:"tools,omitempty"`
+	Model string   `toml:"model,omitempty"`
+	Color string   `toml:"color,omitempty"`
 }
 
+// Model aliases accepted by clients besides full model names (claude-...)
+var modelAliases = []string{"inherit", "sonnet", "opus", "haiku"}
+
+// Colors an agent can be shown in
+var agentColors = []string{"red", "blue", "green", "yellow", "purple", "orange", "pink", "cyan"}
+
 // HookConfig represents the [hook] section
 type HookConfig struct {
 	Event       string `toml:"event"`
@@ -73,11 +93,71 @@ type HookConfig struct {
 
 // MCPConfig represents the [mcp] section (for both mcp and mcp-remote)
 type MCPConfig struct {
-	Command      string            `toml:"command"`
-	Args         []string          `toml:"args"`
+	Command      string            `toml:"command,omitempty"`
+	Args         []string          `toml:"args,omitempty"`
 	Env          map[string]string `toml:"env,omitempty"`
 	Timeout      int               `toml:"timeout,omitempty"`
 	Capabilities []string          `toml:"capabilities,omitempty"`
+	Requires     []string          `toml:"requires,omitempty"`
+
+	// URL, Transport and Headers register a remote (HTTP or SSE) server instead
+	// of launching a command (mcp-remote only)
+	URL       string            `toml:"url,omitempty"`
+	Transport string            `toml:"transport,omitempty"` // http, sse, or stdio
+	Headers   map[string]string `toml:"headers,omitempty"`
+	OAuth     *MCPOAuthConfig   `toml:"oauth,omitempty"`
+
+	// Runtime and Setup prepare a packaged server's dependencies after
+	// extraction (mcp only). Setup commands override the runtime's default.
+	Runtime string   `toml:"runtime,omitempty"` // node, python, or go
+	Setup   []string `toml:"setup,omitempty"`
+}
+
+// MCP runtimes and the setup commands they run by default
+var mcpRuntimeSetup = map[string][]string{
+	"node":   {"npm ci --omit=dev"},
+	"python": {"uv sync --frozen"},
+	"go":     {"go build -o server ."},
+}
+
+// MCP transports
+const (
+	MCPTransportStdio = "stdio"
+	MCPTransportHTTP  = "http"
+	MCPTransportSSE   = "sse"
+)
+
+// MCPOAuthConfig represents the [mcp.oauth] section: hints for clients that
+// authenticate to a remote server with OAuth
+type MCPOAuthConfig struct {
+	ClientID     string `toml:"client-id,omitempty"`
+	CallbackPort int    `toml:"callback-port,omitempty"`
+}
+
+// IsRemote returns true if the server is reached over the network
+func (m *MCPConfig) IsRemote() bool {
+	return m.URL != ""
+}
+
+// SetupCommands returns the commands that prepare a packaged server, from
+// setup or the runtime's default
+func (m *MCPConfig) SetupCommands() []string {
+	if len(m.Setup) > 0 {
+		return m.Setup
+	}
+	return mcpRuntimeSetup[m.Runtime]
+}
+
+// GetTransport returns the transport, defaulting to http for servers with a
+// URL and stdio otherwise
+func (m *MCPConfig) GetTransport() string {
+	if m.Transport != "" {
+		return m.Transport
+	}
+	if m.IsRemote() {
+		return MCPTransportHTTP
+	}
+	return MCPTransportStdio
 }
 
 // metadataCompat is used for parsing old-style metadata with [artifact] section
@@ -159,3 +239,21 @@ func (m *Metadata) GetTypeConfig() interface{} {
 	}
 	return nil
 }
+
+// GetRequires returns the tools the asset requires (e.g. "git>=2.30")
+func (m *Metadata) GetRequires() []string {
+	switch {
+	case m.Skill != nil:
+		return m.Skill.Requires
+	case m.Agent != nil:
+		return m.Agent.Requires
+	case m.MCP != nil:
+		return m.MCP.Requires
+	}
+	return nil
+}
+
+// IsDangerous checks if the asset is a command marked as dangerous
+func (m *Metadata) IsDangerous() bool {
+	return m.Command != nil && m.Command.Dangerous
+}
@@ -93,3 +93,125 @@ args = [
 		t.Errorf("MCP args not preserved after round-trip: got %q, want %q", meta2.MCP.Args[0], "server.js")
 	}
 }
+
+func TestRemoteMCPMetadata(t *testing.T) {
+	meta, err := Parse([]byte(`[asset]
+name = "linear"
+version = "1.0.0"
+type = "mcp-remote"
+
+[mcp]
+url = "https://mcp.linear.app/sse"
+transport = "sse"
+
+[mcp.headers]
+Authorization = "Bearer ${LINEAR_TOKEN}"
+
+[mcp.oauth]
+client-id = "sx"
+callback-port = 8080
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse metadata: %v", err)
+	}
+	if err := meta.Validate(); err != nil {
+		t.Fatalf("Validation failed: %v", err)
+	}
+
+	if !meta.MCP.IsRemote() || meta.MCP.GetTransport() != MCPTransportSSE {
+		t.Errorf("MCP = %+v, want a remote sse server", meta.MCP)
+	}
+	if meta.MCP.Headers["Authorization"] != "Bearer ${LINEAR_TOKEN}" {
+		t.Errorf("headers = %v", meta.MCP.Headers)
+	}
+	if meta.MCP.OAuth == nil || meta.MCP.OAuth.ClientID != "sx" || meta.MCP.OAuth.CallbackPort != 8080 {
+		t.Errorf("oauth = %+v", meta.MCP.OAuth)
+	}
+
+	// Remote servers don't need a command
+	data, err := Marshal(meta)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	reparsed, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Failed to reparse: %v", err)
+	}
+	if reparsed.MCP.Command != "" || reparsed.MCP.URL != meta.MCP.URL {
+		t.Errorf("round trip MCP = %+v", reparsed.MCP)
+	}
+}
+
+func TestMCPConfigValidateTransport(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  MCPConfig
+		wantErr bool
+	}{
+		{"stdio command", MCPConfig{Command: "node", Args: []string{"server.js"}}, false},
+		{"url defaults to http", MCPConfig{URL: "https://example.com/mcp"}, false},
+		{"sse url", MCPConfig{URL: "https://example.com/sse", Transport: "sse"}, false},
+		{"unknown transport", MCPConfig{URL: "https://example.com/mcp", Transport: "websocket"}, true},
+		{"http without url", MCPConfig{Transport: "http"}, true},
+		{"non-http url", MCPConfig{URL: "ftp://example.com/mcp"}, true},
+		{"stdio with url", MCPConfig{URL: "https://example.com/mcp", Transport: "stdio", Command: "node", Args: []string{"x"}}, true},
+		{"url with command", MCPConfig{URL: "https://example.com/mcp", Command: "npx", Args: []string{"mcp-remote"}}, true},
+		{"headers without url", MCPConfig{Command: "node", Args: []string{"x"}, Headers: map[string]string{"X-Team": "a"}}, true},
+		{"invalid header name", MCPConfig{URL: "https://example.com/mcp", Headers: map[string]string{"Bad Header": "a"}}, true},
+		{"invalid callback port", MCPConfig{URL: "https://example.com/mcp", OAuth: &MCPOAuthConfig{CallbackPort: 70000}}, true},
+		{"negative callback port", MCPConfig{URL: "https://example.com/mcp", OAuth: &MCPOAuthConfig{CallbackPort: -1}}, true},
+		{"automatic callback port", MCPConfig{URL: "https://example.com/mcp", OAuth: &MCPOAuthConfig{CallbackPort: 0}}, false},
+		{"lowest callback port", MCPConfig{URL: "https://example.com/mcp", OAuth: &MCPOAuthConfig{CallbackPort: 1}}, false},
+		{"highest callback port", MCPConfig{URL: "https://example.com/mcp", OAuth: &MCPOAuthConfig{CallbackPort: 65535}}, false},
+		{"callback port past the range", MCPConfig{URL: "https://example.com/mcp", OAuth: &MCPOAuthConfig{CallbackPort: 65536}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPackagedMCPRejectsURL(t *testing.T) {
+	meta := &Metadata{
+		Asset: Asset{Name: "github", Version: "1.0.0", Type: asset.TypeMCP},
+		MCP:   &MCPConfig{URL: "https://example.com/mcp"},
+	}
+	if err := meta.Validate(); err == nil {
+		t.Error("expected an error for a url on a packaged mcp asset")
+	}
+}
+
+func TestMCPRuntimeSetup(t *testing.T) {
+	node := &MCPConfig{Command: "node", Args: []string{"index.js"}, Runtime: "node"}
+	if err := node.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+	if got := node.SetupCommands(); len(got) != 1 || got[0] != "npm ci --omit=dev" {
+		t.Errorf("SetupCommands() = %v, want the node default", got)
+	}
+
+	custom := &MCPConfig{Command: "node", Args: []string{"index.js"}, Runtime: "node", Setup: []string{"pnpm install --prod"}}
+	if got := custom.SetupCommands(); len(got) != 1 || got[0] != "pnpm install --prod" {
+		t.Errorf("SetupCommands() = %v, want setup to override the runtime", got)
+	}
+
+	if err := (&MCPConfig{Command: "ruby", Args: []string{"x"}, Runtime: "ruby"}).Validate(); err == nil {
+		t.Error("expected an error for an unknown runtime")
+	}
+	if err := (&MCPConfig{Command: "node", Args: []string{"x"}, Setup: []string{" "}}).Validate(); err == nil {
+		t.Error("expected an error for an empty setup command")
+	}
+
+	remote := &Metadata{
+		Asset: Asset{Name: "linear", Version: "1.0.0", Type: asset.TypeMCPRemote},
+		MCP:   &MCPConfig{URL: "https://example.com/mcp", Runtime: "node"},
+	}
+	if err := remote.Validate(); err == nil {
+		t.Error("expected an error for setup on an mcp-remote asset")
+	}
+}
@@ -133,3 +133,57 @@ dependencies = ["dep1", "dep2"]
 		t.Errorf("Expected second dependency 'dep2', got %s", meta.Asset.Dependencies[1])
 	}
 }
+
+func TestParseRequirement(t *testing.T) {
+	tests := []struct {
+		req            string
+		wantTool       string
+		wantConstraint string
+		wantErr        bool
+	}{
+		{"git", "git", "", false},
+		{"git>=2.30", "git", ">=2.30", false},
+		{"node >= 18, < 23", "node", ">= 18, < 23", false},
+		{"python3.11", "python3.11", "", false},
+		{"git>=abc", "", "", true},
+		{"", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.req, func(t *testing.T) {
+			tool, constraint, err := ParseRequirement(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRequirement(%q) error = %v, wantErr %v", tt.req, err, tt.wantErr)
+			}
+			if tool != tt.wantTool || constraint != tt.wantConstraint {
+				t.Errorf("ParseRequirement(%q) = %q, %q; want %q, %q", tt.req, tool, constraint, tt.wantTool, tt.wantConstraint)
+			}
+		})
+	}
+}
+
+func TestValidateFrontmatterFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  interface{ Validate() error }
+		wantErr bool
+	}{
+		{"agent with tools, model and color", &AgentConfig{PromptFile: "AGENT.md", Tools: []string{"Read", "Bash(git:*)"}, Model: "sonnet", Color: "blue"}, false},
+		{"agent with full model name", &AgentConfig{PromptFile: "AGENT.md", Model: "claude-opus-4-1"}, false},
+		{"agent with unknown model", &AgentConfig{PromptFile: "AGENT.md", Model: "gpt-4"}, true},
+		{"agent with unknown color", &AgentConfig{PromptFile: "AGENT.md", Color: "magenta"}, true},
+		{"agent with empty tool", &AgentConfig{PromptFile: "AGENT.md", Tools: []string{""}}, true},
+		{"command with frontmatter", &CommandConfig{PromptFile: "COMMAND.md", Description: "Ship it", ArgumentHint: "[env]", AllowedTools: []string{"Bash(git push:*)"}, Model: "haiku"}, false},
+		{"command tool with comma", &CommandConfig{PromptFile: "COMMAND.md", AllowedTools: []string{"Read, Write"}}, true},
+		{"command with invalid alias", &CommandConfig{PromptFile: "COMMAND.md", Aliases: []string{"ship it"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package metadata
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/sleuth-io/sx/internal/asset"
@@ -12,6 +14,9 @@ var (
 	// nameRegex matches valid asset names (alphanumeric, dashes, underscores)
 	nameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 
+	// requirementRegex matches an executable name followed by an optional version constraint
+	requirementRegex = regexp.MustCompile(`^([a-zA-Z0-9_.+-]+?)\s*((?:[><=~!^][^a-zA-Z]*)?)$`)
+
 	// Valid hook events
 	validHookEvents = map[string]bool{
 		"pre-commit":  true,
@@ -23,6 +28,11 @@ var (
 	}
 )
 
+// HookEvents returns the valid hook events in lifecycle order
+func HookEvents() []string {
+	return []string{"pre-commit", "post-commit", "pre-push", "post-push", "pre-merge", "post-merge"}
+}
+
 // Validate validates the entire metadata structure
 func (m *Metadata) Validate() error {
 	// Validate asset section
@@ -47,6 +57,11 @@ func (m *Metadata) Validate() error {
 		if err := m.Command.Validate(); err != nil {
 			return fmt.Errorf("command: %w", err)
 		}
+		for _, alias := range m.Command.Aliases {
+			if alias == m.Asset.Name {
+				return fmt.Errorf("command: alias %q is the command's own name", alias)
+			}
+		}
 
 	case asset.TypeAgent:
 		if m.Agent == nil {
@@ -68,6 +83,12 @@ func (m *Metadata) Validate() error {
 		if m.MCP == nil {
 			return fmt.Errorf("[mcp] section is required for %s assets", m.Asset.Type)
 		}
+		if m.Asset.Type == asset.TypeMCP && m.MCP.IsRemote() {
+			return fmt.Errorf("mcp: url is only supported for mcp-remote assets")
+		}
+		if m.Asset.Type == asset.TypeMCPRemote && (m.MCP.Runtime != "" || len(m.MCP.Setup) > 0) {
+			return fmt.Errorf("mcp: runtime and setup are only supported for packaged mcp assets")
+		}
 		if err := m.MCP.Validate(); err != nil {
 			return fmt.Errorf("mcp: %w", err)
 		}
@@ -116,7 +137,15 @@ func (c *CommandConfig) Validate() error {
 	if c.PromptFile == "" {
 		return fmt.Errorf("prompt-file is required")
 	}
-	return nil
+	for _, alias := range c.Aliases {
+		if !nameRegex.MatchString(alias) {
+			return fmt.Errorf("alias %q must contain only alphanumeric characters, dashes, and underscores", alias)
+		}
+	}
+	if err := validateTools("allowed-tools", c.AllowedTools); err != nil {
+		return err
+	}
+	return validateModel(c.Model)
 }
 
 // Validate validates the [agent] section
@@ -124,9 +153,45 @@ func (a *AgentConfig) Validate() error {
 	if a.PromptFile == "" {
 		return fmt.Errorf("prompt-file is required")
 	}
+	if err := validateTools("tools", a.Tools); err != nil {
+		return err
+	}
+	if a.Color != "" && !containsString(agentColors, a.Color) {
+		return fmt.Errorf("invalid color %q (must be one of %s)", a.Color, strings.Join(agentColors, ", "))
+	}
+	return validateModel(a.Model)
+}
+
+// validateModel checks a model is an alias or a full Claude model name
+func validateModel(model string) error {
+	if model == "" || containsString(modelAliases, model) || strings.HasPrefix(model, "claude-") {
+		return nil
+	}
+	return fmt.Errorf("invalid model %q (must be %s, or a full model name like claude-sonnet-4-5)", model, strings.Join(modelAliases, ", "))
+}
+
+// validateTools checks tool names, which are written comma-separated into frontmatter
+func validateTools(field string, tools []string) error {
+	for _, tool := range tools {
+		if strings.TrimSpace(tool) == "" {
+			return fmt.Errorf("%s entries can't be empty", field)
+		}
+		if strings.Contains(tool, ",") {
+			return fmt.Errorf("%s entry %q can't contain a comma", field, tool)
+		}
+	}
 	return nil
 }
 
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate validates the [hook] section
 func (h *HookConfig) Validate() error {
 	if h.Event == "" {
@@ -150,6 +215,34 @@ func (h *HookConfig) Validate() error {
 
 // Validate validates the [mcp] section
 func (m *MCPConfig) Validate() error {
+	if m.Timeout < 0 {
+		return fmt.Errorf("timeout must be non-negative")
+	}
+
+	if _, ok := mcpRuntimeSetup[m.Runtime]; m.Runtime != "" && !ok {
+		return fmt.Errorf("invalid runtime %q (must be node, python, or go)", m.Runtime)
+	}
+	for _, command := range m.Setup {
+		if strings.TrimSpace(command) == "" {
+			return fmt.Errorf("setup commands can't be empty")
+		}
+	}
+
+	switch m.GetTransport() {
+	case MCPTransportHTTP, MCPTransportSSE:
+		return m.validateRemote()
+	case MCPTransportStdio:
+	default:
+		return fmt.Errorf("invalid transport %q (must be http, sse, or stdio)", m.Transport)
+	}
+
+	if m.URL != "" {
+		return fmt.Errorf("url requires the http or sse transport")
+	}
+	if len(m.Headers) > 0 || m.OAuth != nil {
+		return fmt.Errorf("headers and oauth are only supported with a url")
+	}
+
 	if m.Command == "" {
 		return fmt.Errorf("command is required")
 	}
@@ -158,8 +251,32 @@ func (m *MCPConfig) Validate() error {
 		return fmt.Errorf("args is required (must be a non-empty array)")
 	}
 
-	if m.Timeout < 0 {
-		return fmt.Errorf("timeout must be non-negative")
+	return nil
+}
+
+// validateRemote validates a server reached over http or sse
+func (m *MCPConfig) validateRemote() error {
+	if m.URL == "" {
+		return fmt.Errorf("url is required for the %s transport", m.GetTransport())
+	}
+	u, err := url.Parse(m.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an http(s) URL: %s", m.URL)
+	}
+
+	if m.Command != "" || len(m.Args) > 0 {
+		return fmt.Errorf("command and args can't be combined with a url")
+	}
+
+	for name := range m.Headers {
+		if name == "" || strings.ContainsAny(name, " :\r\n") {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+	}
+
+	// A callback port of 0 (or none) lets the client pick one
+	if m.OAuth != nil && (m.OAuth.CallbackPort < 0 || m.OAuth.CallbackPort > 65535) {
+		return fmt.Errorf("oauth callback-port must be between 1 and 65535, or 0 to pick one automatically")
 	}
 
 	return nil
@@ -195,6 +312,23 @@ func ParseDependency(dep string) (name string, constraint string, err error) {
 	return name, constraint, nil
 }
 
+// ParseRequirement parses a tool requirement (e.g., "git>=2.30" or "node")
+// Returns the executable name and version constraint
+func ParseRequirement(req string) (tool string, constraint string, err error) {
+	matches := requirementRegex.FindStringSubmatch(strings.TrimSpace(req))
+	if matches == nil {
+		return "", "", fmt.Errorf("invalid requirement format: %s", req)
+	}
+
+	tool = matches[1]
+	constraint = strings.TrimSpace(matches[2])
+	if err := ValidateDependencyConstraint(constraint); err != nil {
+		return "", "", err
+	}
+
+	return tool, constraint, nil
+}
+
 // ValidateDependencyConstraint validates a version constraint string
 // Supports: >=X.Y.Z, ~=X.Y.Z, ~X.Y.Z, and comma-separated constraints
 func ValidateDependencyConstraint(constraint string) error {
@@ -0,0 +1,194 @@
+package overlay
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sleuth-io/sx/internal/utils"
+)
+
+// Dir is where a repository keeps its overlays, relative to the repository root.
+// Each asset's overlay lives in Dir/<asset-name>/.
+const Dir = ".sx/overlays"
+
+// AppendSuffix marks an overlay file whose content is appended to the asset file
+// of the same name (e.g. SKILL.md.append) instead of replacing it
+const AppendSuffix = ".append"
+
+// Overlay is a set of local files applied on top of a vault asset at install time
+type Overlay struct {
+	// Path is the overlay directory
+	Path string
+	// Hash identifies the overlay's content, so changes can trigger a reinstall
+	Hash  string
+	files []file
+}
+
+type file struct {
+	name     string
+	content  []byte
+	mode     os.FileMode
+	appended bool
+}
+
+// Path returns the overlay directory for an asset in a repository
+func Path(repoRoot, assetName string) string {
+	return filepath.Join(repoRoot, Dir, assetName)
+}
+
+// Load reads the overlay for an asset, returning nil if the repository has none
+func Load(repoRoot, assetName string) (*Overlay, error) {
+	dir := Path(repoRoot, assetName)
+	if !utils.IsDirectory(dir) {
+		return nil, nil
+	}
+
+	o := &Overlay{Path: dir}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if utils.ShouldSkipPath(rel) {
+			return nil
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		f := file{name: rel, content: content, mode: info.Mode().Perm()}
+		if strings.HasSuffix(rel, AppendSuffix) {
+			f.name = strings.TrimSuffix(rel, AppendSuffix)
+			f.appended = true
+		}
+		if f.name == "metadata.toml" {
+			return fmt.Errorf("overlays can't change metadata.toml")
+		}
+		o.files = append(o.files, f)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overlay %s: %w", dir, err)
+	}
+
+	if len(o.files) == 0 {
+		return nil, nil
+	}
+
+	// Replacements go before appends to the same file
+	sort.Slice(o.files, func(i, j int) bool {
+		if o.files[i].name != o.files[j].name {
+			return o.files[i].name < o.files[j].name
+		}
+		return !o.files[i].appended && o.files[j].appended
+	})
+	o.Hash = hashFiles(o.files)
+	return o, nil
+}
+
+// hashFiles digests file names, modes and contents
+func hashFiles(files []file) string {
+	h := sha256.New()
+	for _, f := range files {
+		fmt.Fprintf(h, "%s\x00%t\x00%o\x00%d\x00", f.name, f.appended, f.mode, len(f.content))
+		h.Write(f.content)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// Files lists the asset files the overlay touches
+func (o *Overlay) Files() []string {
+	names := make([]string, len(o.files))
+	for i, f := range o.files {
+		names[i] = f.name
+	}
+	return names
+}
+
+// Apply returns zipData with the overlay's files replacing, or appended to, the
+// asset's files. Appending to a file the asset doesn't ship is an error.
+func (o *Overlay) Apply(zipData []byte) ([]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(zipData), int64(len(zipData)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zip: %w", err)
+	}
+
+	type entry struct {
+		header  zip.FileHeader
+		content []byte
+	}
+	var entries []*entry
+	byName := make(map[string]*entry)
+	for _, zf := range reader.File {
+		rc, err := zf.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", zf.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", zf.Name, err)
+		}
+		e := &entry{header: zf.FileHeader, content: content}
+		entries = append(entries, e)
+		byName[zf.Name] = e
+	}
+
+	for _, f := range o.files {
+		existing := byName[f.name]
+		if f.appended {
+			if existing == nil {
+				return nil, fmt.Errorf("cannot append to %s: the asset has no such file", f.name)
+			}
+			if len(existing.content) > 0 && !bytes.HasSuffix(existing.content, []byte("\n")) {
+				existing.content = append(existing.content, '\n')
+			}
+			existing.content = append(existing.content, f.content...)
+			continue
+		}
+
+		if existing == nil {
+			existing = &entry{header: zip.FileHeader{Name: f.name, Method: zip.Deflate}}
+			entries = append(entries, existing)
+			byName[f.name] = existing
+		}
+		existing.header.SetMode(f.mode)
+		existing.content = f.content
+	}
+
+	buf := new(bytes.Buffer)
+	writer := zip.NewWriter(buf)
+	for _, e := range entries {
+		header := e.header
+		w, err := writer.CreateHeader(&header)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s in zip: %w", header.Name, err)
+		}
+		if _, err := w.Write(e.content); err != nil {
+			return nil, fmt.Errorf("failed to write %s to zip: %w", header.Name, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close zip writer: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
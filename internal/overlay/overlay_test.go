@@ -0,0 +1,122 @@
+package overlay
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/utils"
+)
+
+func writeFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assetZip(t *testing.T) []byte {
+	t.Helper()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "metadata.toml"), "[asset]\nname = \"code-review\"\n", 0644)
+	writeFile(t, filepath.Join(dir, "SKILL.md"), "Review code", 0644)
+	writeFile(t, filepath.Join(dir, "docs", "style.md"), "vault style", 0644)
+	data, err := utils.CreateZip(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestLoadMissingOverlay(t *testing.T) {
+	o, err := Load(t.TempDir(), "code-review")
+	if err != nil || o != nil {
+		t.Errorf("Load() = %v, %v; want nil, nil", o, err)
+	}
+}
+
+func TestApplyReplacesAndAppends(t *testing.T) {
+	repo := t.TempDir()
+	dir := Path(repo, "code-review")
+	writeFile(t, filepath.Join(dir, "SKILL.md.append"), "Also check our conventions.\n", 0644)
+	writeFile(t, filepath.Join(dir, "docs", "style.md"), "repo style", 0644)
+	writeFile(t, filepath.Join(dir, "scripts", "check.sh"), "#!/bin/sh\n", 0755)
+
+	o, err := Load(repo, "code-review")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	patched, err := o.Apply(assetZip(t))
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	for file, want := range map[string]string{
+		"SKILL.md":         "Review code\nAlso check our conventions.\n",
+		"docs/style.md":    "repo style",
+		"scripts/check.sh": "#!/bin/sh\n",
+		"metadata.toml":    "[asset]\nname = \"code-review\"\n",
+	} {
+		got, err := utils.ReadZipFile(patched, file)
+		if err != nil {
+			t.Errorf("%s: %v", file, err)
+			continue
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", file, got, want)
+		}
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(patched), int64(len(patched)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range reader.File {
+		if f.Name == "scripts/check.sh" && f.Mode().Perm()&0100 == 0 {
+			t.Errorf("check.sh mode = %v, want executable", f.Mode())
+		}
+	}
+}
+
+func TestHashChangesWithContent(t *testing.T) {
+	repo := t.TempDir()
+	path := filepath.Join(Path(repo, "code-review"), "SKILL.md.append")
+	writeFile(t, path, "one", 0644)
+	first, err := Load(repo, "code-review")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, path, "two", 0644)
+	second, err := Load(repo, "code-review")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.Hash == "" || first.Hash == second.Hash {
+		t.Errorf("hashes = %q, %q; want distinct non-empty hashes", first.Hash, second.Hash)
+	}
+}
+
+func TestOverlayErrors(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(Path(repo, "meta"), "metadata.toml"), "", 0644)
+	if _, err := Load(repo, "meta"); err == nil {
+		t.Error("expected an error for an overlay that changes metadata.toml")
+	}
+
+	writeFile(t, filepath.Join(Path(repo, "code-review"), "missing.md.append"), "x", 0644)
+	o, err := Load(repo, "code-review")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.Apply(assetZip(t)); err == nil {
+		t.Error("expected an error appending to a file the asset doesn't ship")
+	}
+}
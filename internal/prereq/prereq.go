@@ -0,0 +1,114 @@
+// Package prereq checks that the tools an asset requires (its metadata
+// requires, e.g. "git>=2.30") are available on this machine.
+package prereq
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/metadata"
+)
+
+// versionTimeout bounds asking a tool for its version
+const versionTimeout = 5 * time.Second
+
+// versionArgs are tried in order until a tool prints something that looks like a version
+var versionArgs = []string{"--version", "version", "-version"}
+
+var versionRegex = regexp.MustCompile(`\d+\.\d+(\.\d+)?`)
+
+// tool is what was found out about an executable
+type tool struct {
+	found   bool
+	version *semver.Version
+}
+
+// Tools are looked up once per process, since many assets share requirements
+var (
+	mu    sync.Mutex
+	tools = make(map[string]tool)
+)
+
+// Check checks each requirement and returns a description of every one that
+// isn't met, such as "git not found on PATH". A tool whose version can't be
+// determined is assumed to satisfy its constraint.
+func Check(ctx context.Context, requires []string) []string {
+	var missing []string
+	for _, req := range requires {
+		if problem := checkOne(ctx, req); problem != "" {
+			missing = append(missing, problem)
+		}
+	}
+	return missing
+}
+
+func checkOne(ctx context.Context, req string) string {
+	name, constraint, err := metadata.ParseRequirement(req)
+	if err != nil {
+		return err.Error()
+	}
+
+	t := lookup(ctx, name)
+	if !t.found {
+		return fmt.Sprintf("%s not found on PATH", name)
+	}
+	if constraint == "" {
+		return ""
+	}
+
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return err.Error()
+	}
+	if t.version == nil {
+		logger.Get().Debug("could not determine tool version, assuming it's satisfied", "tool", name, "constraint", constraint)
+		return ""
+	}
+	if !c.Check(t.version) {
+		return fmt.Sprintf("%s %s doesn't satisfy %s", name, t.version, constraint)
+	}
+	return ""
+}
+
+func lookup(ctx context.Context, name string) tool {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if t, ok := tools[name]; ok {
+		return t
+	}
+
+	var t tool
+	if path, err := exec.LookPath(name); err == nil {
+		t.found = true
+		t.version = version(ctx, path)
+	}
+	tools[name] = t
+	return t
+}
+
+// version runs the tool with the common version flags and parses the first
+// version number it prints
+func version(ctx context.Context, path string) *semver.Version {
+	for _, arg := range versionArgs {
+		ctx, cancel := context.WithTimeout(ctx, versionTimeout)
+		out, err := exec.CommandContext(ctx, path, arg).CombinedOutput()
+		cancel()
+		if err != nil {
+			continue
+		}
+		if match := versionRegex.Find(out); match != nil {
+			if v, err := semver.NewVersion(string(match)); err == nil {
+				return v
+			}
+		}
+	}
+	return nil
+}
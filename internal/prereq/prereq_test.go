@@ -0,0 +1,30 @@
+package prereq
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+
+	if missing := Check(ctx, []string{"git", "git>=1.0"}); len(missing) != 0 {
+		t.Errorf("Expected git requirements to be met, got %v", missing)
+	}
+
+	missing := Check(ctx, []string{"git>=999", "sx-no-such-tool"})
+	if len(missing) != 2 {
+		t.Fatalf("Expected 2 missing requirements, got %v", missing)
+	}
+	if !strings.Contains(missing[0], "doesn't satisfy >=999") {
+		t.Errorf("Expected version problem for git, got %q", missing[0])
+	}
+	if missing[1] != "sx-no-such-tool not found on PATH" {
+		t.Errorf("Expected missing tool, got %q", missing[1])
+	}
+}
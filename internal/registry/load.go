@@ -0,0 +1,118 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sleuth-io/sx/internal/cache"
+)
+
+// VaultPrefix marks a registry source as a path inside the configured vault
+// (e.g. "vault:registry.yaml").
+const VaultPrefix = "vault:"
+
+// DefaultVaultIndex is the vault path checked when no registries are configured.
+const DefaultVaultIndex = VaultPrefix + "registry.yaml"
+
+// VaultReader reads a file from the configured vault.
+type VaultReader func(ctx context.Context, path string) ([]byte, error)
+
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+// Load reads the skills from each source in order. A source is an http(s) URL, a
+// local path or file:// URL, or a "vault:" path read with readVault (which may be
+// nil if the vault can't serve files). When several indexes list the same name,
+// the first one wins.
+// Sources that fail are skipped; their errors are joined into the returned error,
+// so callers can show the skills that did load and warn about the rest.
+func Load(ctx context.Context, sources []string, readVault VaultReader) ([]Skill, error) {
+	var skills []Skill
+	var errs []error
+	seen := make(map[string]bool)
+
+	for _, source := range sources {
+		data, err := readSource(ctx, source, readVault)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("registry %s: %w", source, err))
+			continue
+		}
+
+		entries, err := ParseIndex(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("registry %s: %w", source, err))
+			continue
+		}
+
+		for _, skill := range entries {
+			key := strings.ToLower(skill.Name)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			skill.Registry = source
+			skills = append(skills, skill)
+		}
+	}
+
+	return skills, errors.Join(errs...)
+}
+
+func readSource(ctx context.Context, source string, readVault VaultReader) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(source, VaultPrefix):
+		if readVault == nil {
+			return nil, fmt.Errorf("the configured vault can't serve registry files")
+		}
+		return readVault(ctx, strings.TrimPrefix(source, VaultPrefix))
+	case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"):
+		return fetchRemote(ctx, source)
+	default:
+		return os.ReadFile(strings.TrimPrefix(source, "file://"))
+	}
+}
+
+// fetchRemote downloads an index, falling back to the last cached copy when offline
+func fetchRemote(ctx context.Context, indexURL string) ([]byte, error) {
+	data, err := download(ctx, indexURL)
+	if err != nil {
+		if cached, cacheErr := cache.LoadRegistryIndex(indexURL); cacheErr == nil {
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	// Caching is best effort; it only matters offline
+	if _, err := ParseIndex(data); err == nil {
+		_ = cache.SaveRegistryIndex(indexURL, data)
+	}
+	return data, nil
+}
+
+func download(ctx context.Context, indexURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, indexURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch registry: HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry: %w", err)
+	}
+	return data, nil
+}
@@ -3,8 +3,12 @@ package registry
 
 import (
 	_ "embed"
+	"fmt"
+	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/sleuth-io/sx/internal/github"
 )
 
 //go:embed featured.yaml
@@ -12,16 +16,83 @@ var featuredYAML []byte
 
 // Skill represents a skill in the registry.
 type Skill struct {
-	Name        string `yaml:"name"`
-	Description string `yaml:"description"`
-	URL         string `yaml:"url"`
+	Name        string   `yaml:"name" json:"name"`
+	Description string   `yaml:"description" json:"description"`
+	URL         string   `yaml:"url" json:"url"`
+	Category    string   `yaml:"category,omitempty" json:"category,omitempty"`
+	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	// Commit pins the skill to a commit SHA instead of the branch in URL
+	Commit string `yaml:"commit,omitempty" json:"commit,omitempty"`
+	// Registry is the index the skill was loaded from (set by Load)
+	Registry string `yaml:"-" json:"registry,omitempty"`
+}
+
+// index is the mapping form of a registry file; a plain list of skills is also accepted
+type index struct {
+	Skills []Skill `yaml:"skills"`
 }
 
 // FeaturedSkills returns the list of featured skills.
 func FeaturedSkills() ([]Skill, error) {
+	return ParseIndex(featuredYAML)
+}
+
+// ParseIndex parses a registry index: either a list of skills or a mapping with a
+// "skills" list.
+func ParseIndex(data []byte) ([]Skill, error) {
 	var skills []Skill
-	if err := yaml.Unmarshal(featuredYAML, &skills); err != nil {
-		return nil, err
+	if err := yaml.Unmarshal(data, &skills); err != nil {
+		var idx index
+		if err := yaml.Unmarshal(data, &idx); err != nil {
+			return nil, fmt.Errorf("invalid registry index: %w", err)
+		}
+		skills = idx.Skills
+	}
+
+	for i, skill := range skills {
+		if skill.Name == "" || skill.URL == "" {
+			return nil, fmt.Errorf("invalid registry index: entry %d needs a name and url", i+1)
+		}
 	}
 	return skills, nil
 }
+
+// InstallURL returns the URL to add the skill from, pinned to Commit when set.
+func (s *Skill) InstallURL() string {
+	if s.Commit == "" {
+		return s.URL
+	}
+	treeURL := github.ParseTreeURL(s.URL)
+	if treeURL == nil {
+		return s.URL
+	}
+	treeURL.Ref = s.Commit
+	return treeURL.String()
+}
+
+// Matches reports whether the skill's name, description, category, or tags
+// contain term (case-insensitive). An empty term matches everything.
+func (s *Skill) Matches(term string) bool {
+	term = strings.ToLower(strings.TrimSpace(term))
+	if term == "" {
+		return true
+	}
+
+	fields := append([]string{s.Name, s.Description, s.Category}, s.Tags...)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), term) {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the skill with the given name (case-insensitive), or nil.
+func Find(skills []Skill, name string) *Skill {
+	for i := range skills {
+		if strings.EqualFold(skills[i].Name, name) {
+			return &skills[i]
+		}
+	}
+	return nil
+}
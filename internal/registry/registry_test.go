@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const companyIndex = `skills:
+  - name: deploy-checklist
+    description: Pre-deploy checks for our services
+    url: https://github.com/acme/skills/tree/main/deploy-checklist
+    category: Operations
+    tags: [deploy, release]
+    commit: 0123456789abcdef0123456789abcdef01234567
+  - name: code-review
+    description: Our review standards
+    url: https://github.com/acme/skills/tree/main/code-review
+`
+
+func TestFeaturedSkillsParse(t *testing.T) {
+	skills, err := FeaturedSkills()
+	if err != nil || len(skills) == 0 {
+		t.Fatalf("FeaturedSkills() = %d skills, err=%v", len(skills), err)
+	}
+}
+
+func TestSkillInstallURLAndMatches(t *testing.T) {
+	skills, err := ParseIndex([]byte(companyIndex))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pinned := Find(skills, "Deploy-Checklist")
+	if pinned == nil {
+		t.Fatal("expected case-insensitive Find to match")
+	}
+	want := "https://github.com/acme/skills/tree/0123456789abcdef0123456789abcdef01234567/deploy-checklist"
+	if got := pinned.InstallURL(); got != want {
+		t.Errorf("InstallURL() = %q, want %q", got, want)
+	}
+	if got := skills[1].InstallURL(); got != skills[1].URL {
+		t.Errorf("unpinned InstallURL() = %q", got)
+	}
+
+	for term, expected := range map[string]bool{"release": true, "operations": true, "standards": true, "kubernetes": false, "": true} {
+		if got := pinned.Matches(term) || skills[1].Matches(term); got != expected {
+			t.Errorf("Matches(%q) = %v, want %v", term, got, expected)
+		}
+	}
+}
+
+func TestLoadMergesSourcesAndFallsBackToCache(t *testing.T) {
+	t.Setenv("SX_CACHE_DIR", t.TempDir())
+	ctx := context.Background()
+
+	online := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !online {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("- name: code-review\n  description: upstream\n  url: https://github.com/up/skills/tree/main/code-review\n- name: remote-only\n  description: from the web\n  url: https://github.com/up/skills/tree/main/remote-only\n"))
+	}))
+	defer server.Close()
+
+	vaultReader := func(ctx context.Context, path string) ([]byte, error) {
+		if path != "registry.yaml" {
+			return nil, os.ErrNotExist
+		}
+		return []byte(companyIndex), nil
+	}
+
+	localIndex := filepath.Join(t.TempDir(), "local.yaml")
+	if err := os.WriteFile(localIndex, []byte("not: [valid"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	sources := []string{DefaultVaultIndex, server.URL, localIndex}
+	skills, err := Load(ctx, sources, vaultReader)
+	if err == nil {
+		t.Error("expected the invalid local index to be reported")
+	}
+	if len(skills) != 3 {
+		t.Fatalf("expected 3 skills, got %+v", skills)
+	}
+	// The vault index comes first, so its code-review wins over upstream's
+	if review := Find(skills, "code-review"); review.Description != "Our review standards" || review.Registry != DefaultVaultIndex {
+		t.Errorf("expected the vault's code-review, got %+v", review)
+	}
+
+	// Offline: the cached copy of the remote index is used
+	online = false
+	skills, err = Load(ctx, []string{server.URL}, nil)
+	if err != nil || Find(skills, "remote-only") == nil {
+		t.Errorf("expected cached remote index, got %+v (err=%v)", skills, err)
+	}
+
+	// Vault sources need a reader
+	if _, err := Load(ctx, []string{DefaultVaultIndex}, nil); err == nil {
+		t.Error("expected an error for a vault source without a reader")
+	}
+	if _, err := Load(ctx, []string{"vault:missing.yaml"}, vaultReader); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected a wrapped not-exist error, got %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+// Package scaffold generates new asset directories for 'sx new'.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/assets/detectors"
+	"github.com/sleuth-io/sx/internal/metadata"
+)
+
+//go:embed templates
+var builtinTemplates embed.FS
+
+// VaultTemplateDir is where org-defined templates live in a vault:
+// templates/<type>/<file>.tmpl overrides the built-in template for that file.
+const VaultTemplateDir = "templates"
+
+// DefaultVersion is the version new assets start at
+const DefaultVersion = "0.1.0"
+
+// Options describe the asset to generate
+type Options struct {
+	Type        asset.Type
+	Name        string
+	Description string
+	// Triggers are situations in which a skill or agent should be used
+	Triggers []string
+	// Event is the hook event (hook assets)
+	Event string
+	// Command and Args launch an MCP server (mcp and mcp-remote assets)
+	Command string
+	Args    []string
+	// URL and Transport register a remote server instead (mcp-remote assets)
+	URL       string
+	Transport string
+}
+
+// TemplateReader reads an org template by path relative to the vault root
+// It returns an error satisfying errors.Is(err, fs.ErrNotExist) when there is none.
+type TemplateReader func(path string) ([]byte, error)
+
+// templateData is what templates are rendered with
+type templateData struct {
+	Options
+	// Title is the name in title case (e.g. "code-review" -> "Code Review")
+	Title string
+}
+
+// filesByType lists the files generated for each asset type, besides metadata.toml
+var filesByType = map[string][]string{
+	asset.TypeSkill.Key:     {"SKILL.md", "README.md"},
+	asset.TypeCommand.Key:   {"COMMAND.md", "README.md"},
+	asset.TypeAgent.Key:     {"AGENT.md", "README.md"},
+	asset.TypeHook.Key:      {"hook.sh", "README.md"},
+	asset.TypeMCP.Key:       {"README.md"},
+	asset.TypeMCPRemote.Key: {"README.md"},
+}
+
+// Metadata builds the metadata.toml for opts from the type's defaults
+func Metadata(opts Options) (*metadata.Metadata, error) {
+	meta := detectors.CreateDefaultMetadataForType(opts.Type.Key, opts.Name, DefaultVersion)
+	if meta == nil {
+		return nil, fmt.Errorf("unknown asset type: %s", opts.Type.Key)
+	}
+
+	meta.Asset.Description = opts.Description
+	switch {
+	case meta.Skill != nil:
+		meta.Skill.Triggers = opts.Triggers
+	case meta.Agent != nil:
+		meta.Agent.Triggers = opts.Triggers
+	case meta.Hook != nil:
+		if opts.Event != "" {
+			meta.Hook.Event = opts.Event
+		}
+	case meta.MCP != nil && opts.URL != "":
+		meta.MCP.URL = opts.URL
+		meta.MCP.Transport = opts.Transport
+	case meta.MCP != nil:
+		meta.MCP.Command = opts.Command
+		meta.MCP.Args = opts.Args
+		if !strings.Contains(opts.Command, "/") {
+			meta.MCP.Requires = []string{opts.Command}
+		}
+	}
+
+	if err := meta.Validate(); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// Generate writes a new asset into dir, which must not exist or be empty
+// Returns the generated file names.
+func Generate(dir string, opts Options, readTemplate TemplateReader) ([]string, error) {
+	meta, err := Metadata(opts)
+	if err != nil {
+		return nil, err
+	}
+	opts.Event = eventOf(meta)
+
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("directory %s already exists and is not empty", dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	if err := metadata.Write(meta, filepath.Join(dir, "metadata.toml")); err != nil {
+		return nil, err
+	}
+	files := []string{"metadata.toml"}
+
+	data := templateData{Options: opts, Title: titleCase(opts.Name)}
+	for _, name := range filesByType[opts.Type.Key] {
+		content, err := render(opts.Type.Key, name, data, readTemplate)
+		if err != nil {
+			return nil, err
+		}
+
+		mode := os.FileMode(0644)
+		if strings.HasSuffix(name, ".sh") {
+			mode = 0755
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), content, mode); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		files = append(files, name)
+	}
+
+	return files, nil
+}
+
+// render executes the template for a file, preferring the org's version in the vault
+func render(typeKey, name string, data templateData, readTemplate TemplateReader) ([]byte, error) {
+	source, origin, err := loadTemplate(typeKey, name, readTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", origin, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %w", origin, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// loadTemplate looks up templates/<type>/<file>.tmpl in the vault, then the built-in
+// per-type template, then the built-in shared one
+func loadTemplate(typeKey, name string, readTemplate TemplateReader) (source []byte, origin string, err error) {
+	typed := VaultTemplateDir + "/" + typeKey + "/" + name + ".tmpl"
+	if readTemplate != nil {
+		data, err := readTemplate(typed)
+		if err == nil {
+			return data, "vault:" + typed, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, "", fmt.Errorf("failed to read template %s from vault: %w", typed, err)
+		}
+	}
+
+	for _, path := range []string{typed, VaultTemplateDir + "/" + name + ".tmpl"} {
+		if data, err := builtinTemplates.ReadFile(path); err == nil {
+			return data, path, nil
+		}
+	}
+	return nil, "", fmt.Errorf("no template for %s", name)
+}
+
+func eventOf(meta *metadata.Metadata) string {
+	if meta.Hook != nil {
+		return meta.Hook.Event
+	}
+	return ""
+}
+
+func titleCase(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' })
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,167 @@
+package scaffold
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/metadata"
+)
+
+func TestGenerateEachType(t *testing.T) {
+	tests := []struct {
+		opts  Options
+		files []string
+	}{
+		{Options{Type: asset.TypeSkill, Name: "code-review", Description: "Review code", Triggers: []string{"Reviewing a PR"}}, []string{"metadata.toml", "SKILL.md", "README.md"}},
+		{Options{Type: asset.TypeCommand, Name: "deploy", Description: "Deploy the app"}, []string{"metadata.toml", "COMMAND.md", "README.md"}},
+		{Options{Type: asset.TypeAgent, Name: "reviewer", Description: "Reviews code"}, []string{"metadata.toml", "AGENT.md", "README.md"}},
+		{Options{Type: asset.TypeHook, Name: "lint", Description: "Lint staged files", Event: "pre-push"}, []string{"metadata.toml", "hook.sh", "README.md"}},
+		{Options{Type: asset.TypeMCP, Name: "github", Description: "GitHub tools", Command: "npx", Args: []string{"-y", "server-github"}}, []string{"metadata.toml", "README.md"}},
+		{Options{Type: asset.TypeMCPRemote, Name: "linear", Description: "Linear", Command: "npx", Args: []string{"-y", "mcp-remote", "https://mcp.linear.app/sse"}}, []string{"metadata.toml", "README.md"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.opts.Type.Key, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), tt.opts.Name)
+
+			files, err := Generate(dir, tt.opts, nil)
+			if err != nil {
+				t.Fatalf("Generate failed: %v", err)
+			}
+			if strings.Join(files, ",") != strings.Join(tt.files, ",") {
+				t.Errorf("files = %v, want %v", files, tt.files)
+			}
+
+			meta, err := metadata.ParseFile(filepath.Join(dir, "metadata.toml"))
+			if err != nil {
+				t.Fatalf("failed to parse metadata: %v", err)
+			}
+			if err := meta.Validate(); err != nil {
+				t.Errorf("generated metadata is invalid: %v", err)
+			}
+			if meta.Asset.Type != tt.opts.Type || meta.Asset.Version != DefaultVersion {
+				t.Errorf("asset = %s %s, want %s %s", meta.Asset.Type.Key, meta.Asset.Version, tt.opts.Type.Key, DefaultVersion)
+			}
+
+			readme, err := os.ReadFile(filepath.Join(dir, "README.md"))
+			if err != nil {
+				t.Fatalf("failed to read README: %v", err)
+			}
+			if !strings.Contains(string(readme), tt.opts.Description) {
+				t.Errorf("README missing description:\n%s", readme)
+			}
+		})
+	}
+}
+
+func TestGenerateSkillContent(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{Type: asset.TypeSkill, Name: "code-review", Description: "Review code", Triggers: []string{"Reviewing a PR"}}
+
+	if _, err := Generate(dir, opts, nil); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	for _, want := range []string{"name: code-review\n", "description: Review code\n", "# Code Review", "- Reviewing a PR\n\n## Instructions"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("SKILL.md missing %q:\n%s", want, content)
+		}
+	}
+
+	meta, err := metadata.ParseFile(filepath.Join(dir, "metadata.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.Skill.Triggers) != 1 {
+		t.Errorf("Triggers = %v, want one trigger", meta.Skill.Triggers)
+	}
+}
+
+func TestGenerateHookIsExecutable(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{Type: asset.TypeHook, Name: "lint", Description: "Lint", Event: "pre-push"}
+
+	if _, err := Generate(dir, opts, nil); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "hook.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm()&0100 == 0 {
+		t.Errorf("hook.sh mode = %v, want executable", info.Mode().Perm())
+	}
+	script, _ := os.ReadFile(filepath.Join(dir, "hook.sh"))
+	if !strings.Contains(string(script), "pre-push") {
+		t.Errorf("hook.sh doesn't mention its event:\n%s", script)
+	}
+}
+
+func TestGenerateRejectsNonEmptyDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "existing.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Generate(dir, Options{Type: asset.TypeSkill, Name: "x", Description: "x"}, nil)
+	if err == nil {
+		t.Fatal("expected error for non-empty directory")
+	}
+}
+
+func TestGenerateInvalidMetadata(t *testing.T) {
+	_, err := Generate(t.TempDir(), Options{Type: asset.TypeHook, Name: "lint", Description: "x", Event: "on-save"}, nil)
+	if err == nil {
+		t.Fatal("expected error for invalid hook event")
+	}
+}
+
+func TestGenerateVaultTemplateOverride(t *testing.T) {
+	dir := t.TempDir()
+	var requested []string
+	readTemplate := func(path string) ([]byte, error) {
+		requested = append(requested, path)
+		if path == "templates/skill/SKILL.md.tmpl" {
+			return []byte("ACME skill {{.Name}}\n"), nil
+		}
+		return nil, fs.ErrNotExist
+	}
+
+	if _, err := Generate(dir, Options{Type: asset.TypeSkill, Name: "acme", Description: "x"}, readTemplate); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	skill, _ := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if string(skill) != "ACME skill acme\n" {
+		t.Errorf("SKILL.md = %q, want vault template output", skill)
+	}
+	readme, _ := os.ReadFile(filepath.Join(dir, "README.md"))
+	if !strings.Contains(string(readme), "# acme") {
+		t.Errorf("README should fall back to the built-in template:\n%s", readme)
+	}
+	if len(requested) != 2 {
+		t.Errorf("requested = %v, want the vault checked for both files", requested)
+	}
+}
+
+func TestGenerateVaultTemplateError(t *testing.T) {
+	readTemplate := func(path string) ([]byte, error) {
+		return nil, errors.New("vault unavailable")
+	}
+
+	_, err := Generate(t.TempDir(), Options{Type: asset.TypeSkill, Name: "acme", Description: "x"}, readTemplate)
+	if err == nil || !strings.Contains(err.Error(), "vault unavailable") {
+		t.Fatalf("err = %v, want vault error", err)
+	}
+}
@@ -2,6 +2,7 @@ package scope
 
 import (
 	"net/url"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -25,6 +26,7 @@ type Scope struct {
 	Type     lockfile.ScopeType // TypeGlobal, TypeRepo, or TypePath
 	RepoURL  string             // Repository URL (if in a repo)
 	RepoPath string             // Path relative to repo root (if applicable)
+	Branch   string             // Current branch (empty if unknown or detached)
 }
 
 // NewMatcher creates a new scope matcher
@@ -66,8 +68,22 @@ func (m *Matcher) matchesRepository(repo *lockfile.Scope) bool {
 		return false
 	}
 
-	// If repository has no paths, it matches the entire repo
-	if len(repo.Paths) == 0 {
+	// Branch conditions apply on top of the repo and paths
+	if !m.matchesBranch(repo.Branches) {
+		return false
+	}
+
+	includes, excludes := splitPatterns(repo.Paths)
+
+	// Excluded paths win over included ones
+	for _, path := range excludes {
+		if m.matchesPath(path) {
+			return false
+		}
+	}
+
+	// If repository has no included paths, it matches the entire repo
+	if len(includes) == 0 {
 		return true
 	}
 
@@ -76,7 +92,7 @@ func (m *Matcher) matchesRepository(repo *lockfile.Scope) bool {
 		return false
 	}
 
-	for _, path := range repo.Paths {
+	for _, path := range includes {
 		if m.matchesPath(path) {
 			return true
 		}
@@ -91,27 +107,105 @@ func (m *Matcher) matchesRepoURL(assetRepo string) bool {
 		return false
 	}
 
-	// Normalize both URLs for comparison
-	currentNormalized := NormalizeRepoURL(m.currentScope.RepoURL)
-	assetNormalized := NormalizeRepoURL(assetRepo)
-
-	return currentNormalized == assetNormalized
+	return MatchRepoPattern(m.currentScope.RepoURL, assetRepo)
 }
 
 // matchesPath checks if the asset's path matches the current path
 func (m *Matcher) matchesPath(assetPath string) bool {
+	_, ok := m.matchPathPrefix(assetPath)
+	return ok
+}
+
+// matchPathPrefix checks if the current path is within or equal to a path
+// matching assetPath, and returns that path. For example, if the asset is
+// scoped to "services/*/api" and we're in "services/users/api/handlers", it
+// matches with "services/users/api".
+func (m *Matcher) matchPathPrefix(assetPath string) (string, bool) {
 	if m.currentScope.RepoPath == "" || assetPath == "" {
-		return false
+		return "", false
 	}
 
-	// Normalize paths
-	currentPath := normalizeRepoPath(m.currentScope.RepoPath)
-	assetPath = normalizeRepoPath(assetPath)
+	currentParts := strings.Split(normalizeRepoPath(m.currentScope.RepoPath), "/")
+	patternParts := strings.Split(normalizeRepoPath(assetPath), "/")
+	if len(currentParts) < len(patternParts) {
+		return "", false
+	}
+
+	for i, pattern := range patternParts {
+		if ok, err := path.Match(pattern, currentParts[i]); err != nil || !ok {
+			return "", false
+		}
+	}
+
+	return strings.Join(currentParts[:len(patternParts)], "/"), true
+}
+
+// matchesBranch checks the current branch against branch patterns. An unknown
+// branch only matches when no branches are required.
+func (m *Matcher) matchesBranch(branches []string) bool {
+	includes, excludes := splitPatterns(branches)
+	branch := m.currentScope.Branch
+
+	for _, pattern := range excludes {
+		if branch != "" && matchGlob(pattern, branch) {
+			return false
+		}
+	}
+
+	if len(includes) == 0 {
+		return true
+	}
+
+	for _, pattern := range includes {
+		if branch != "" && matchGlob(pattern, branch) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitPatterns separates patterns into inclusions and !-prefixed exclusions
+func splitPatterns(patterns []string) (includes, excludes []string) {
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			excludes = append(excludes, strings.TrimPrefix(pattern, "!"))
+		} else {
+			includes = append(includes, pattern)
+		}
+	}
+	return includes, excludes
+}
+
+func matchGlob(pattern, name string) bool {
+	ok, err := path.Match(pattern, name)
+	return err == nil && ok
+}
+
+// isPattern checks if a scope value contains glob characters
+func isPattern(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
+
+// MatchRepoPattern checks if a repository URL matches a scope's repo, which is
+// either a URL or a glob over the normalized URL (e.g. github.com/my-org/*).
+// A pattern without a host, such as my-org/*, matches on any host.
+func MatchRepoPattern(repoURL, pattern string) bool {
+	if !isPattern(pattern) {
+		return MatchRepoURLs(repoURL, pattern)
+	}
+
+	normalizedPattern := NormalizeRepoURL(pattern)
+	normalizedURL := NormalizeRepoURL(repoURL)
+
+	// Hosts contain a dot; an owner/repo pattern is matched against the path only
+	if first, _, _ := strings.Cut(normalizedPattern, "/"); !strings.Contains(first, ".") {
+		if _, rest, found := strings.Cut(normalizedURL, "/"); found {
+			normalizedURL = rest
+		}
+	}
 
-	// Check if current path is within or equal to asset path
-	// For example, if asset is scoped to "services/api"
-	// and we're in "services/api/handlers", it should match
-	return strings.HasPrefix(currentPath, assetPath) || currentPath == assetPath
+	return matchGlob(normalizedPattern, normalizedURL)
 }
 
 // MatchRepoURLs checks if two repository URLs refer to the same repository
@@ -215,11 +309,11 @@ func GetInstallLocations(asset *lockfile.Asset, currentScope *Scope, repoRoot, g
 			continue
 		}
 
-		// If repository has paths, install to each path
-		if len(repo.Paths) > 0 {
-			for _, path := range repo.Paths {
-				if matcher.matchesPath(path) {
-					locations = append(locations, filepath.Join(repoRoot, path, ".claude"))
+		// If repository has paths, install to each matching path
+		if includes, _ := splitPatterns(repo.Paths); len(includes) > 0 {
+			for _, pattern := range includes {
+				if matched, ok := matcher.matchPathPrefix(pattern); ok {
+					locations = append(locations, filepath.Join(repoRoot, matched, ".claude"))
 				}
 			}
 		} else {
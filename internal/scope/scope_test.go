@@ -176,3 +176,60 @@ func TestGetInstallLocations(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchesAssetPatterns(t *testing.T) {
+	repoScope := func(path, branch string) *Scope {
+		s := &Scope{Type: TypeRepo, RepoURL: "git@github.com:my-org/api.git", Branch: branch}
+		if path != "" {
+			s.Type = TypePath
+			s.RepoPath = path
+		}
+		return s
+	}
+
+	tests := []struct {
+		name  string
+		scope *Scope
+		entry lockfile.Scope
+		want  bool
+	}{
+		{"org pattern", repoScope("", "main"), lockfile.Scope{Repo: "github.com/my-org/*"}, true},
+		{"org pattern with scheme", repoScope("", "main"), lockfile.Scope{Repo: "https://github.com/my-org/*"}, true},
+		{"org pattern without host", repoScope("", "main"), lockfile.Scope{Repo: "my-org/*"}, true},
+		{"other org", repoScope("", "main"), lockfile.Scope{Repo: "github.com/other-org/*"}, false},
+		{"glob path", repoScope("services/users/api/handlers", "main"), lockfile.Scope{Repo: "my-org/*", Paths: []string{"services/*/api"}}, true},
+		{"glob path elsewhere", repoScope("services/users/web", "main"), lockfile.Scope{Repo: "my-org/*", Paths: []string{"services/*/api"}}, false},
+		{"path prefix is per segment", repoScope("services/api2", "main"), lockfile.Scope{Repo: "my-org/*", Paths: []string{"services/api"}}, false},
+		{"exclusion only, at root", repoScope("", "main"), lockfile.Scope{Repo: "my-org/*", Paths: []string{"!services/legacy"}}, true},
+		{"exclusion only, in excluded path", repoScope("services/legacy/db", "main"), lockfile.Scope{Repo: "my-org/*", Paths: []string{"!services/legacy"}}, false},
+		{"exclusion wins", repoScope("services/legacy", "main"), lockfile.Scope{Repo: "my-org/*", Paths: []string{"services/*", "!services/legacy"}}, false},
+		{"branch", repoScope("", "release/1.2"), lockfile.Scope{Repo: "my-org/*", Branches: []string{"release/*"}}, true},
+		{"other branch", repoScope("", "main"), lockfile.Scope{Repo: "my-org/*", Branches: []string{"release/*"}}, false},
+		{"unknown branch", repoScope("", ""), lockfile.Scope{Repo: "my-org/*", Branches: []string{"release/*"}}, false},
+		{"excluded branch", repoScope("", "main"), lockfile.Scope{Repo: "my-org/*", Branches: []string{"!main"}}, false},
+		{"excluded branch, unknown branch", repoScope("", ""), lockfile.Scope{Repo: "my-org/*", Branches: []string{"!main"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asset := &lockfile.Asset{Name: "test", Scopes: []lockfile.Scope{tt.entry}}
+			if got := NewMatcher(tt.scope).MatchesAsset(asset); got != tt.want {
+				t.Errorf("MatchesAsset() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetInstallLocationsGlobPath(t *testing.T) {
+	asset := &lockfile.Asset{
+		Name:   "test",
+		Scopes: []lockfile.Scope{{Repo: "https://github.com/test/repo", Paths: []string{"services/*/api"}}},
+	}
+	currentScope := &Scope{Type: TypePath, RepoURL: "https://github.com/test/repo", RepoPath: "services/users/api/handlers"}
+
+	got := GetInstallLocations(asset, currentScope, "/repo", "/global")
+	want := filepath.Join("/repo", "services/users/api", ".claude")
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("GetInstallLocations() = %v, want [%s]", got, want)
+	}
+}
@@ -0,0 +1,243 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sleuth-io/sx/internal/buildinfo"
+)
+
+const (
+	serviceName = "sx"
+	scopeName   = "github.com/sleuth-io/sx"
+
+	// OTLP status codes and span kinds (see opentelemetry-proto)
+	statusCodeOK     = 1
+	statusCodeError  = 2
+	spanKindInternal = 1
+
+	// severityInfo is the OTLP severity number for INFO
+	severityInfo = 9
+)
+
+// otlpClient posts OTLP/HTTP JSON payloads to a collector
+type otlpClient struct {
+	endpoint   string
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+func newOTLPClient(endpoint string, headers map[string]string) *otlpClient {
+	return &otlpClient{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		headers:  headers,
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+}
+
+// OTLP JSON wire types (only the fields sx emits)
+
+type otlpAnyValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpTracesRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+// exportSpans sends spans to {endpoint}/v1/traces
+func (c *otlpClient) exportSpans(ctx context.Context, spans []*Span) error {
+	wireSpans := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		status := otlpStatus{Code: statusCodeOK}
+		if s.err != nil {
+			status = otlpStatus{Code: statusCodeError, Message: s.err.Error()}
+		}
+		wireSpans = append(wireSpans, otlpSpan{
+			TraceID:           s.traceID,
+			SpanID:            s.spanID,
+			ParentSpanID:      s.parentID,
+			Name:              s.name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: unixNano(s.start),
+			EndTimeUnixNano:   unixNano(s.end),
+			Attributes:        toKeyValues(s.attrs),
+			Status:            status,
+		})
+	}
+
+	req := otlpTracesRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: resource(),
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: scopeName, Version: buildinfo.Version},
+				Spans: wireSpans,
+			}},
+		}},
+	}
+
+	return c.post(ctx, "/v1/traces", req)
+}
+
+// exportLogs sends log records to {endpoint}/v1/logs
+func (c *otlpClient) exportLogs(ctx context.Context, records []logRecord) error {
+	observed := unixNano(time.Now())
+	wireRecords := make([]otlpLogRecord, 0, len(records))
+	for _, r := range records {
+		wireRecords = append(wireRecords, otlpLogRecord{
+			TimeUnixNano:         unixNano(r.timestamp),
+			ObservedTimeUnixNano: observed,
+			SeverityNumber:       severityInfo,
+			SeverityText:         "INFO",
+			Body:                 otlpAnyValue{StringValue: r.body},
+			Attributes:           toKeyValues(r.attrs),
+		})
+	}
+
+	req := otlpLogsRequest{
+		ResourceLogs: []otlpResourceLogs{{
+			Resource: resource(),
+			ScopeLogs: []otlpScopeLogs{{
+				Scope:      otlpScope{Name: scopeName, Version: buildinfo.Version},
+				LogRecords: wireRecords,
+			}},
+		}},
+	}
+
+	return c.post(ctx, "/v1/logs", req)
+}
+
+// post marshals payload as JSON and sends it to the collector
+func (c *otlpClient) post(ctx context.Context, path string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal OTLP payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", buildinfo.GetUserAgent())
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send OTLP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("collector returned HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
+
+// resource describes the process emitting telemetry
+func resource() otlpResource {
+	return otlpResource{
+		Attributes: []otlpKeyValue{
+			{Key: "service.name", Value: otlpAnyValue{StringValue: serviceName}},
+			{Key: "service.version", Value: otlpAnyValue{StringValue: buildinfo.Version}},
+		},
+	}
+}
+
+// toKeyValues converts attributes to OTLP key/values in a stable order
+func toKeyValues(attrs map[string]string) []otlpKeyValue {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]otlpKeyValue, 0, len(keys))
+	for _, k := range keys {
+		kvs = append(kvs, otlpKeyValue{Key: k, Value: otlpAnyValue{StringValue: attrs[k]}})
+	}
+	return kvs
+}
+
+// unixNano formats t as the decimal string OTLP JSON uses for 64-bit timestamps
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
@@ -0,0 +1,219 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/sleuth-io/sx/internal/logger"
+	"github.com/sleuth-io/sx/internal/stats"
+)
+
+// Exporter buffers spans and log records and ships them to an OTLP/HTTP collector.
+// A nil or disabled exporter is safe to use; every method becomes a no-op.
+type Exporter struct {
+	endpoint string
+	headers  map[string]string
+	client   *otlpClient
+
+	mu      sync.Mutex
+	spans   []*Span
+	records []logRecord
+}
+
+var (
+	global   *Exporter
+	globalMu sync.Mutex
+)
+
+// Init configures the global exporter for the given collector endpoint
+// An empty endpoint disables telemetry
+func Init(endpoint string, headers map[string]string) *Exporter {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+
+	global = New(endpoint, headers)
+	return global
+}
+
+// Get returns the global exporter (disabled until Init is called with an endpoint)
+func Get() *Exporter {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+
+	if global == nil {
+		global = New("", nil)
+	}
+	return global
+}
+
+// New creates an exporter for the given collector endpoint
+// The endpoint is the collector base URL, e.g. http://localhost:4318
+func New(endpoint string, headers map[string]string) *Exporter {
+	e := &Exporter{
+		endpoint: endpoint,
+		headers:  headers,
+	}
+	if endpoint != "" {
+		e.client = newOTLPClient(endpoint, headers)
+	}
+	return e
+}
+
+// Enabled reports whether telemetry is being exported
+func (e *Exporter) Enabled() bool {
+	return e != nil && e.client != nil
+}
+
+// Span is a single timed operation, e.g. one phase of an install
+type Span struct {
+	exporter *Exporter
+	traceID  string
+	spanID   string
+	parentID string
+	name     string
+	start    time.Time
+	end      time.Time
+	attrs    map[string]string
+	err      error
+	ended    bool
+}
+
+type spanKeyType struct{}
+
+var spanKey = spanKeyType{}
+
+// StartSpan starts a span as a child of the span in ctx (if any)
+// The returned context carries the new span so nested phases are linked
+func (e *Exporter) StartSpan(ctx context.Context, name string, attrs ...string) (context.Context, *Span) {
+	if !e.Enabled() {
+		return ctx, nil
+	}
+
+	span := &Span{
+		exporter: e,
+		spanID:   randomHex(8),
+		name:     name,
+		start:    time.Now(),
+		attrs:    pairsToMap(attrs),
+	}
+
+	if parent, ok := ctx.Value(spanKey).(*Span); ok && parent != nil {
+		span.traceID = parent.traceID
+		span.parentID = parent.spanID
+	} else {
+		span.traceID = randomHex(16)
+	}
+
+	return context.WithValue(ctx, spanKey, span), span
+}
+
+// SetAttr adds or replaces an attribute on the span
+func (s *Span) SetAttr(key, value string) {
+	if s == nil {
+		return
+	}
+	s.exporter.mu.Lock()
+	defer s.exporter.mu.Unlock()
+	s.attrs[key] = value
+}
+
+// End finishes the span and queues it for export
+// A non-nil err marks the span as failed
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.exporter.mu.Lock()
+	defer s.exporter.mu.Unlock()
+
+	if s.ended {
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	s.err = err
+	s.exporter.spans = append(s.exporter.spans, s)
+}
+
+// logRecord is a single OTLP log record
+type logRecord struct {
+	timestamp time.Time
+	body      string
+	attrs     map[string]string
+}
+
+// RecordUsage queues a usage event as an OTLP log record
+func (e *Exporter) RecordUsage(event stats.UsageEvent) {
+	if !e.Enabled() {
+		return
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, event.Timestamp)
+	if err != nil {
+		timestamp = time.Now()
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.records = append(e.records, logRecord{
+		timestamp: timestamp,
+		body:      "asset.usage",
+		attrs: map[string]string{
+			"sx.asset.name":    event.AssetName,
+			"sx.asset.version": event.AssetVersion,
+			"sx.asset.type":    event.AssetType,
+		},
+	})
+}
+
+// Flush sends all buffered spans and log records to the collector
+// Export failures are logged and returned but never retried; telemetry is best-effort
+func (e *Exporter) Flush(ctx context.Context) error {
+	if !e.Enabled() {
+		return nil
+	}
+
+	e.mu.Lock()
+	spans := e.spans
+	records := e.records
+	e.spans = nil
+	e.records = nil
+	e.mu.Unlock()
+
+	log := logger.Get()
+
+	if len(spans) > 0 {
+		if err := e.client.exportSpans(ctx, spans); err != nil {
+			log.Warn("failed to export spans", "endpoint", e.endpoint, "error", err)
+			return err
+		}
+	}
+
+	if len(records) > 0 {
+		if err := e.client.exportLogs(ctx, records); err != nil {
+			log.Warn("failed to export log records", "endpoint", e.endpoint, "error", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pairsToMap converts a flat key/value list into a map, ignoring a trailing odd key
+func pairsToMap(pairs []string) map[string]string {
+	attrs := make(map[string]string, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		attrs[pairs[i]] = pairs[i+1]
+	}
+	return attrs
+}
+
+// randomHex returns n random bytes hex-encoded, as used for trace and span IDs
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,189 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/stats"
+)
+
+// testCollector records OTLP payloads posted to it
+type testCollector struct {
+	mu       sync.Mutex
+	traces   []otlpTracesRequest
+	logs     []otlpLogsRequest
+	lastAuth string
+}
+
+func newTestCollector(t *testing.T) (*testCollector, *httptest.Server) {
+	c := &testCollector{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read body: %v", err)
+			return
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.lastAuth = r.Header.Get("Authorization")
+
+		switch r.URL.Path {
+		case "/v1/traces":
+			var req otlpTracesRequest
+			if err := json.Unmarshal(body, &req); err != nil {
+				t.Errorf("invalid traces payload: %v", err)
+			}
+			c.traces = append(c.traces, req)
+		case "/v1/logs":
+			var req otlpLogsRequest
+			if err := json.Unmarshal(body, &req); err != nil {
+				t.Errorf("invalid logs payload: %v", err)
+			}
+			c.logs = append(c.logs, req)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return c, server
+}
+
+func TestDisabledExporterIsNoop(t *testing.T) {
+	e := New("", nil)
+	if e.Enabled() {
+		t.Fatal("exporter without endpoint should be disabled")
+	}
+
+	ctx, span := e.StartSpan(context.Background(), "sx.install")
+	if span != nil {
+		t.Error("disabled exporter should not create spans")
+	}
+	span.SetAttr("k", "v")
+	span.End(nil)
+	e.RecordUsage(stats.UsageEvent{AssetName: "x"})
+
+	if err := e.Flush(ctx); err != nil {
+		t.Errorf("Flush() on disabled exporter returned error: %v", err)
+	}
+}
+
+func TestExportSpans(t *testing.T) {
+	collector, server := newTestCollector(t)
+	e := New(server.URL, map[string]string{"Authorization": "Bearer test"})
+
+	ctx, root := e.StartSpan(context.Background(), "sx.install", "sx.vault.type", "git")
+	_, child := e.StartSpan(ctx, "sx.install.lock_fetch")
+	child.End(errors.New("boom"))
+	root.End(nil)
+
+	if err := e.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error: %v", err)
+	}
+
+	if len(collector.traces) != 1 {
+		t.Fatalf("expected 1 traces request, got %d", len(collector.traces))
+	}
+	if collector.lastAuth != "Bearer test" {
+		t.Errorf("expected custom header to be sent, got %q", collector.lastAuth)
+	}
+
+	spans := collector.traces[0].ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	byName := make(map[string]otlpSpan)
+	for _, s := range spans {
+		byName[s.Name] = s
+	}
+
+	rootSpan, lockSpan := byName["sx.install"], byName["sx.install.lock_fetch"]
+	if lockSpan.TraceID != rootSpan.TraceID {
+		t.Error("child span should share the root trace ID")
+	}
+	if lockSpan.ParentSpanID != rootSpan.SpanID {
+		t.Errorf("child parent = %q, want %q", lockSpan.ParentSpanID, rootSpan.SpanID)
+	}
+	if lockSpan.Status.Code != statusCodeError || lockSpan.Status.Message != "boom" {
+		t.Errorf("unexpected child status: %+v", lockSpan.Status)
+	}
+	if rootSpan.Status.Code != statusCodeOK {
+		t.Errorf("unexpected root status: %+v", rootSpan.Status)
+	}
+	if len(rootSpan.Attributes) != 1 || rootSpan.Attributes[0].Key != "sx.vault.type" {
+		t.Errorf("unexpected root attributes: %+v", rootSpan.Attributes)
+	}
+
+	// Buffer is drained after a flush
+	if err := e.Flush(context.Background()); err != nil {
+		t.Fatalf("second Flush() error: %v", err)
+	}
+	if len(collector.traces) != 1 {
+		t.Errorf("expected no additional export, got %d requests", len(collector.traces))
+	}
+}
+
+func TestRecordUsage(t *testing.T) {
+	collector, server := newTestCollector(t)
+	e := New(server.URL, nil)
+
+	e.RecordUsage(stats.UsageEvent{
+		AssetName:    "my-skill",
+		AssetVersion: "2",
+		AssetType:    "skill",
+		Timestamp:    "2025-01-02T03:04:05Z",
+	})
+
+	if err := e.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error: %v", err)
+	}
+
+	if len(collector.logs) != 1 {
+		t.Fatalf("expected 1 logs request, got %d", len(collector.logs))
+	}
+
+	records := collector.logs[0].ResourceLogs[0].ScopeLogs[0].LogRecords
+	if len(records) != 1 {
+		t.Fatalf("expected 1 log record, got %d", len(records))
+	}
+
+	record := records[0]
+	if record.Body.StringValue != "asset.usage" {
+		t.Errorf("body = %q, want asset.usage", record.Body.StringValue)
+	}
+	if record.TimeUnixNano != "1735787045000000000" {
+		t.Errorf("timeUnixNano = %q", record.TimeUnixNano)
+	}
+
+	attrs := make(map[string]string)
+	for _, kv := range record.Attributes {
+		attrs[kv.Key] = kv.Value.StringValue
+	}
+	if attrs["sx.asset.name"] != "my-skill" || attrs["sx.asset.version"] != "2" || attrs["sx.asset.type"] != "skill" {
+		t.Errorf("unexpected attributes: %v", attrs)
+	}
+}
+
+func TestFlushReportsCollectorErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	e := New(server.URL, nil)
+	_, span := e.StartSpan(context.Background(), "sx.install")
+	span.End(nil)
+
+	if err := e.Flush(context.Background()); err == nil {
+		t.Error("expected error from failing collector")
+	}
+}
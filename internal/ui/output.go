@@ -325,6 +325,14 @@ func (o *Output) ErrorText(text string) string {
 	return o.theme.Styles().Error.Render(text)
 }
 
+// WarningText returns warning-styled text.
+func (o *Output) WarningText(text string) string {
+	if o.noTTY {
+		return text
+	}
+	return o.theme.Styles().Warning.Render(text)
+}
+
 // Theme returns the current theme.
 func (o *Output) Theme() theme.Theme {
 	return o.theme
@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
+// FileRefPattern matches @filename or @path/to/file references in prompt content
+var FileRefPattern = regexp.MustCompile(`@([a-zA-Z0-9_\-./]+\.[a-zA-Z0-9]+)`)
+
 // ExpandTilde expands a tilde (~) at the beginning of a path to the user's home directory
 func ExpandTilde(path string) (string, error) {
 	if !strings.HasPrefix(path, "~") {
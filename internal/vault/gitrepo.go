@@ -5,8 +5,10 @@ import (
 	"bytes"
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -38,6 +40,12 @@ const (
 	// readmeTemplateVersion is the current version of the README.md template
 	// Increment this when making changes to the template
 	readmeTemplateVersion = "1"
+
+	// maxPushAttempts bounds retries when a push races another writer
+	maxPushAttempts = 5
+
+	// pushRetryDelay is the base delay between push attempts, doubled each retry
+	pushRetryDelay = 200 * time.Millisecond
 )
 
 // GitVault implements Vault for Git vaults
@@ -48,7 +56,9 @@ type GitVault struct {
 	httpHandler *HTTPSourceHandler
 	pathHandler *PathSourceHandler
 	gitHandler  *GitSourceHandler
-	hasSynced   bool // Track if we've synced in this CLI execution
+	hasSynced   bool      // Track if we've synced in this CLI execution
+	propose     bool      // Push writes to a proposal branch instead of the default branch
+	proposal    *Proposal // Proposal branch created in this CLI execution
 }
 
 // NewGitVault creates a new Git repository
@@ -178,26 +188,30 @@ func (g *GitVault) AddAsset(ctx context.Context, asset *lockfile.Asset, zipData
 		return fmt.Errorf("failed to clone/update repository: %w", err)
 	}
 
-	// Create assets directory structure: assets/{name}/{version}/
-	assetDir := filepath.Join(g.repoPath, "assets", asset.Name, asset.Version)
-	if err := os.MkdirAll(assetDir, 0755); err != nil {
-		return fmt.Errorf("failed to create asset directory: %w", err)
-	}
+	apply := func() error {
+		// Create assets directory structure: assets/{name}/{version}/
+		assetDir := filepath.Join(g.repoPath, "assets", asset.Name, asset.Version)
+		if err := os.MkdirAll(assetDir, 0755); err != nil {
+			return fmt.Errorf("failed to create asset directory: %w", err)
+		}
 
-	// For Git repositories, store assets exploded (not as zip)
-	// This makes them easier to browse and diff in Git
-	if err := extractZipToDir(zipData, assetDir); err != nil {
-		return fmt.Errorf("failed to extract zip to directory: %w", err)
-	}
+		// For Git repositories, store assets exploded (not as zip)
+		// This makes them easier to browse and diff in Git
+		if err := extractZipToDir(zipData, assetDir); err != nil {
+			return fmt.Errorf("failed to extract zip to directory: %w", err)
+		}
 
-	// Update list.txt with this version
-	listPath := filepath.Join(g.repoPath, "assets", asset.Name, "list.txt")
-	if err := g.updateVersionList(listPath, asset.Version); err != nil {
-		return fmt.Errorf("failed to update version list: %w", err)
+		// Update list.txt with this version
+		listPath := filepath.Join(g.repoPath, "assets", asset.Name, "list.txt")
+		if err := g.updateVersionList(listPath, asset.Version); err != nil {
+			return fmt.Errorf("failed to update version list: %w", err)
+		}
+		return nil
 	}
 
 	// Commit and push the asset to the repository
-	if err := g.commitAndPush(ctx, asset); err != nil {
+	message := fmt.Sprintf("Add %s %s", asset.Name, asset.Version)
+	if err := g.publish(ctx, asset.Name, asset.Version, message, apply); err != nil {
 		return fmt.Errorf("failed to commit and push asset: %w", err)
 	}
 
@@ -207,16 +221,26 @@ func (g *GitVault) AddAsset(ctx context.Context, asset *lockfile.Asset, zipData
 	return nil
 }
 
+// ReadFile reads a file from the default branch of the vault (e.g. a registry index)
+func (g *GitVault) ReadFile(ctx context.Context, relPath string) ([]byte, error) {
+	fileLock, err := g.acquireFileLock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer func() { _ = fileLock.Unlock() }()
+
+	if err := g.cloneOrUpdate(ctx); err != nil {
+		return nil, fmt.Errorf("failed to clone/update repository: %w", err)
+	}
+
+	return readRepoFile(g.repoPath, relPath)
+}
+
 // GetLockFilePath returns the path to the lock file in the git repository
 func (g *GitVault) GetLockFilePath() string {
 	return filepath.Join(g.repoPath, constants.SkillLockFile)
 }
 
-// CommitAndPush commits all changes and pushes to remote
-func (g *GitVault) CommitAndPush(ctx context.Context, asset *lockfile.Asset) error {
-	return g.commitAndPush(ctx, asset)
-}
-
 // GetVersionList retrieves available versions for an asset from list.txt
 func (g *GitVault) GetVersionList(ctx context.Context, name string) ([]string, error) {
 	// Clone or update repository
@@ -290,7 +314,10 @@ func (g *GitVault) cloneOrUpdate(ctx context.Context) error {
 			return err
 		}
 	} else {
-		// Repository exists, pull updates
+		// Repository exists, pull updates on the default branch
+		if err := g.ensureDefaultBranch(ctx); err != nil {
+			return err
+		}
 		if err := g.pull(ctx); err != nil {
 			return err
 		}
@@ -520,14 +547,104 @@ func convertToRawURL(repoURL string) string {
 	return "https://raw.githubusercontent.com/YOUR_ORG/YOUR_REPO/main/install.sh"
 }
 
+// publish applies a change to the working tree, commits it and pushes it
+// If the push is rejected because someone else pushed first, the clone is reset to
+// the remote, the change is re-applied on top and the push retried with backoff. The
+// lock file is re-applied semantically: our edits are merged into the remote's copy
+// so concurrent changes to other assets (or other scopes) are kept.
+func (g *GitVault) publish(ctx context.Context, name, version, message string, apply func() error) error {
+	lockFilePath := g.GetLockFilePath()
+	baseLock, _ := os.ReadFile(lockFilePath)
+	if err := apply(); err != nil {
+		return err
+	}
+	ourLock, _ := os.ReadFile(lockFilePath)
+
+	for attempt := 1; ; attempt++ {
+		err := g.commitAndPush(ctx, name, version, message)
+		// Proposal branches are owned by one author, a reject there needs a human
+		if err == nil || !errors.Is(err, git.ErrPushRejected) || g.proposal != nil || attempt >= maxPushAttempts {
+			return err
+		}
+
+		log := logger.Get()
+		log.Info("push rejected, retrying on top of remote changes", "attempt", attempt, "asset", name)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pushBackoff(attempt)):
+		}
+
+		// Start over from the remote and replay the change
+		if err := g.gitClient.Fetch(ctx, g.repoPath); err != nil {
+			return err
+		}
+		if err := g.gitClient.ResetToUpstream(ctx, g.repoPath); err != nil {
+			return err
+		}
+		theirLock, _ := os.ReadFile(lockFilePath)
+		if err := apply(); err != nil {
+			return err
+		}
+		if !bytes.Equal(baseLock, ourLock) {
+			if err := mergeLockFile(lockFilePath, baseLock, ourLock, theirLock); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// pushBackoff returns the delay before retry attempt n, with jitter so racing
+// writers don't retry in lockstep
+func pushBackoff(attempt int) time.Duration {
+	delay := pushRetryDelay << (attempt - 1)
+	return delay + time.Duration(rand.Int63n(int64(pushRetryDelay)))
+}
+
+// mergeLockFile writes the three-way merge of our lock file change onto theirs
+func mergeLockFile(lockFilePath string, base, ours, theirs []byte) error {
+	parse := func(data []byte) (*lockfile.LockFile, error) {
+		if len(data) == 0 {
+			return &lockfile.LockFile{}, nil
+		}
+		return lockfile.Parse(data)
+	}
+
+	baseLF, err := parse(base)
+	if err != nil {
+		return fmt.Errorf("failed to parse lock file: %w", err)
+	}
+	ourLF, err := parse(ours)
+	if err != nil {
+		return fmt.Errorf("failed to parse lock file: %w", err)
+	}
+	theirLF, err := parse(theirs)
+	if err != nil {
+		return fmt.Errorf("failed to parse remote lock file: %w", err)
+	}
+
+	merged, conflicts := lockfile.Merge(baseLF, ourLF, theirLF)
+	if len(conflicts) > 0 {
+		log := logger.Get()
+		log.Warn("lock file entries changed concurrently in conflicting ways, keeping our changes", "assets", conflicts)
+	}
+	return lockfile.Write(merged, lockFilePath)
+}
+
 // commitAndPush commits and pushes changes
-func (g *GitVault) commitAndPush(ctx context.Context, asset *lockfile.Asset) error {
+func (g *GitVault) commitAndPush(ctx context.Context, name, version, message string) error {
 	// Ensure install.sh and README.md exist before committing
 	if err := g.ensureInstallScript(ctx); err != nil {
 		// Log warning but continue - these files are convenience features
 		fmt.Fprintf(os.Stderr, "Warning: could not create repository files: %v\n", err)
 	}
 
+	// Move to the proposal branch if review is required
+	if err := g.beginChange(ctx, name, version); err != nil {
+		return err
+	}
+
 	// Add all changes
 	if err := g.gitClient.Add(ctx, g.repoPath, "."); err != nil {
 		return err
@@ -545,13 +662,12 @@ func (g *GitVault) commitAndPush(ctx context.Context, asset *lockfile.Asset) err
 	}
 
 	// Commit with message
-	commitMsg := fmt.Sprintf("Add %s %s", asset.Name, asset.Version)
-	if err := g.gitClient.Commit(ctx, g.repoPath, commitMsg); err != nil {
+	if err := g.gitClient.Commit(ctx, g.repoPath, message); err != nil {
 		return err
 	}
 
 	// Push
-	if err := g.gitClient.Push(ctx, g.repoPath); err != nil {
+	if err := g.push(ctx); err != nil {
 		return err
 	}
 
@@ -688,13 +804,16 @@ func (g *GitVault) SetInstallations(ctx context.Context, asset *lockfile.Asset)
 	}
 
 	// Update lock file with asset and scopes
-	lockFilePath := g.GetLockFilePath()
-	if err := lockfile.AddOrUpdateAsset(lockFilePath, asset); err != nil {
-		return fmt.Errorf("failed to update lock file: %w", err)
+	apply := func() error {
+		if err := lockfile.AddOrUpdateAsset(g.GetLockFilePath(), asset); err != nil {
+			return fmt.Errorf("failed to update lock file: %w", err)
+		}
+		return nil
 	}
 
 	// Commit and push changes
-	if err := g.commitAndPush(ctx, asset); err != nil {
+	message := fmt.Sprintf("Add %s %s", asset.Name, asset.Version)
+	if err := g.publish(ctx, asset.Name, asset.Version, message, apply); err != nil {
 		return fmt.Errorf("failed to commit and push: %w", err)
 	}
 
@@ -716,21 +835,16 @@ func (g *GitVault) RemoveAsset(ctx context.Context, assetName, version string) e
 	}
 
 	// Remove from lock file
-	if err := lockfile.RemoveAsset(g.GetLockFilePath(), assetName, version); err != nil {
-		return fmt.Errorf("failed to remove asset from lock file: %w", err)
-	}
-
-	// Add, commit and push
-	if err := g.gitClient.Add(ctx, g.repoPath, constants.SkillLockFile); err != nil {
-		return fmt.Errorf("failed to stage lock file: %w", err)
-	}
-
-	commitMsg := fmt.Sprintf("Remove %s@%s", assetName, version)
-	if err := g.gitClient.Commit(ctx, g.repoPath, commitMsg); err != nil {
-		return fmt.Errorf("failed to commit removal: %w", err)
+	apply := func() error {
+		if err := lockfile.RemoveAsset(g.GetLockFilePath(), assetName, version); err != nil {
+			return fmt.Errorf("failed to remove asset from lock file: %w", err)
+		}
+		return nil
 	}
 
-	if err := g.gitClient.Push(ctx, g.repoPath); err != nil {
+	// Commit and push, on a proposal branch if review is required
+	message := fmt.Sprintf("Remove %s@%s", assetName, version)
+	if err := g.publish(ctx, assetName, "remove", message, apply); err != nil {
 		return fmt.Errorf("failed to push removal: %w", err)
 	}
 
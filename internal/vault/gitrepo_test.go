@@ -1,8 +1,14 @@
 package vault
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/lockfile"
 )
 
 func TestExtractTemplateVersion(t *testing.T) {
@@ -230,3 +236,61 @@ func TestConvertToRawURL(t *testing.T) {
 		})
 	}
 }
+
+func TestGitVaultRetriesRejectedPush(t *testing.T) {
+	origin, seed := setupOriginRepo(t)
+	ctx := context.Background()
+
+	mine := &lockfile.Asset{
+		Name:       "mine",
+		Version:    "1.0.0",
+		Type:       asset.TypeSkill,
+		SourcePath: &lockfile.SourcePath{Path: "./assets/mine/1.0.0"},
+	}
+
+	vault, err := NewGitVault("file://" + origin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Sync now so the clone is stale by the time we push
+	if _, err := vault.GetVersionList(ctx, "mine"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A teammate publishes in the meantime
+	theirLock := `lock-version = "1.0"
+version = "1"
+created-by = "test"
+
+[[assets]]
+name = "theirs"
+version = "2.0.0"
+type = "skill"
+
+[assets.source-path]
+path = "./assets/theirs/2.0.0"
+`
+	if err := os.WriteFile(filepath.Join(seed, "sx.lock"), []byte(theirLock), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, seed, "add", ".")
+	runGit(t, seed, "commit", "--quiet", "-m", "Add theirs 2.0.0")
+	runGit(t, seed, "push", "--quiet", "origin", "HEAD:main")
+
+	if err := vault.SetInstallations(ctx, mine); err != nil {
+		t.Fatalf("SetInstallations() should retry a rejected push, got: %v", err)
+	}
+
+	data := runGit(t, origin, "show", "main:sx.lock")
+	lf, err := lockfile.Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, a := range lf.Assets {
+		names = append(names, a.Name)
+	}
+	if len(names) != 2 || !strings.Contains(strings.Join(names, ","), "theirs") || !strings.Contains(strings.Join(names, ","), "mine") {
+		t.Errorf("expected both writers' assets on main, got %v", names)
+	}
+}
@@ -106,6 +106,11 @@ func (p *PathVault) GetAsset(ctx context.Context, asset *lockfile.Asset) ([]byte
 	}
 }
 
+// ReadFile reads a file from the vault directory (e.g. a registry index)
+func (p *PathVault) ReadFile(ctx context.Context, relPath string) ([]byte, error) {
+	return readRepoFile(p.repoPath, relPath)
+}
+
 // AddAsset adds an asset to the local repository
 // Follows the same pattern as GitRepository: exploded storage + list.txt
 func (p *PathVault) AddAsset(ctx context.Context, asset *lockfile.Asset, zipData []byte) error {
@@ -0,0 +1,249 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	// ProposalBranchPrefix namespaces the branches sx pushes for review
+	ProposalBranchPrefix = "sx/propose/"
+
+	// vaultSettingsFile holds vault-wide settings committed to the vault repository
+	vaultSettingsFile = "vault.toml"
+)
+
+// vaultSettings are the settings read from vault.toml in a git vault
+type vaultSettings struct {
+	// ReviewRequired makes every publish go through a proposal branch
+	ReviewRequired bool `toml:"review-required"`
+}
+
+// Proposal is a branch pushed for review instead of the vault's default branch
+// The asset goes live once the branch is merged.
+type Proposal struct {
+	Branch     string    `json:"branch"`
+	Asset      string    `json:"asset"`
+	Version    string    `json:"version"`
+	Author     string    `json:"author,omitempty"`
+	UpdatedAt  time.Time `json:"updatedAt,omitzero"`
+	CompareURL string    `json:"compareUrl,omitempty"`
+}
+
+// SetPropose makes subsequent writes go to a proposal branch instead of the default branch
+func (g *GitVault) SetPropose(propose bool) {
+	g.propose = propose
+}
+
+// Proposal returns the proposal created by writes in this execution, or nil if
+// changes were pushed to the default branch
+func (g *GitVault) Proposal() *Proposal {
+	return g.proposal
+}
+
+// ListProposals returns the proposal branches on the remote that are not merged yet
+func (g *GitVault) ListProposals(ctx context.Context) ([]Proposal, error) {
+	fileLock, err := g.acquireFileLock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	defer func() { _ = fileLock.Unlock() }()
+
+	if err := g.cloneOrUpdate(ctx); err != nil {
+		return nil, fmt.Errorf("failed to clone/update repository: %w", err)
+	}
+
+	// Pull doesn't drop branches deleted after merge, so prune explicitly
+	if err := g.gitClient.FetchPrune(ctx, g.repoPath); err != nil {
+		return nil, err
+	}
+
+	defaultBranch, err := g.gitClient.DefaultBranch(ctx, g.repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine default branch: %w", err)
+	}
+
+	branches, err := g.gitClient.ListRemoteBranches(ctx, g.repoPath, ProposalBranchPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var proposals []Proposal
+	for _, branch := range branches {
+		// Merged branches that weren't deleted are no longer pending
+		merged, err := g.gitClient.IsAncestor(ctx, g.repoPath, "origin/"+branch.Name, "origin/"+defaultBranch)
+		if err != nil {
+			return nil, err
+		}
+		if merged {
+			continue
+		}
+
+		name, version := parseProposalBranch(branch.Name)
+		proposals = append(proposals, Proposal{
+			Branch:     branch.Name,
+			Asset:      name,
+			Version:    version,
+			Author:     branch.Author,
+			UpdatedAt:  branch.CommitDate,
+			CompareURL: CompareURL(g.repoURL, defaultBranch, branch.Name),
+		})
+	}
+
+	return proposals, nil
+}
+
+// proposing reports whether writes should go to a proposal branch
+func (g *GitVault) proposing() bool {
+	if g.propose {
+		return true
+	}
+	settings, err := g.loadSettings()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring %s: %v\n", vaultSettingsFile, err)
+		return false
+	}
+	return settings.ReviewRequired
+}
+
+// loadSettings reads vault.toml from the repository root; a missing file means defaults
+func (g *GitVault) loadSettings() (*vaultSettings, error) {
+	settings := &vaultSettings{}
+	data, err := os.ReadFile(filepath.Join(g.repoPath, vaultSettingsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return settings, nil
+		}
+		return nil, err
+	}
+	if err := toml.Unmarshal(data, settings); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", vaultSettingsFile, err)
+	}
+	return settings, nil
+}
+
+// beginChange switches to the proposal branch for name/version when proposing
+// Uncommitted changes are carried over, so it can be called right before committing.
+// Later writes in the same execution reuse the branch.
+func (g *GitVault) beginChange(ctx context.Context, name, version string) error {
+	if g.proposal != nil || !g.proposing() {
+		return nil
+	}
+
+	defaultBranch, err := g.gitClient.GetCurrentBranch(ctx, g.repoPath)
+	if err != nil {
+		return err
+	}
+
+	branch := ProposalBranchPrefix + name + "/" + version
+	if err := g.gitClient.CheckoutNewBranch(ctx, g.repoPath, branch); err != nil {
+		return fmt.Errorf("failed to create proposal branch: %w", err)
+	}
+
+	g.proposal = &Proposal{
+		Branch:     branch,
+		Asset:      name,
+		Version:    version,
+		CompareURL: CompareURL(g.repoURL, defaultBranch, branch),
+	}
+	return nil
+}
+
+// push pushes the current branch, either the proposal branch or the default branch
+func (g *GitVault) push(ctx context.Context) error {
+	if g.proposal != nil {
+		return g.gitClient.PushBranch(ctx, g.repoPath, g.proposal.Branch)
+	}
+	return g.gitClient.Push(ctx, g.repoPath)
+}
+
+// ensureDefaultBranch returns the clone to the default branch if a previous
+// execution left it on a proposal branch
+func (g *GitVault) ensureDefaultBranch(ctx context.Context) error {
+	current, err := g.gitClient.GetCurrentBranch(ctx, g.repoPath)
+	if err != nil || !strings.HasPrefix(current, ProposalBranchPrefix) {
+		return err
+	}
+
+	defaultBranch, err := g.gitClient.DefaultBranch(ctx, g.repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to determine default branch: %w", err)
+	}
+	return g.gitClient.Checkout(ctx, g.repoPath, defaultBranch)
+}
+
+// parseProposalBranch extracts the asset name and version from a proposal branch name
+func parseProposalBranch(branch string) (name, version string) {
+	rest := strings.TrimPrefix(branch, ProposalBranchPrefix)
+	if i := strings.LastIndex(rest, "/"); i >= 0 {
+		return rest[:i], rest[i+1:]
+	}
+	return rest, ""
+}
+
+// CompareURL returns the web URL for opening a pull/merge request from branch into base
+// Returns an empty string for hosts it doesn't recognize.
+func CompareURL(repoURL, base, branch string) string {
+	webURL := repoWebURL(repoURL)
+	if webURL == "" {
+		return ""
+	}
+
+	switch host := hostOf(webURL); {
+	case strings.Contains(host, "github"):
+		return fmt.Sprintf("%s/compare/%s...%s?expand=1", webURL, base, branch)
+	case strings.Contains(host, "gitlab"):
+		q := url.Values{}
+		q.Set("merge_request[source_branch]", branch)
+		q.Set("merge_request[target_branch]", base)
+		return webURL + "/-/merge_requests/new?" + q.Encode()
+	case strings.Contains(host, "bitbucket"):
+		q := url.Values{}
+		q.Set("source", branch)
+		q.Set("dest", base)
+		return webURL + "/pull-requests/new?" + q.Encode()
+	default:
+		return ""
+	}
+}
+
+// repoWebURL converts an HTTPS or SSH clone URL to the repository's https web URL
+func repoWebURL(repoURL string) string {
+	trimmed := strings.TrimSuffix(strings.TrimSpace(repoURL), ".git")
+
+	// SCP-like SSH syntax: git@host:owner/repo
+	if !strings.Contains(trimmed, "://") {
+		at := strings.Index(trimmed, "@")
+		colon := strings.Index(trimmed, ":")
+		if colon <= at+1 {
+			return ""
+		}
+		return "https://" + trimmed[at+1:colon] + "/" + strings.TrimPrefix(trimmed[colon+1:], "/")
+	}
+
+	u, err := url.Parse(trimmed)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	switch u.Scheme {
+	case "https", "http", "ssh", "git+ssh":
+		return "https://" + u.Hostname() + "/" + strings.TrimPrefix(u.Path, "/")
+	default:
+		return ""
+	}
+}
+
+func hostOf(webURL string) string {
+	u, err := url.Parse(webURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
@@ -0,0 +1,166 @@
+package vault
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/asset"
+	"github.com/sleuth-io/sx/internal/lockfile"
+	"github.com/sleuth-io/sx/internal/utils"
+)
+
+func TestCompareURL(t *testing.T) {
+	tests := []struct {
+		repoURL  string
+		expected string
+	}{
+		{"https://github.com/acme/skills.git", "https://github.com/acme/skills/compare/main...sx/propose/foo/1.0.0?expand=1"},
+		{"git@github.com:acme/skills.git", "https://github.com/acme/skills/compare/main...sx/propose/foo/1.0.0?expand=1"},
+		{"ssh://git@gitlab.com/acme/team/skills.git", "https://gitlab.com/acme/team/skills/-/merge_requests/new?merge_request%5Bsource_branch%5D=sx%2Fpropose%2Ffoo%2F1.0.0&merge_request%5Btarget_branch%5D=main"},
+		{"git@bitbucket.org:acme/skills.git", "https://bitbucket.org/acme/skills/pull-requests/new?dest=main&source=sx%2Fpropose%2Ffoo%2F1.0.0"},
+		{"https://git.example.com/acme/skills.git", ""},
+		{"file:///tmp/skills", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repoURL, func(t *testing.T) {
+			if got := CompareURL(tt.repoURL, "main", "sx/propose/foo/1.0.0"); got != tt.expected {
+				t.Errorf("CompareURL() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGitVaultProposeFlow(t *testing.T) {
+	origin, seed := setupOriginRepo(t)
+
+	ctx := context.Background()
+	zipData, err := utils.CreateZipFromContent("SKILL.md", []byte("# proposed"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newAsset := func(name string) *lockfile.Asset {
+		return &lockfile.Asset{
+			Name:       name,
+			Version:    "1.0.0",
+			Type:       asset.TypeSkill,
+			SourcePath: &lockfile.SourcePath{Path: "./assets/" + name + "/1.0.0"},
+		}
+	}
+
+	vault, err := NewGitVault("file://" + origin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vault.SetPropose(true)
+	proposed := newAsset("proposed-skill")
+	if err := vault.AddAsset(ctx, proposed, zipData); err != nil {
+		t.Fatalf("AddAsset() error: %v", err)
+	}
+	if err := vault.SetInstallations(ctx, proposed); err != nil {
+		t.Fatalf("SetInstallations() error: %v", err)
+	}
+
+	proposal := vault.Proposal()
+	if proposal == nil || proposal.Branch != "sx/propose/proposed-skill/1.0.0" {
+		t.Fatalf("unexpected proposal: %+v", proposal)
+	}
+	if out := runGit(t, origin, "ls-tree", "-r", "--name-only", "main"); strings.Contains(out, "proposed-skill") {
+		t.Error("proposed asset must not reach the default branch before merge")
+	}
+	if out := runGit(t, origin, "ls-tree", "-r", "--name-only", proposal.Branch); !strings.Contains(out, "assets/proposed-skill/1.0.0/SKILL.md") {
+		t.Errorf("proposal branch is missing the asset:\n%s", out)
+	}
+
+	// A later run starts from the default branch and publishes directly
+	direct, err := NewGitVault("file://" + origin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending, err := direct.ListProposals(ctx)
+	if err != nil {
+		t.Fatalf("ListProposals() error: %v", err)
+	}
+	if len(pending) != 1 || pending[0].Asset != "proposed-skill" || pending[0].Version != "1.0.0" {
+		t.Fatalf("unexpected pending proposals: %+v", pending)
+	}
+	if err := direct.AddAsset(ctx, newAsset("direct-skill"), zipData); err != nil {
+		t.Fatalf("AddAsset() error: %v", err)
+	}
+	if direct.Proposal() != nil {
+		t.Error("expected a direct publish without --propose")
+	}
+	if out := runGit(t, origin, "ls-tree", "-r", "--name-only", "main"); !strings.Contains(out, "direct-skill") || strings.Contains(out, "proposed-skill") {
+		t.Errorf("unexpected default branch contents:\n%s", out)
+	}
+
+	// Merging the branch takes it off the pending list
+	runGit(t, seed, "fetch", "--quiet", "origin")
+	runGit(t, seed, "checkout", "--quiet", "-B", "main", "origin/main")
+	runGit(t, seed, "merge", "--quiet", "--no-edit", "origin/"+proposal.Branch)
+	runGit(t, seed, "push", "--quiet", "origin", "main")
+
+	merged, err := NewGitVault("file://" + origin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending, err := merged.ListProposals(ctx); err != nil || len(pending) != 0 {
+		t.Errorf("expected no pending proposals after merge, got %+v (err=%v)", pending, err)
+	}
+}
+
+func TestGitVaultReviewRequiredSetting(t *testing.T) {
+	tempDir := t.TempDir()
+	vault := &GitVault{repoPath: tempDir}
+	if vault.proposing() {
+		t.Error("expected direct publishing without vault.toml")
+	}
+
+	if err := os.WriteFile(filepath.Join(tempDir, vaultSettingsFile), []byte("review-required = true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !vault.proposing() {
+		t.Error("expected review-required = true to force proposals")
+	}
+}
+
+// setupOriginRepo creates a bare origin with one commit on main and a seed clone
+// for pushing changes as another writer
+func setupOriginRepo(t *testing.T) (origin, seed string) {
+	t.Helper()
+	tempDir := t.TempDir()
+	t.Setenv("SX_CACHE_DIR", filepath.Join(tempDir, "cache"))
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "Test")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "test@test.com")
+	}
+
+	origin = filepath.Join(tempDir, "origin.git")
+	seed = filepath.Join(tempDir, "seed")
+	runGit(t, tempDir, "init", "--quiet", "--bare", "--initial-branch=main", origin)
+	runGit(t, tempDir, "clone", "--quiet", origin, seed)
+	if err := os.WriteFile(filepath.Join(seed, "README.md"), []byte("vault\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, seed, "add", ".")
+	runGit(t, seed, "commit", "--quiet", "-m", "init")
+	runGit(t, seed, "push", "--quiet", "origin", "HEAD:main")
+	return origin, seed
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return string(output)
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/sleuth-io/sx/internal/asset"
@@ -15,6 +16,7 @@ import (
 	"github.com/sleuth-io/sx/internal/cache"
 	"github.com/sleuth-io/sx/internal/git"
 	"github.com/sleuth-io/sx/internal/lockfile"
+	"github.com/sleuth-io/sx/internal/logger"
 	"github.com/sleuth-io/sx/internal/metadata"
 	"github.com/sleuth-io/sx/internal/version"
 )
@@ -584,6 +586,45 @@ func (s *SleuthVault) executeGraphQLQuery(ctx context.Context, query string, var
 	return nil
 }
 
+// inputFields returns the fields of a GraphQL input type, by introspection
+func (s *SleuthVault) inputFields(ctx context.Context, typeName string) (map[string]bool, error) {
+	query := `query InputFields($name: String!) {
+		__type(name: $name) {
+			inputFields {
+				name
+			}
+		}
+	}`
+
+	var gqlResp struct {
+		Data struct {
+			Type *struct {
+				InputFields []struct {
+					Name string `json:"name"`
+				} `json:"inputFields"`
+			} `json:"__type"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := s.executeGraphQLQuery(ctx, query, map[string]interface{}{"name": typeName}, &gqlResp); err != nil {
+		return nil, err
+	}
+	if len(gqlResp.Errors) > 0 {
+		return nil, fmt.Errorf("GraphQL error: %s", gqlResp.Errors[0].Message)
+	}
+	if gqlResp.Data.Type == nil {
+		return nil, fmt.Errorf("type %s not found", typeName)
+	}
+
+	fields := make(map[string]bool, len(gqlResp.Data.Type.InputFields))
+	for _, f := range gqlResp.Data.Type.InputFields {
+		fields[f.Name] = true
+	}
+	return fields, nil
+}
+
 // SetInstallations sets the installation scopes for an asset using GraphQL mutation
 func (s *SleuthVault) SetInstallations(ctx context.Context, asset *lockfile.Asset) error {
 	mutation := `mutation SetAssetInstallations($input: SetAssetInstallationsInput!) {
@@ -614,18 +655,50 @@ func (s *SleuthVault) SetInstallations(ctx context.Context, asset *lockfile.Asse
 			if len(scope.Paths) > 0 {
 				repo["paths"] = scope.Paths
 			}
+			if len(scope.Branches) > 0 {
+				repo["branches"] = scope.Branches
+			}
 			repositories = append(repositories, repo)
 		}
 	}
 
-	variables := map[string]interface{}{
-		"input": map[string]interface{}{
-			"assetName":    asset.Name,
-			"assetVersion": asset.Version,
-			"repositories": repositories,
-		},
+	input := map[string]interface{}{
+		"assetName":    asset.Name,
+		"assetVersion": asset.Version,
+		"repositories": repositories,
 	}
 
+	// Newer settings are only sent when set, and only if the server knows them,
+	// since a GraphQL server rejects the whole mutation over an unknown field
+	optional := map[string]interface{}{}
+	if len(asset.Audience) > 0 {
+		optional["audience"] = asset.Audience
+	}
+	if asset.MissingRequires != "" {
+		optional["missingRequires"] = asset.MissingRequires
+	}
+	if asset.AutoScope != "" {
+		optional["autoScope"] = asset.AutoScope
+	}
+	if len(optional) > 0 {
+		supported, err := s.inputFields(ctx, "SetAssetInstallationsInput")
+		for _, key := range []string{"audience", "missingRequires", "autoScope"} {
+			value, ok := optional[key]
+			if !ok {
+				continue
+			}
+			// Send it anyway if the server doesn't allow introspection
+			if err != nil || supported[key] {
+				input[key] = value
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "Warning: the server doesn't support %s yet; it was not saved for %s\n", key, asset.Name)
+			logger.Get().Warn("server does not support installation field", "field", key, "asset", asset.Name)
+		}
+	}
+
+	variables := map[string]interface{}{"input": input}
+
 	var gqlResp struct {
 		Data struct {
 			SetAssetInstallations struct {
@@ -0,0 +1,60 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sleuth-io/sx/internal/lockfile"
+)
+
+func TestSetInstallationsOmitsFieldsTheServerLacks(t *testing.T) {
+	t.Setenv("SX_CACHE_DIR", t.TempDir())
+
+	var input map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+
+		// A server that knows audience but not the later fields
+		if strings.Contains(req.Query, "__type") {
+			_, _ = w.Write([]byte(`{"data":{"__type":{"inputFields":[
+				{"name":"assetName"},{"name":"assetVersion"},{"name":"repositories"},{"name":"audience"}]}}}`))
+			return
+		}
+		input, _ = req.Variables["input"].(map[string]interface{})
+		_, _ = w.Write([]byte(`{"data":{"setAssetInstallations":{"asset":{"name":"skill","latestVersion":"1.0.0"},"errors":[]}}}`))
+	}))
+	defer server.Close()
+
+	asset := &lockfile.Asset{
+		Name:      "skill",
+		Version:   "1.0.0",
+		Audience:  []string{"frontend"},
+		AutoScope: lockfile.AutoScopeLanguages,
+	}
+	if err := NewSleuthVault(server.URL, "token").SetInstallations(context.Background(), asset); err != nil {
+		t.Fatalf("SetInstallations() error: %v", err)
+	}
+
+	if input == nil {
+		t.Fatal("mutation was not sent")
+	}
+	if _, ok := input["audience"]; !ok {
+		t.Error("audience should be sent to a server that supports it")
+	}
+	if _, ok := input["autoScope"]; ok {
+		t.Error("autoScope should not be sent to a server that doesn't support it")
+	}
+	if _, ok := input["missingRequires"]; ok {
+		t.Error("unset fields should not be sent")
+	}
+}
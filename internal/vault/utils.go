@@ -1,6 +1,12 @@
 package vault
 
-import "bytes"
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
 
 // parseVersionList parses a newline-separated list of versions from bytes
 // This is the standard format for list.txt files across all repository types
@@ -14,3 +20,12 @@ func parseVersionList(data []byte) []string {
 	}
 	return versions
 }
+
+// readRepoFile reads a file relative to a vault root, refusing paths that escape it
+func readRepoFile(root, relPath string) ([]byte, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(relPath))
+	if filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("path %q is outside the vault", relPath)
+	}
+	return os.ReadFile(filepath.Join(root, cleaned))
+}